@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newBumpCmd() *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "bump [flags] <tag>",
+		Short: "Update the version strings of the configured files to match a tag.",
+		Long: "Bump updates the version strings in the files configured under `bump.files` (i.e - VERSION, " +
+			"package.json, pyproject.toml, and Helm Chart.yaml files) to match the provided tag, as part of a " +
+			"release-prep workflow.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			// Update the version files.
+			changed, err := lorekeeper.BumpVersionFiles(args[0], config.Bump)
+			for _, path := range changed {
+				log.Info("Bumped version", "path", path, "tag", args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "",
+		fmt.Sprintf("The path to the configuration file (default %q, if it exists).", lorekeeper.DefaultConfigPath),
+	)
+
+	return cmd
+}
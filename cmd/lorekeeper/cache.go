@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of the pull requests retrieved from the forge.",
+		Long: "Cache manages the on-disk cache of the pull requests retrieved from the forge, enabled with " +
+			"`cache.enabled`, so that long-lived runners don't accumulate stale data.",
+	}
+
+	cmd.AddCommand(
+		newCacheClearCmd(),
+		newCacheStatsCmd(),
+	)
+
+	return cmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	var (
+		configPath string
+		expired    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clear [flags]",
+		Short: "Remove the cached pull requests.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			// Remove the cached pull requests.
+			removed, err := lorekeeper.ClearCache(config.Cache, expired)
+			if err != nil {
+				return err
+			}
+			log.Info("Cleared the cache", "removed", removed, "expiredOnly", expired)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "",
+		fmt.Sprintf("The path to the configuration file (default %q, if it exists).", lorekeeper.DefaultConfigPath),
+	)
+	cmd.Flags().BoolVar(&expired, "expired", false,
+		"Only remove the cached pull requests older than the TTL.",
+	)
+
+	return cmd
+}
+
+func newCacheStatsCmd() *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "stats [flags]",
+		Short: "Output the number and size of the cached pull requests.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			// Output the statistics of the cache.
+			stats, err := lorekeeper.GetCacheStats(config.Cache)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Directory: %s\n", stats.Dir)
+			fmt.Fprintf(cmd.OutOrStdout(), "Entries:   %d (%d expired)\n", stats.Entries, stats.Expired)
+			fmt.Fprintf(cmd.OutOrStdout(), "Size:      %d bytes\n", stats.Size)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "",
+		fmt.Sprintf("The path to the configuration file (default %q, if it exists).", lorekeeper.DefaultConfigPath),
+	)
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+// defaultChangelogPath is the path of the changelog, unless another is
+// provided.
+const defaultChangelogPath = "CHANGELOG.md"
+
+func newCheckChangelogCmd(ctx context.Context) *cobra.Command {
+	var (
+		cliArgs Arguments
+		path    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check-changelog [flags]",
+		Short: "Check that the changelog has an entry for each unreleased change.",
+		Long: "Check-changelog fails unless the unreleased section of the changelog already has an entry for each " +
+			"of the changes merged since the latest release (or tag in the tag mode), leaving the changelog as it " +
+			"is, so it can be used as a required CI check. Use unreleased --changelog to update the section.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Translate the Mode string to a lorekeeper.mode.
+			mode, err := lorekeeper.GetModeByName(cliArgs.Mode)
+			if err != nil {
+				return err
+			}
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+
+			// Check the changelog is current.
+			if err := lorekeeper.CheckChangelog(ctx, path, cliArgs.options(
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)...); err != nil {
+				return err
+			}
+			log.Info("The changelog is up to date", "path", path)
+
+			return nil
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+	cmd.Flags().StringVar(&path, "path", defaultChangelogPath, "The path of the changelog.")
+
+	return cmd
+}
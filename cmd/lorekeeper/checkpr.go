@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+// reGitHubPullRequestRef matches the ref of a pull request that GitHub Actions
+// runs the workflows of pull requests on, i.e - `refs/pull/123/merge`.
+var reGitHubPullRequestRef = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+func newCheckPRCmd(ctx context.Context) *cobra.Command {
+	var cliArgs Arguments
+
+	cmd := &cobra.Command{
+		Use:   "check-pr [flags] [number]",
+		Short: "Check that a pull request will produce a release notes entry.",
+		Long: "Check-pr fails unless the pull request is assigned one of the configured categories (i.e - by a " +
+			"label), has a conventional commit title, or adds a fragment file when fragments are configured, so " +
+			"that every merge produces a sane entry. Pull requests excluded by the filter pass. It is intended to " +
+			"be run in the CI of each pull request, and reads the number of the pull request from GITHUB_REF in " +
+			"GitHub Actions if none is provided.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Read the number of the pull request, or detect it.
+			var number string
+			if len(args) > 0 {
+				number = args[0]
+			} else if matches := reGitHubPullRequestRef.FindStringSubmatch(os.Getenv("GITHUB_REF")); matches != nil {
+				number = matches[1]
+			} else {
+				return errors.New("a pull request number is required outside of the pull request workflows of GitHub Actions")
+			}
+			pullRequest, err := strconv.Atoi(number)
+			if err != nil || pullRequest < 1 {
+				return fmt.Errorf("invalid pull request number %q", number)
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+
+			signal, err := lorekeeper.CheckPullRequest(ctx, pullRequest, cliArgs.options(
+				lorekeeper.WithConfig(config),
+			)...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✓ #%d: %s\n", pullRequest, signal)
+
+			return nil
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+)
+
+// errTagNotConfirmed is returned when the detected tag is declined.
+var errTagNotConfirmed = errors.New("the detected tag was declined, provide one with --tag")
+
+// detectTag returns the most recent tag in the local repository, asking for it
+// to be confirmed when running interactively.
+func detectTag(ctx context.Context, in io.Reader, out io.Writer, opts ...lorekeeper.Option) (string, error) {
+	tagName, err := lorekeeper.DetectLatestTag(ctx, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// Only ask for confirmation when there's someone to answer it on the
+	// reader it is read from.
+	if !isTerminal(in) {
+		log.Info("Using the latest tag", "tag", tagName)
+		return tagName, nil
+	}
+
+	fmt.Fprintf(out, "Make the release notes for %s? [Y/n] ", tagName)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return tagName, nil
+	default:
+		return "", errTagNotConfirmed
+	}
+}
+
+// isTerminal reports whether the provided reader is a terminal, which it can
+// only be if it is a file.
+func isTerminal(in io.Reader) bool {
+	file, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newFetchCmd(ctx context.Context) *cobra.Command {
+	var cliArgs Arguments
+
+	cmd := &cobra.Command{
+		Use:   "fetch [flags]",
+		Short: "Fetch the published release notes for a tag.",
+		Long: "Fetch retrieves the published release notes for the provided tag (the body of the release in the " +
+			"release mode, or the annotation of the tag in the tag mode), parses them back into lorekeeper's " +
+			"structured model, and re-renders them in the provided format.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Translate the Mode string to a lorekeeper.mode.
+			mode, err := lorekeeper.GetModeByName(cliArgs.Mode)
+			if err != nil {
+				return err
+			}
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+
+			return lorekeeper.FetchReleaseNotes(ctx, cliArgs.TagName,
+				lorekeeper.WithWriter(cmd.OutOrStdout()),
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+	_ = cmd.MarkFlagRequired("tag")
+
+	return cmd
+}
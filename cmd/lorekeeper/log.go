@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/log"
 )
@@ -9,8 +12,19 @@ import (
 const (
 	// Default log level - Warn.
 	defaultLogLevel = log.WarnLevel
+
+	// The environment variable that sets the log level, when neither the
+	// `--log-level` nor `--verbose` flags are provided.
+	logLevelEnvVar = "LOREKEEPER_LOG_LEVEL"
 )
 
+// _logFormatters maps the names of the log formats to their log.Formatter.
+var _logFormatters = map[string]log.Formatter{
+	"text":   log.TextFormatter,
+	"json":   log.JSONFormatter,
+	"logfmt": log.LogfmtFormatter,
+}
+
 type _logLevels []log.Level
 
 // charmbracelet/log doesn't have an AllLevels list.
@@ -31,18 +45,16 @@ func initLogging() {
 	log.SetOutput(os.Stderr)
 }
 
-// logLevelsAbove returns all log.Level that are higher than the provided
-// log.Level.
-func logLevelsAbove(level log.Level) []log.Level {
+// logLevelsBelow returns all log.Level that are lower (more verbose) than the
+// provided log.Level, from the closest to the furthest.
+func logLevelsBelow(level log.Level) []log.Level {
 	var (
 		levelIdx = logLevelIndex(level)
 		levels   []log.Level
 	)
 
-	for idx, l := range _AllLogLevels {
-		if idx > levelIdx {
-			levels = append(levels, l)
-		}
+	for idx := levelIdx - 1; idx >= 0; idx-- {
+		levels = append(levels, _AllLogLevels[idx])
 	}
 
 	return levels
@@ -58,3 +70,38 @@ func logLevelIndex(level log.Level) int {
 	}
 	return -1
 }
+
+// parseLogLevel parses the provided log level name, i.e - "debug".
+func parseLogLevel(name string) (log.Level, error) {
+	level, err := log.ParseLevel(strings.ToLower(name))
+	if err != nil || logLevelIndex(level) < 0 {
+		return level, fmt.Errorf("invalid log level: expected one of debug, info, warn, error, fatal, got %q", name)
+	}
+	return level, nil
+}
+
+// setLogFormat sets the log.Formatter for the provided log format name.
+func setLogFormat(format string) error {
+	formatter, ok := _logFormatters[format]
+	if !ok {
+		return fmt.Errorf("invalid log format: expected one of %s, got %q", getLogFormatNamesString(), format)
+	}
+
+	log.SetFormatter(formatter)
+
+	// Timestamps are only useful to machines parsing the logs.
+	log.SetReportTimestamp(formatter != log.TextFormatter)
+
+	return nil
+}
+
+// getLogFormatNamesString returns the names of the log formats as a comma
+// separated string.
+func getLogFormatNamesString() string {
+	var names []string
+	for name := range _logFormatters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
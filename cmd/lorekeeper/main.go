@@ -3,6 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/charmbracelet/log"
 	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
@@ -15,19 +18,39 @@ const (
 )
 
 func main() {
-	// Create a new context.
-	ctx := context.Background()
+	// Create a new context, cancelled on an interrupt.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Initialise the logger.
 	initLogging()
 
+	// Initialise the tracing.
+	shutdownTracing, err := initTracing(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Execute the cobra.Command.
-	if err := newLorekeeperCmd(ctx).Execute(); err != nil {
+	var prof profiler
+	err = newLorekeeperCmd(ctx, &prof).Execute()
+
+	// Write the profiles, if requested, even when the command failed.
+	if err := prof.stop(); err != nil {
+		log.Warn("Failed to write the profiles", "err", err)
+	}
+
+	// Flush the spans before exiting, even once interrupted.
+	if err := shutdownTracing(context.WithoutCancel(ctx)); err != nil {
+		log.Warn("Failed to flush traces", "err", err)
+	}
+
+	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func newLorekeeperCmd(ctx context.Context) *cobra.Command {
+func newLorekeeperCmd(ctx context.Context, prof *profiler) *cobra.Command {
 	var cliArgs Arguments
 
 	cmd := &cobra.Command{
@@ -53,15 +76,35 @@ func newLorekeeperCmd(ctx context.Context) *cobra.Command {
 				return err
 			}
 
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+
+			// Name the release train by date, or offer the latest tag, if no
+			// tag was provided.
+			if cliArgs.TagName == "" {
+				var tagName string
+				if mode == lorekeeper.ModeTrain {
+					tagName, err = lorekeeper.TrainName(config)
+				} else {
+					tagName, err = detectTag(ctx, cmd.InOrStdin(), cmd.ErrOrStderr(), cliArgs.options()...)
+				}
+				if err != nil {
+					return err
+				}
+				cliArgs.TagName = tagName
+			}
+
 			// Call Lorekeeper.
-			err = lorekeeper.MakeReleaseNotes(
-				ctx,
-				cliArgs.TagName,
-				cliArgs.ReleaseCandidateRegex,
-				cliArgs.CurrentBranchName,
-				cliArgs.DefaultBranchName,
-				mode,
-			)
+			err = lorekeeper.MakeReleaseNotes(ctx, cliArgs.TagName, cliArgs.options(
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)...)
 			if err != nil {
 				return fmt.Errorf("lorekeeper failed to make release notes: %w", err)
 			}
@@ -71,8 +114,25 @@ func newLorekeeperCmd(ctx context.Context) *cobra.Command {
 		},
 	}
 
-	// Set the flags for the cobra.Command.
+	// Set the flags for the cobra.Command, and the profiling flags inherited
+	// by the subcommands.
 	cliArgs.setFlags(cmd)
+	prof.setFlags(cmd)
+
+	// Add the subcommands.
+	cmd.AddCommand(
+		newValidateCmd(ctx),
+		newNotifyCmd(ctx),
+		newFetchCmd(ctx),
+		newUnreleasedCmd(ctx),
+		newCheckChangelogCmd(ctx),
+		newNextCmd(ctx),
+		newCheckPRCmd(ctx),
+		newReportCmd(ctx),
+		newBumpCmd(),
+		newTagCmd(ctx),
+		newCacheCmd(),
+	)
 
 	return cmd
 }
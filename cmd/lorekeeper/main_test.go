@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func TestSetAndValidateArgsMode(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no flags", args: nil, want: lorekeeper.ModeRelease.Name},
+		{name: "offline", args: []string{"--offline"}, want: lorekeeper.ModeTag.Name},
+		{name: "offline train", args: []string{"--offline", "--mode", "train"}, want: lorekeeper.ModeTrain.Name},
+		{name: "mode", args: []string{"--mode", "tag"}, want: lorekeeper.ModeTag.Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			cmd := &cobra.Command{}
+			args.setFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := args.setAndValidateArgs(); err != nil {
+				t.Fatalf("setAndValidateArgs() error = %v", err)
+			}
+			if args.Mode != tt.want {
+				t.Errorf("setAndValidateArgs() mode = %q, want %q", args.Mode, tt.want)
+			}
+		})
+	}
+}
+
+func TestLorekeeperCmdWithoutFlags(t *testing.T) {
+	// Run in a repository without tags, which fails before the forge is
+	// needed, but after the mode has been read.
+	dir := t.TempDir()
+	if output, err := exec.Command("git", "init", "--quiet", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v: %s", err, output)
+	}
+	t.Chdir(dir)
+
+	cmd := newLorekeeperCmd(context.Background(), &profiler{})
+	cmd.SetArgs([]string{})
+	cmd.SetIn(eofReader{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err := cmd.Execute()
+	var modeErr *lorekeeper.ModeGetByNameError
+	if errors.As(err, &modeErr) {
+		t.Errorf("Execute() error = %v, want the mode to default to %q", err, lorekeeper.ModeRelease.Name)
+	}
+}
+
+// eofReader is an empty stdin.
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
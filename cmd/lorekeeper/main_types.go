@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/log"
@@ -53,6 +56,11 @@ type Arguments struct {
 	// that are release candidates.
 	ReleaseCandidateRegex string
 
+	// ReleaseCandidatePreset is the name of a built-in regex pattern to use to
+	// identify tags that are release candidates, in place of
+	// ReleaseCandidateRegex.
+	ReleaseCandidatePreset string
+
 	// CurrentBranchName is the name of the current branch.
 	CurrentBranchName string
 
@@ -60,6 +68,43 @@ type Arguments struct {
 	// repository (i.e - main, master, etc).
 	DefaultBranchName string
 
+	// Remote is the name of the remote pointing to the canonical repository.
+	Remote string
+
+	// RepoPath is the path of the local repository, which may be bare.
+	// Defaults to the working directory.
+	RepoPath string
+
+	// Repo is the `owner/name` of the repository on the forge. If provided,
+	// everything is resolved through the forge, without a local clone.
+	Repo string
+
+	// repository is the parsed Repo.
+	repository lorekeeper.Repository
+
+	// Deepen is whether a shallow clone is deepened automatically.
+	Deepen bool
+
+	// FetchTags is whether the tags of the remote are fetched first.
+	FetchTags bool
+
+	// Offline is whether the release notes are made from the local repository
+	// alone, without the network.
+	Offline bool
+
+	// Strategy is how the pull requests merged since the previous release are
+	// found. Overrides the configuration file.
+	Strategy string
+
+	// CollapseCandidates is whether the release notes of a final release list
+	// everything since the previous release, marked with the release
+	// candidate it was first published in. Overrides the configuration file.
+	CollapseCandidates bool
+
+	// Concurrency is the maximum number of calls to the forge that are made in
+	// parallel.
+	Concurrency int
+
 	// Mode determines whether GitHub Releases or Git Tags are being used to
 	// identify releases.
 	//
@@ -68,6 +113,108 @@ type Arguments struct {
 	//	MODE_TAG			// Can be used with any Git repositories.
 	Mode string
 
+	// ConfigPath is the path to the lorekeeper configuration file. Defaults to
+	// `.lorekeeper.yaml` in the working directory, if it exists.
+	ConfigPath string
+
+	// Format is the format that the release notes are output in. Overrides the
+	// configuration file.
+	Format string
+
+	// Publish is whether the release notes should be published as the body of
+	// the release. Overrides the configuration file.
+	Publish bool
+
+	// GoReleaser is whether the release notes are written to a file for
+	// GoReleaser, filtered by its changelog filters. Overrides the
+	// configuration file.
+	GoReleaser bool
+
+	// Stream is whether each entry is output as soon as it is fetched.
+	// Overrides the configuration file.
+	Stream bool
+
+	// Diff is whether a diff of the published release notes against the
+	// generated release notes should be output instead.
+	Diff bool
+
+	// Check is whether the release notes should be regenerated, failing if
+	// the output differs.
+	Check bool
+
+	// NotifySlack is the list of Slack incoming webhook URLs to post the
+	// release notes to. Appended to the configuration file.
+	NotifySlack []string
+
+	// NotifyDiscord is the list of Discord webhook URLs to post the release
+	// notes to. Appended to the configuration file.
+	NotifyDiscord []string
+
+	// NotifyTeams is the list of Microsoft Teams incoming webhook URLs to post
+	// the release notes to. Appended to the configuration file.
+	NotifyTeams []string
+
+	// Webhooks is the list of endpoints to post the structured JSON release
+	// notes to. Appended to the configuration file.
+	Webhooks []string
+
+	// WebhookSecret is the key used to sign the payload posted to the
+	// webhooks provided by flag.
+	WebhookSecret string
+
+	// Environment is the environment that lorekeeper is running in, selecting
+	// which of the environment specific webhooks are posted to. Overrides the
+	// configuration file.
+	Environment string
+
+	// Timezone is the IANA name of the timezone that dates are displayed and
+	// compared in. Overrides the configuration file.
+	Timezone string
+
+	// DateFormat is how dates are displayed, as a Go time layout or preset.
+	// Overrides the configuration file.
+	DateFormat string
+
+	// Template is the path to a Go text/template file that the markdown
+	// release notes are rendered with. Overrides the configuration file.
+	Template string
+
+	// Filter is a CEL expression that excludes pull requests from the release
+	// notes when false. Overrides the configuration file.
+	Filter string
+
+	// SignMethod is the method used to sign the rendered release notes.
+	// Overrides the configuration file.
+	SignMethod string
+
+	// SignKey is the key used to sign the rendered release notes. Overrides
+	// the configuration file.
+	SignKey string
+
+	// SignOutput is the path that the signed release notes are written to.
+	// Overrides the configuration file.
+	SignOutput string
+
+	// Provenance is whether a provenance section should be embedded in the
+	// release notes. Overrides the configuration file.
+	Provenance bool
+
+	// Sigstore is whether the uploaded release notes should be signed and
+	// attested with Sigstore. Overrides the configuration file.
+	Sigstore bool
+
+	// SiteDir is the directory that a static-site fragment of the release
+	// notes is written to. Overrides the configuration file.
+	SiteDir string
+
+	// SBOMPrevious is the path to the CycloneDX or SPDX JSON SBOM of the
+	// previous release. Overrides the configuration file.
+	SBOMPrevious string
+
+	// SBOMCurrent is the path to the CycloneDX or SPDX JSON SBOM of the current
+	// release. Overrides the configuration file.
+	SBOMCurrent string
+
 	// FromEnv is whether the Owner, Repo, Tag, and GitHub Token should be
 	// sourced from environment variables.
 	//
@@ -81,15 +228,201 @@ type Arguments struct {
 
 	// Define the debugging arguments.
 	Verbosity int
+
+	// Quiet suppresses all diagnostics except errors.
+	Quiet bool
+
+	// LogFormat is the format of the logs written to stderr.
+	LogFormat string
+
+	// LogLevel is the minimum level of the logs written to stderr.
+	LogLevel string
+
+	// NoProgress disables the progress reporting on stderr.
+	NoProgress bool
 }
 
 func (args *Arguments) setAndValidateArgs() error {
-	// Set the log level based on the verbosity flag.
-	args.setLogVerbosity()
+	// Set the log level based on the log level and verbosity flags.
+	if err := args.setLogLevel(); err != nil {
+		return err
+	}
+
+	// Set the log format.
+	if err := setLogFormat(args.LogFormat); err != nil {
+		return err
+	}
+
+	// Use the regex of the release candidate preset, if provided.
+	if args.ReleaseCandidatePreset != "" {
+		if args.ReleaseCandidateRegex != "" {
+			return errors.New("only one of --release-candidate-regex and --rc-preset can be provided")
+		}
+		preset, err := lorekeeper.GetReleaseCandidatePresetByName(args.ReleaseCandidatePreset)
+		if err != nil {
+			return err
+		}
+		args.ReleaseCandidateRegex = preset.Regex
+	}
+
+	// At least one call to the forge must be made at a time.
+	if args.Concurrency < 1 {
+		return fmt.Errorf("the concurrency must be at least 1, got %d", args.Concurrency)
+	}
+
+	// Only the tag mode can be used offline.
+	if args.Offline {
+		if args.Repo != "" {
+			return errors.New("only one of --offline and --repo can be provided")
+		}
+		if args.FetchTags {
+			return errors.New("only one of --offline and --fetch-tags can be provided")
+		}
+	}
+
+	// Default the mode to that of the library, or to the tag mode offline.
+	if args.Mode == "" {
+		args.Mode = lorekeeper.ModeRelease.Name
+		if args.Offline {
+			args.Mode = lorekeeper.ModeTag.Name
+		}
+	}
+
+	// Parse the repository on the forge, if provided.
+	if args.Repo != "" {
+		if args.RepoPath != "" {
+			return errors.New("only one of --repo and --repo-path can be provided")
+		}
+		repository, err := lorekeeper.ParseRepository(args.Repo)
+		if err != nil {
+			return err
+		}
+		args.repository = repository
+	}
+
+	// Read the configuration file from the repository, if it has one and no
+	// other configuration file was provided.
+	if args.ConfigPath == "" && args.RepoPath != "" {
+		path := filepath.Join(args.RepoPath, lorekeeper.DefaultConfigPath)
+		if _, err := os.Stat(path); err == nil {
+			args.ConfigPath = path
+		}
+	}
+
+	// Suppress everything but errors in quiet mode.
+	if args.Quiet {
+		log.SetLevel(log.ErrorLevel)
+	}
 
 	return nil
 }
 
+// applyToConfig overrides the fields of the provided lorekeeper.Config with
+// any arguments that have been set.
+func (args *Arguments) applyToConfig(config *lorekeeper.Config) {
+	if args.Format != "" {
+		config.Format = lorekeeper.Format(args.Format)
+	}
+	if args.Publish {
+		config.Publish.Enabled = true
+	}
+	if args.Strategy != "" {
+		config.Strategy = lorekeeper.Strategy(args.Strategy)
+	}
+	if args.CollapseCandidates {
+		config.ReleaseCandidates.Collapse = true
+	}
+	if args.GoReleaser {
+		config.GoReleaser.Enabled = true
+	}
+	if args.Stream {
+		config.Stream = true
+	}
+	if args.Diff {
+		config.Diff = true
+	}
+	if args.Check {
+		config.Check = true
+	}
+	config.Notify.Slack = append(config.Notify.Slack, args.NotifySlack...)
+	config.Notify.Discord = append(config.Notify.Discord, args.NotifyDiscord...)
+	for _, webhookURL := range args.NotifyTeams {
+		config.Notify.Teams = append(config.Notify.Teams, lorekeeper.TeamsWebhookConfig{URL: webhookURL})
+	}
+	for _, webhookURL := range args.Webhooks {
+		config.Notify.Webhooks = append(config.Notify.Webhooks, lorekeeper.WebhookConfig{
+			URL:    webhookURL,
+			Secret: args.WebhookSecret,
+		})
+	}
+	if args.Environment != "" {
+		config.Notify.Environment = args.Environment
+	}
+	if args.Timezone != "" {
+		config.Timezone = args.Timezone
+	}
+	if args.DateFormat != "" {
+		config.DateFormat = args.DateFormat
+	}
+	if args.Template != "" {
+		config.Template = args.Template
+	}
+	if args.Filter != "" {
+		config.Filter = args.Filter
+	}
+	if args.SignMethod != "" {
+		config.Sign.Method = lorekeeper.SignMethod(args.SignMethod)
+	}
+	if args.SignKey != "" {
+		config.Sign.Key = args.SignKey
+	}
+	if args.SignOutput != "" {
+		config.Sign.Output = args.SignOutput
+	}
+	if args.Provenance {
+		config.Provenance = true
+	}
+	if args.Sigstore {
+		config.Sigstore.Enabled = true
+	}
+	if args.SiteDir != "" {
+		config.Site.Dir = args.SiteDir
+	}
+	if args.SBOMPrevious != "" {
+		config.SBOM.Previous = args.SBOMPrevious
+	}
+	if args.SBOMCurrent != "" {
+		config.SBOM.Current = args.SBOMCurrent
+	}
+	config.Progress = !args.NoProgress && !args.Quiet
+}
+
+// options returns the lorekeeper.Option values for the arguments, followed by
+// any extra options provided.
+func (args *Arguments) options(extra ...lorekeeper.Option) []lorekeeper.Option {
+	opts := []lorekeeper.Option{
+		lorekeeper.WithReleaseCandidateRegex(args.ReleaseCandidateRegex),
+		lorekeeper.WithCurrentBranch(args.CurrentBranchName),
+		lorekeeper.WithDefaultBranch(args.DefaultBranchName),
+		lorekeeper.WithRemote(args.Remote),
+		lorekeeper.WithRepoPath(args.RepoPath),
+		lorekeeper.WithDeepen(args.Deepen),
+		lorekeeper.WithFetchTags(args.FetchTags),
+		lorekeeper.WithOffline(args.Offline),
+		lorekeeper.WithConcurrency(args.Concurrency),
+	}
+
+	// Resolve everything through the forge, if the repository was provided.
+	if args.Repo != "" {
+		opts = append(opts,
+			lorekeeper.WithRepository(args.repository),
+			lorekeeper.WithAPIOnly(true),
+		)
+	}
+
+	return append(opts, extra...)
+}
+
 // setFlags set the flags for the provided cobra.Command.
 func (args *Arguments) setFlags(cmd *cobra.Command) {
 	var efsl extendedFlagSetList
@@ -97,22 +430,143 @@ func (args *Arguments) setFlags(cmd *cobra.Command) {
 	// Application flags.
 	fsApplication := efsl.NewExtendedFlagSet("Application", nil)
 	fsApplication.StringVarP(&args.TagName, "tag", "t", "",
-		"The release tag to use when checking for relevant branches and pull requests.",
+		"The release tag to use when checking for relevant branches and pull requests. Defaults to the latest tag, confirmed when running interactively.",
 	)
 	fsApplication.StringVarP(&args.ReleaseCandidateRegex, "release-candidate-regex", "r", "",
 		"The regex pattern to use to identify tags that are release candidates.",
 	)
+	fsApplication.StringVar(&args.ReleaseCandidatePreset, "rc-preset", "", getReleaseCandidatePresetsUsage())
 	fsApplication.StringVarP(&args.CurrentBranchName, "current-branch-name", "c", "",
-		"The name of the current branch.",
+		"The name of the current branch. Detected from HEAD if not provided.",
 	)
 	fsApplication.StringVarP(&args.DefaultBranchName, "default-branch-name", "d", "",
-		"The name of the default branch in the target repository (i.e - main, master, etc).",
+		"The name of the default branch in the target repository (i.e - main, master, etc). Detected from the forge, or the remote, if not provided.",
+	)
+	fsApplication.StringVar(&args.Repo, "repo", "",
+		"The owner/name of the repository on the forge. If provided, the tags, commits, and pull requests are resolved through the forge API, without a local clone.",
+	)
+	fsApplication.StringVar(&args.RepoPath, "repo-path", "",
+		"The path of the local repository, which may be a bare repository (i.e - a mirror). Defaults to the working directory.",
+	)
+	fsApplication.BoolVar(&args.Deepen, "deepen", false,
+		"Fetch the full history and tags of a shallow clone (i.e - a CI checkout), rather than failing.",
+	)
+	fsApplication.BoolVar(&args.FetchTags, "fetch-tags", false,
+		"Fetch the tags of the remote first, so the tag is compared with the tags of the canonical repository (i.e - with --remote upstream).",
+	)
+	fsApplication.BoolVar(&args.Offline, "offline", false,
+		"Make the release notes from the local tags and merge commits alone, without the network. The pull request bodies, labels, and avatars are omitted. Implies --mode tag.",
+	)
+	fsApplication.StringVar(&args.Remote, "remote", "",
+		"The remote pointing to the canonical repository, i.e - upstream in a fork. The repository and default branch are detected from it, and its tags are fetched with --fetch-tags (default \"origin\").",
+	)
+	fsApplication.StringVar(&args.Strategy, "strategy", "",
+		"How the pull requests merged since the previous release are found, either \"merge-base\", from the commit graph, or \"timestamp\", from the date the previous release was published (default \"merge-base\").",
+	)
+	fsApplication.BoolVar(&args.CollapseCandidates, "collapse-candidates", false,
+		"List everything since the previous release in the release notes of a final release, once each, marked with the release candidate it was first published in.",
+	)
+	fsApplication.IntVar(&args.Concurrency, "concurrency", lorekeeper.DefaultConcurrency,
+		"The maximum number of calls to the forge (i.e - to fetch the pull requests) made in parallel. Lower it on strict rate limits or small runners.",
 	)
 	fsApplication.StringVarP(&args.Mode, "mode", "m", "", getModesUsage())
+	fsApplication.StringVarP(&args.Format, "format", "f", "", getFormatsUsage())
+	fsApplication.BoolVar(&args.Publish, "publish", false,
+		"Publish the release notes as the body of the release.",
+	)
+	fsApplication.BoolVar(&args.GoReleaser, "goreleaser", false,
+		"Write the release notes to a file for goreleaser release --release-notes (default \"release-notes.md\"), leaving out the entries excluded by the changelog filters of the GoReleaser configuration.",
+	)
+	fsApplication.BoolVar(&args.Stream, "stream", false,
+		"Output each entry as markdown as soon as it is fetched, in the order listed, keeping the memory used flat for very large releases.",
+	)
+	fsApplication.BoolVar(&args.Diff, "diff", false,
+		"Output a unified diff of the published release notes against the generated release notes.",
+	)
+	fsApplication.BoolVar(&args.Check, "check", false,
+		"Regenerate the release notes, and fail if the output is not identical.",
+	)
+
+	// Notification flags.
+	fsNotification := efsl.NewExtendedFlagSet("Notification", nil)
+	fsNotification.StringSliceVar(&args.NotifySlack, "notify-slack", nil,
+		"A Slack incoming webhook URL to post the release notes to. Can be repeated.",
+	)
+	fsNotification.StringSliceVar(&args.NotifyDiscord, "notify-discord", nil,
+		"A Discord webhook URL to post the release notes to. Can be repeated.",
+	)
+	fsNotification.StringSliceVar(&args.NotifyTeams, "notify-teams", nil,
+		"A Microsoft Teams incoming webhook URL to post the release notes to. Can be repeated.",
+	)
+	fsNotification.StringSliceVar(&args.Webhooks, "webhook", nil,
+		"An endpoint to post the structured JSON release notes to. Can be repeated.",
+	)
+	fsNotification.StringVar(&args.WebhookSecret, "webhook-secret", "",
+		"The key used to sign the payload posted to the --webhook endpoints, sent as an HMAC-SHA256 signature "+
+			"in the X-Lorekeeper-Signature-256 header.",
+	)
+	fsNotification.StringVar(&args.Environment, "environment", "",
+		"The environment that lorekeeper is running in, selecting which of the environment specific webhooks "+
+			"are posted to.",
+	)
+
+	// Configuration flags.
+	fsConfiguration := efsl.NewExtendedFlagSet("Configuration", nil)
+	fsConfiguration.StringVar(&args.ConfigPath, "config", "",
+		fmt.Sprintf("The path to the configuration file (default %q, if it exists).", lorekeeper.DefaultConfigPath),
+	)
+	fsConfiguration.StringVar(&args.Timezone, "timezone", "",
+		"The IANA name of the timezone, i.e - \"Europe/London\", that dates are displayed and compared in (default \"UTC\").",
+	)
+	fsConfiguration.StringVar(&args.DateFormat, "date-format", "",
+		"How dates are displayed, as a Go time layout or one of the \"date\", \"datetime\", \"rfc3339\", \"long\", or \"short\" presets (default \"date\").",
+	)
+	fsConfiguration.StringVar(&args.Template, "template", "",
+		"The path to a Go text/template file to render the markdown release notes with.",
+	)
+	fsConfiguration.StringVar(&args.Filter, "filter", "",
+		"A CEL expression, evaluated for each pull request as pr, that excludes the pull request when false, i.e - 'pr.labels.exists(l, l == \"public\")'.",
+	)
+	fsConfiguration.StringVar(&args.SignMethod, "sign-method", "",
+		"The method used to sign the rendered release notes, either \"gpg\" or \"ssh\".",
+	)
+	fsConfiguration.StringVar(&args.SignKey, "sign-key", "",
+		"The GPG key ID, or the path to the SSH private key, used to sign the rendered release notes.",
+	)
+	fsConfiguration.StringVar(&args.SignOutput, "sign-output", "",
+		"The path to write the rendered release notes to, with their detached signature alongside them.",
+	)
+	fsConfiguration.BoolVar(&args.Provenance, "provenance", false,
+		"Embed a provenance section (builder, workflow ref, and commit SHA) when running in GitHub Actions or GitLab CI.",
+	)
+	fsConfiguration.BoolVar(&args.Sigstore, "sigstore", false,
+		"Sign and attest the uploaded release notes keylessly with cosign, uploading the Sigstore bundles alongside them.",
+	)
+	fsConfiguration.StringVar(&args.SiteDir, "site-dir", "",
+		"The directory to write a static-site fragment (markdown with YAML front matter) of the release notes to.",
+	)
+	fsConfiguration.StringVar(&args.SBOMPrevious, "sbom-previous", "",
+		"The path to the CycloneDX or SPDX JSON SBOM of the previous release.",
+	)
+	fsConfiguration.StringVar(&args.SBOMCurrent, "sbom-current", "",
+		"The path to the CycloneDX or SPDX JSON SBOM of the current release.",
+	)
 
 	// Debugging flags.
 	fsDebugging := efsl.NewExtendedFlagSet("Debugging", nil)
 	fsDebugging.CountVarP(&args.Verbosity, "verbose", "v", getVerbosityUsage())
+	fsDebugging.BoolVarP(&args.Quiet, "quiet", "q", false,
+		"Suppress all diagnostics except errors. Only the release notes are ever written to stdout.",
+	)
+	fsDebugging.BoolVar(&args.NoProgress, "no-progress", false,
+		"Disable the progress reporting on stderr, which is otherwise shown when stderr is a terminal.",
+	)
+	fsDebugging.StringVar(&args.LogLevel, "log-level", "",
+		fmt.Sprintf("The minimum level of the logs written to stderr, one of debug, info, warn, error, or fatal (default %q). Can also be set with the %s environment variable.", defaultLogLevel, logLevelEnvVar),
+	)
+	fsDebugging.StringVar(&args.LogFormat, "log-format", "text",
+		fmt.Sprintf("The format of the logs written to stderr, one of %s. Use json for machine-parseable logs of the commands run, requests made, and their timings.", getLogFormatNamesString()),
+	)
 
 	// Add the extended flag sets to the cobra.Command.
 	efsl.AddToCobraCmd(cmd)
@@ -121,22 +575,36 @@ func (args *Arguments) setFlags(cmd *cobra.Command) {
 
 }
 
-// setLogVerbosity sets the logging level based on the `--verbosity` flag.
-func (args *Arguments) setLogVerbosity() {
-	// If the verbosity is less than or equal to 0, wo do not change the log level.
-	if args.Verbosity <= 0 {
-		return
-	}
+// setLogLevel sets the logging level based on the `--log-level` flag, or the
+// `--verbose` flag, or the LOREKEEPER_LOG_LEVEL environment variable, in that
+// order of precedence.
+func (args *Arguments) setLogLevel() error {
+	switch {
+	case args.LogLevel != "":
+		level, err := parseLogLevel(args.LogLevel)
+		if err != nil {
+			return err
+		}
+		log.SetLevel(level)
+	case args.Verbosity > 0:
+		// Cap the verbosity to the maximum allowed value.
+		var (
+			defaultLogLevelIndex = logLevelIndex(defaultLogLevel)
+			maxVerbosity         = defaultLogLevelIndex
+		)
+		args.Verbosity = min(args.Verbosity, maxVerbosity)
 
-	// Cap the verbosity to the maximum allowed value.
-	var (
-		defaultLogLevelIndex = logLevelIndex(defaultLogLevel)
-		maxVerbosity         = len(_AllLogLevels) - defaultLogLevelIndex - 1
-	)
-	args.Verbosity = max(args.Verbosity, maxVerbosity)
+		// Set the logging level depending on the verbosity flag.
+		log.SetLevel(_AllLogLevels[defaultLogLevelIndex-args.Verbosity])
+	case os.Getenv(logLevelEnvVar) != "":
+		level, err := parseLogLevel(os.Getenv(logLevelEnvVar))
+		if err != nil {
+			return fmt.Errorf("%s: %w", logLevelEnvVar, err)
+		}
+		log.SetLevel(level)
+	}
 
-	// Set the logging level depending on the verbosity flag.
-	log.SetLevel(_AllLogLevels[defaultLogLevelIndex+args.Verbosity])
+	return nil
 }
 
 func getModesUsage() string {
@@ -144,15 +612,38 @@ func getModesUsage() string {
 	for _, mode := range lorekeeper.GetModes() {
 		availableModes = append(availableModes, fmt.Sprintf("  %s: %s", mode.Name, mode.Description))
 	}
-	return "Determines whether GitHub Releases or Git Tags are being used to identify releases.\n" +
+	return "Determines whether GitHub Releases, Git Tags, or a scheduled release train are used to identify releases " +
+		"(default \"" + lorekeeper.ModeRelease.Name + "\", or \"" + lorekeeper.ModeTag.Name + "\" with --offline).\n" +
 		strings.Join(availableModes, "\n")
 }
 
+// getReleaseCandidatePresetsUsage returns the usage string for the
+// `--rc-preset` flag.
+func getReleaseCandidatePresetsUsage() string {
+	var availablePresets []string
+	for _, preset := range lorekeeper.GetReleaseCandidatePresets() {
+		availablePresets = append(availablePresets, fmt.Sprintf("  %s: %s", preset.Name, preset.Description))
+	}
+	return "A built-in regex pattern to use to identify tags that are release candidates, in place of --release-candidate-regex.\n" +
+		strings.Join(availablePresets, "\n")
+}
+
+// getFormatsUsage returns the usage string for the `--format` flag.
+func getFormatsUsage() string {
+	var availableFormats []string
+	for _, format := range lorekeeper.GetFormats() {
+		availableFormats = append(availableFormats, "  "+string(format))
+	}
+	return "The format that the release notes are output in (default \"markdown\").\n" +
+		strings.Join(availableFormats, "\n") + "\n" +
+		"  or the name of a format plugin (plugins.formats)"
+}
+
 // getVerbosityUsage returns the usage string for the `--verbosity` flag.
 func getVerbosityUsage() string {
 	var usage []string
 
-	for idx, level := range logLevelsAbove(defaultLogLevel) {
+	for idx, level := range logLevelsBelow(defaultLogLevel) {
 		usage = append(usage, fmt.Sprintf(
 			"  -%s = %s",
 			strings.Repeat("v", idx+1),
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newNextCmd(ctx context.Context) *cobra.Command {
+	var (
+		cliArgs     Arguments
+		versionOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "next [flags]",
+		Short: "Predict the next version, and preview its release notes.",
+		Long: "Next predicts the version of the next release from the changes merged since the latest release (or " +
+			"tag in the tag mode), by the highest bump of their conventional commit titles or changesets (major for " +
+			"breaking changes, minor for features, and patch otherwise), and outputs it followed by a preview of " +
+			"the release notes, before the release has been tagged.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Translate the Mode string to a lorekeeper.mode.
+			mode, err := lorekeeper.GetModeByName(cliArgs.Mode)
+			if err != nil {
+				return err
+			}
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+
+			opts := cliArgs.options(
+				lorekeeper.WithWriter(cmd.OutOrStdout()),
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)
+
+			// Output the version alone, i.e - for scripts, or with a preview.
+			if versionOnly {
+				next, err := lorekeeper.PredictNextVersion(ctx, opts...)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), next.TagName)
+				return nil
+			}
+			return lorekeeper.MakeNextVersionNotes(ctx, opts...)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+	cmd.Flags().BoolVar(&versionOnly, "version-only", false,
+		"Output only the predicted version, without the preview of the release notes.",
+	)
+
+	return cmd
+}
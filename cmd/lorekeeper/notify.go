@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+// notifyArguments represents the arguments shared by the notify subcommands.
+type notifyArguments struct {
+	// TagName is the release tag that the release notes are for.
+	TagName string
+
+	// Webhooks is the list of webhook URLs to post the release notes to.
+	Webhooks []string
+}
+
+// setFlags set the flags for the provided notify subcommand.
+func (args *notifyArguments) setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&args.TagName, "tag", "t", "",
+		"The release tag that the release notes are for.",
+	)
+	cmd.Flags().StringSliceVarP(&args.Webhooks, "webhook", "w", nil,
+		"A webhook URL to post the release notes to. Can be repeated.",
+	)
+	_ = cmd.MarkFlagRequired("tag")
+	_ = cmd.MarkFlagRequired("webhook")
+}
+
+// readNotes reads the rendered release notes from the file named by the first
+// argument, or from stdin if there is no argument or it is `-`.
+func readNotes(cmd *cobra.Command, args []string) (string, error) {
+	if len(args) == 0 || args[0] == "-" {
+		notes, err := io.ReadAll(cmd.InOrStdin())
+		return string(notes), err
+	}
+
+	notes, err := os.ReadFile(args[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to read the release notes: %w", err)
+	}
+	return string(notes), nil
+}
+
+func newNotifyCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Post rendered release notes to chat and webhook destinations.",
+		Long: "Notify posts release notes that have already been rendered (i.e - by a previous `lorekeeper` run) " +
+			"to a chat or webhook destination, reading them from a file or stdin.",
+	}
+
+	cmd.AddCommand(
+		newNotifySlackCmd(ctx),
+		newNotifyDiscordCmd(ctx),
+		newNotifyTeamsCmd(ctx),
+		newNotifyWebhookCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newNotifySlackCmd(ctx context.Context) *cobra.Command {
+	var cliArgs notifyArguments
+
+	cmd := &cobra.Command{
+		Use:   "slack [flags] [file]",
+		Short: "Post the release notes to Slack incoming webhooks, as Block Kit blocks.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notes, err := readNotes(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			return lorekeeper.NotifySlack(ctx, cliArgs.Webhooks, cliArgs.TagName, notes)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+
+	return cmd
+}
+
+func newNotifyDiscordCmd(ctx context.Context) *cobra.Command {
+	var cliArgs notifyArguments
+
+	cmd := &cobra.Command{
+		Use:   "discord [flags] [file]",
+		Short: "Post the release notes to Discord webhooks, as embeds.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notes, err := readNotes(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			return lorekeeper.NotifyDiscord(ctx, cliArgs.Webhooks, cliArgs.TagName, notes)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+
+	return cmd
+}
+
+func newNotifyTeamsCmd(ctx context.Context) *cobra.Command {
+	var cliArgs notifyArguments
+
+	cmd := &cobra.Command{
+		Use:   "teams [flags] [file]",
+		Short: "Post the release notes to Microsoft Teams incoming webhooks, as an Adaptive Card.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notes, err := readNotes(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			return lorekeeper.NotifyTeams(ctx, cliArgs.Webhooks, cliArgs.TagName, notes)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+
+	return cmd
+}
+
+func newNotifyWebhookCmd(ctx context.Context) *cobra.Command {
+	var (
+		webhooks []string
+		secret   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "webhook [flags] [file]",
+		Short: "Post the structured JSON release notes to arbitrary endpoints, with an HMAC signature.",
+		Long: "Webhook posts release notes rendered with `--format json` to arbitrary endpoints. If a secret is " +
+			"provided, the HMAC-SHA256 signature of the payload is sent in the X-Lorekeeper-Signature-256 header.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notes, err := readNotes(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			var configs []lorekeeper.WebhookConfig
+			for _, webhookURL := range webhooks {
+				configs = append(configs, lorekeeper.WebhookConfig{URL: webhookURL, Secret: secret})
+			}
+
+			return lorekeeper.NotifyWebhooks(ctx, configs, []byte(notes))
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cmd.Flags().StringSliceVarP(&webhooks, "webhook", "w", nil,
+		"An endpoint URL to post the release notes to. Can be repeated.",
+	)
+	cmd.Flags().StringVarP(&secret, "secret", "s", "",
+		"The key used to sign the payload.",
+	)
+	_ = cmd.MarkFlagRequired("webhook")
+
+	return cmd
+}
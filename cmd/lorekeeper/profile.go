@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/spf13/cobra"
+)
+
+// profiler writes the pprof profiles requested with the `--profile-cpu` and
+// `--profile-mem` flags, i.e - to diagnose the slow generation of the release
+// notes of a large repository.
+type profiler struct {
+	// cpuPath is the path that the CPU profile is written to.
+	cpuPath string
+
+	// memPath is the path that the heap profile is written to.
+	memPath string
+
+	// cpuFile is the file of the running CPU profile, if any.
+	cpuFile *os.File
+}
+
+// setFlags sets the profiling flags on the provided cobra.Command, inherited by
+// its subcommands, starting the profiling before the command is run.
+func (p *profiler) setFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&p.cpuPath, "profile-cpu", "",
+		"Write a pprof CPU profile of the run to the provided path, for go tool pprof.",
+	)
+	cmd.PersistentFlags().StringVar(&p.memPath, "profile-mem", "",
+		"Write a pprof heap profile, taken at the end of the run, to the provided path, for go tool pprof.",
+	)
+	cmd.PersistentPreRunE = func(*cobra.Command, []string) error {
+		return p.start()
+	}
+}
+
+// start starts the CPU profile, if requested.
+func (p *profiler) start() error {
+	if p.cpuPath == "" {
+		return nil
+	}
+
+	file, err := os.Create(p.cpuPath)
+	if err != nil {
+		return fmt.Errorf("failed to create the CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to start the CPU profile: %w", err)
+	}
+	p.cpuFile = file
+	return nil
+}
+
+// stop stops the CPU profile, if running, and writes the heap profile, if
+// requested.
+func (p *profiler) stop() error {
+	var errs []error
+
+	// Stop the CPU profile.
+	if p.cpuFile != nil {
+		pprof.StopCPUProfile()
+		if err := p.cpuFile.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the CPU profile: %w", err))
+		}
+		p.cpuFile = nil
+	}
+
+	// Write the heap profile, with up-to-date statistics.
+	if p.memPath != "" {
+		file, err := os.Create(p.memPath)
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("failed to create the heap profile: %w", err))...)
+		}
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the heap profile: %w", err))
+		}
+		if err := file.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the heap profile: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newReportCmd(ctx context.Context) *cobra.Command {
+	var (
+		cliArgs  Arguments
+		minScore int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report [flags]",
+		Short: "Score the hygiene of the pull requests behind the release notes.",
+		Long: "Report scores the hygiene of the pull requests in the release notes of the tag, or of the changes " +
+			"merged since the latest release (or tag in the tag mode) if no tag is provided: those with an empty " +
+			"body, without a label, or without a conventional commit title. It outputs a markdown table of the " +
+			"checks, and fails if any of the thresholds of the report configuration aren't met, so it can be used " +
+			"as a CI check.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+			if minScore < 0 || minScore > 100 {
+				return fmt.Errorf("invalid minimum score %d: expected a percentage from 0 to 100", minScore)
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Translate the Mode string to a lorekeeper.mode.
+			mode, err := lorekeeper.GetModeByName(cliArgs.Mode)
+			if err != nil {
+				return err
+			}
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+			if cmd.Flags().Changed("min-score") {
+				config.Report.MinScore = minScore
+			}
+
+			return lorekeeper.MakeNotesReport(ctx, cliArgs.TagName, cliArgs.options(
+				lorekeeper.WithWriter(cmd.OutOrStdout()),
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)...)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+	cmd.Flags().IntVar(&minScore, "min-score", 0,
+		"The minimum overall score, as a percentage, below which the command fails. Overrides the configuration file.",
+	)
+
+	return cmd
+}
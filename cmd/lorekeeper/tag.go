@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newTagCmd(ctx context.Context) *cobra.Command {
+	var (
+		cliArgs Arguments
+		sign    bool
+		noPush  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tag [flags] <tag>",
+		Short: "Create and push an annotated tag of the generated release notes.",
+		Long: "Tag creates an annotated (optionally signed) tag whose message is the generated markdown release " +
+			"notes, and pushes it to the remote, closing the loop from release notes to tag.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The tag is provided as an argument.
+			cliArgs.TagName = args[0]
+
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Translate the Mode string to a lorekeeper.mode.
+			mode, err := lorekeeper.GetModeByName(cliArgs.Mode)
+			if err != nil {
+				return err
+			}
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+			if sign {
+				config.Tag.Sign = true
+			}
+			if noPush {
+				config.Tag.SkipPush = true
+			}
+			if cliArgs.Remote != "" {
+				config.Tag.Remote = cliArgs.Remote
+			}
+
+			return lorekeeper.CreateTag(ctx, cliArgs.TagName, cliArgs.options(
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)...)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+	_ = cmd.Flags().MarkHidden("tag")
+	cmd.Flags().BoolVarP(&sign, "sign", "s", false,
+		"Create a signed tag, using the signing key configured in git.",
+	)
+	cmd.Flags().BoolVar(&noPush, "no-push", false,
+		"Create the tag without pushing it.",
+	)
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// initTracing configures the global OpenTelemetry TracerProvider from the
+// standard environment variables, returning a function that flushes and shuts
+// it down.
+//
+// Tracing is enabled by setting `OTEL_TRACES_EXPORTER` to "otlp" (configured
+// by the standard `OTEL_EXPORTER_OTLP_*` variables) or "console" (written to
+// stderr), or by setting an OTLP endpoint.
+func initTracing(ctx context.Context) (func(context.Context) error, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	// Create the exporter.
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "console":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "":
+		if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+			return func(context.Context) error { return nil }, nil
+		}
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Describe the service, allowing the standard environment variables to
+	// override it.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", _APP_NAME)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+func newUnreleasedCmd(ctx context.Context) *cobra.Command {
+	var (
+		cliArgs   Arguments
+		changelog string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "unreleased [flags]",
+		Short: "Render the changes merged since the latest release.",
+		Long: "Unreleased renders everything merged since the latest release (or tag in the tag mode) as markdown, " +
+			"beneath an \"Unreleased\" heading. With --changelog, the unreleased section of the changelog is " +
+			"replaced instead, so that it can be kept current on every merge.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			// Translate the Mode string to a lorekeeper.mode.
+			mode, err := lorekeeper.GetModeByName(cliArgs.Mode)
+			if err != nil {
+				return err
+			}
+
+			// Load the configuration file.
+			config, err := lorekeeper.LoadConfig(cliArgs.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			// Override the configuration with any arguments provided.
+			cliArgs.applyToConfig(&config)
+
+			opts := cliArgs.options(
+				lorekeeper.WithWriter(cmd.OutOrStdout()),
+				lorekeeper.WithMode(mode),
+				lorekeeper.WithConfig(config),
+			)
+
+			// Replace the unreleased section of the changelog, or output it.
+			if changelog != "" {
+				if err := lorekeeper.WriteUnreleasedNotes(ctx, changelog, opts...); err != nil {
+					return err
+				}
+				log.Info("Updated the unreleased changes", "path", changelog)
+				return nil
+			}
+			return lorekeeper.MakeUnreleasedNotes(ctx, opts...)
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+	cmd.Flags().StringVar(&changelog, "changelog", "",
+		"The path of the changelog whose unreleased section is replaced, i.e - CHANGELOG.md, instead of outputting it.",
+	)
+
+	return cmd
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/riftspire/lorekeeper/pkg/lorekeeper"
+	"github.com/spf13/cobra"
+)
+
+// errValidationFailed is returned by the validate command when any of the
+// checks fail. The details of each failure have already been output.
+var errValidationFailed = errors.New("validation failed")
+
+// validationCheck represents a single check performed by the validate
+// command.
+type validationCheck struct {
+	Name  string
+	Check func() error
+
+	// Skipped is why the check doesn't apply, if it doesn't.
+	Skipped string
+}
+
+func newValidateCmd(ctx context.Context) *cobra.Command {
+	var cliArgs Arguments
+
+	cmd := &cobra.Command{
+		Use:   "validate [flags]",
+		Short: "Check that the release notes can be generated.",
+		Long: "Validate checks the configuration file, release candidate regex, tag and branch names, and forge connectivity, and " +
+			"that the release notes for the provided tag (if any) can be generated, without outputting or " +
+			"publishing them. It is intended as a fast pre-flight check in CI.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Validate the arguments.
+			if err := cliArgs.setAndValidateArgs(); err != nil {
+				return err
+			}
+
+			// Silence the usage message printing on error from here on.
+			cmd.SilenceUsage = true
+
+			var (
+				config lorekeeper.Config
+				mode   = lorekeeper.ModeRelease
+			)
+
+			checks := []validationCheck{
+				{
+					Name: "configuration file",
+					Check: func() (err error) {
+						config, err = lorekeeper.LoadConfig(cliArgs.ConfigPath)
+						if err != nil {
+							return err
+						}
+						cliArgs.applyToConfig(&config)
+						return config.Validate()
+					},
+				},
+				{
+					Name: "release candidate regex",
+					Check: func() error {
+						return lorekeeper.ValidateReleaseCandidateRegex(cliArgs.ReleaseCandidateRegex)
+					},
+				},
+				{
+					Name: "mode",
+					Check: func() (err error) {
+						mode, err = lorekeeper.GetModeByName(cliArgs.Mode)
+						return err
+					},
+				},
+				{
+					Name: "forge connectivity",
+					Check: func() error {
+						return lorekeeper.CheckForge(ctx, cliArgs.options()...)
+					},
+				},
+			}
+
+			// The forge isn't used offline.
+			if cliArgs.Offline {
+				checks[len(checks)-1].Skipped = "not needed offline"
+			}
+
+			// Check the tag and branch names, if a tag was provided.
+			if cliArgs.TagName != "" {
+				checks = append(checks, validationCheck{
+					Name: "inputs",
+					Check: func() error {
+						return lorekeeper.ValidateInputs(ctx, cliArgs.TagName, cliArgs.options(
+							lorekeeper.WithMode(mode),
+							lorekeeper.WithConfig(config),
+						)...)
+					},
+				})
+			}
+
+			// Output the result of each check.
+			failed := runValidationChecks(cmd.OutOrStdout(), checks)
+
+			// Only check the release notes can be generated if everything
+			// they depend on is valid.
+			if cliArgs.TagName != "" && !failed {
+				failed = runValidationChecks(cmd.OutOrStdout(), []validationCheck{{
+					Name: fmt.Sprintf("release notes (%s)", cliArgs.TagName),
+					Check: func() error {
+						return lorekeeper.CheckReleaseNotes(ctx, cliArgs.TagName, cliArgs.options(
+							lorekeeper.WithMode(mode),
+							lorekeeper.WithConfig(config),
+						)...)
+					},
+				}})
+			}
+
+			if failed {
+				return errValidationFailed
+			}
+
+			return nil
+		},
+	}
+
+	// Set the flags for the cobra.Command.
+	cliArgs.setFlags(cmd)
+
+	return cmd
+}
+
+// runValidationChecks runs each of the provided checks, outputting the result
+// of each to the writer. It reports whether any of the checks failed.
+func runValidationChecks(w io.Writer, checks []validationCheck) bool {
+	var failed bool
+
+	for _, check := range checks {
+		if check.Skipped != "" {
+			fmt.Fprintf(w, "- %s: skipped, %s\n", check.Name, check.Skipped)
+			continue
+		}
+		if err := check.Check(); err != nil {
+			fmt.Fprintf(w, "✗ %s: %v\n", check.Name, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(w, "✓ %s\n", check.Name)
+	}
+
+	return failed
+}
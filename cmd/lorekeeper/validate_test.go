@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestValidateCmdOffline(t *testing.T) {
+	// Make a repository with a tagged squash merge commit.
+	dir := t.TempDir()
+	for _, key := range []string{"AUTHOR", "COMMITTER"} {
+		t.Setenv("GIT_"+key+"_NAME", "Jane Doe")
+		t.Setenv("GIT_"+key+"_EMAIL", "jane@example.com")
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"commit", "--quiet", "--allow-empty", "--message", "Add a flag (#1)"},
+		{"tag", "v1.0.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %s: %v: %s", strings.Join(args, " "), err, output)
+		}
+	}
+	t.Chdir(dir)
+
+	var output bytes.Buffer
+	cmd := newValidateCmd(context.Background())
+	cmd.SetArgs([]string{"--offline", "--tag", "v1.0.0", "--current-branch-name", "main", "--default-branch-name", "main"})
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v\n%s", err, output.String())
+	}
+	if want := "- forge connectivity: skipped"; !strings.Contains(output.String(), want) {
+		t.Errorf("Execute() output =\n%s\nwant it to contain %q", output.String(), want)
+	}
+}
@@ -0,0 +1,52 @@
+// Package conventional parses commit messages, and pull request titles, that
+// follow the conventional commits specification, i.e -
+// `feat(api)!: add a new endpoint`.
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// reSubject matches a conventional commit subject, i.e -
+// `feat(api)!: add a new endpoint`.
+var reSubject = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// reBreakingFooter matches a breaking change footer in a conventional commit
+// body, i.e - `BREAKING CHANGE: the endpoint was removed`.
+var reBreakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*\S`)
+
+// Commit represents a commit message that follows the conventional commits
+// specification.
+type Commit struct {
+	// Type is the lowercased type of the change, i.e - `feat` or `fix`.
+	Type string
+
+	// Scope is the optional scope of the change, i.e - `api`.
+	Scope string
+
+	// Breaking is whether the change is breaking, marked by a `!` before the
+	// colon of the subject, or a `BREAKING CHANGE:` footer in the body.
+	Breaking bool
+
+	// Description is the description of the change, following the colon of
+	// the subject.
+	Description string
+}
+
+// Parse parses the provided subject and body as a conventional commit,
+// reporting whether the subject follows the specification. The body may be
+// empty, i.e - when parsing a pull request title.
+func Parse(subject, body string) (Commit, bool) {
+	matches := reSubject.FindStringSubmatch(strings.TrimSpace(subject))
+	if matches == nil {
+		return Commit{}, false
+	}
+
+	return Commit{
+		Type:        strings.ToLower(matches[1]),
+		Scope:       strings.TrimSpace(matches[2]),
+		Breaking:    matches[3] == "!" || reBreakingFooter.MatchString(body),
+		Description: matches[4],
+	}, true
+}
@@ -0,0 +1,80 @@
+package conventional
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		want    Commit
+		wantOk  bool
+	}{
+		{
+			name:    "type",
+			subject: "fix: handle the empty list",
+			want:    Commit{Type: "fix", Description: "handle the empty list"},
+			wantOk:  true,
+		},
+		{
+			name:    "scope",
+			subject: "feat(api): add a new endpoint",
+			want:    Commit{Type: "feat", Scope: "api", Description: "add a new endpoint"},
+			wantOk:  true,
+		},
+		{
+			name:    "breaking marker",
+			subject: "feat(api)!: remove the old endpoint",
+			want:    Commit{Type: "feat", Scope: "api", Breaking: true, Description: "remove the old endpoint"},
+			wantOk:  true,
+		},
+		{
+			name:    "breaking footer",
+			subject: "refactor: rename the option",
+			body:    "Renames it.\n\nBREAKING CHANGE: the option was renamed",
+			want:    Commit{Type: "refactor", Breaking: true, Description: "rename the option"},
+			wantOk:  true,
+		},
+		{
+			name:    "hyphenated breaking footer",
+			subject: "refactor: rename the option",
+			body:    "BREAKING-CHANGE: the option was renamed",
+			want:    Commit{Type: "refactor", Breaking: true, Description: "rename the option"},
+			wantOk:  true,
+		},
+		{
+			name:    "uppercase type",
+			subject: "FIX: handle the empty list",
+			want:    Commit{Type: "fix", Description: "handle the empty list"},
+			wantOk:  true,
+		},
+		{
+			name:    "surrounding whitespace",
+			subject: "  docs( readme ):   fix a typo  ",
+			want:    Commit{Type: "docs", Scope: "readme", Description: "fix a typo"},
+			wantOk:  true,
+		},
+		{
+			name:    "breaking change mentioned in the body",
+			subject: "fix: handle the empty list",
+			body:    "This isn't a BREAKING CHANGE: at all",
+			want:    Commit{Type: "fix", Description: "handle the empty list"},
+			wantOk:  true,
+		},
+		{name: "no type", subject: "handle the empty list"},
+		{name: "no description", subject: "fix:"},
+		{name: "space in the type", subject: "bug fix: handle the empty list"},
+		{name: "empty", subject: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.subject, tt.body)
+			if ok != tt.wantOk {
+				t.Fatalf("Parse(%q) ok = %v, want %v", tt.subject, ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
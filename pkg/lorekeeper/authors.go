@@ -0,0 +1,94 @@
+package lorekeeper
+
+import (
+	"path"
+	"strings"
+)
+
+// defaultAnonymousName is the name used in place of anonymised logins when no
+// name has been configured.
+const defaultAnonymousName = "anonymous"
+
+// AuthorsConfig determines how pull request authors are attributed in the
+// release notes.
+type AuthorsConfig struct {
+	// Exclude is a list of logins (or glob patterns, i.e - `dependabot*`) that are
+	// omitted from the release notes entirely.
+	Exclude []string `yaml:"exclude"`
+
+	// Anonymize is a list of logins (or glob patterns) that are rendered
+	// without their login or avatar.
+	Anonymize []string `yaml:"anonymize"`
+
+	// AnonymousName is the name rendered in place of an anonymised login.
+	// Defaults to "anonymous".
+	AnonymousName string `yaml:"anonymousName"`
+}
+
+// apply removes excluded authors from the provided list, and anonymises any
+// authors that match the anonymize list.
+func (c AuthorsConfig) apply(authors []gitAuthor) []gitAuthor {
+	var applied []gitAuthor
+
+	for _, author := range authors {
+		switch {
+		case matchesLogin(c.Exclude, author.Login):
+			continue
+		case matchesLogin(c.Anonymize, author.Login):
+			applied = append(applied, gitAuthor{Login: c.anonymousName()})
+		default:
+			applied = append(applied, author)
+		}
+	}
+
+	return applied
+}
+
+// anonymousName returns the configured anonymous name, or the default if it
+// has not been configured.
+func (c AuthorsConfig) anonymousName() string {
+	if c.AnonymousName == "" {
+		return defaultAnonymousName
+	}
+	return c.AnonymousName
+}
+
+// matchesLogin reports whether the login matches any of the provided patterns.
+//
+// Logins are compared case-insensitively, as GitHub logins are not case
+// sensitive.
+func matchesLogin(patterns []string, login string) bool {
+	login = strings.ToLower(login)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if pattern == login {
+			return true
+		}
+		if matched, err := path.Match(pattern, login); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pullRequestAuthors returns the unique authors of the commits of the provided
+// pull request, in the order they first authored a commit, with the author
+// policy applied.
+func pullRequestAuthors(pullRequest gitPullRequest, config AuthorsConfig) []gitAuthor {
+	var (
+		authors []gitAuthor
+		seen    = map[string]bool{}
+	)
+	for _, commit := range pullRequest.Commits {
+		for _, author := range config.apply(commit.Authors) {
+			if seen[author.Login] {
+				continue
+			}
+			seen[author.Login] = true
+			authors = append(authors, author)
+		}
+	}
+	return authors
+}
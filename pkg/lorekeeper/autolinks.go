@@ -0,0 +1,108 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reAutolinkReference matches a reference to an issue or pull request,
+	// i.e - `#123`, `GH-123`, or the cross-repository `owner/repo#123`,
+	// capturing the reference, its owner and repository, if any, and its
+	// number, as either of the last two groups.
+	reAutolinkReference = regexp.MustCompile(`(?:^|[^\w/&#.-])((?:([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+))?#([0-9]+)|GH-([0-9]+))\b`)
+
+	// reLinked matches an inline markdown link or image, an autolink, or an
+	// HTML tag, whose contents are already linked.
+	reLinked = regexp.MustCompile(`!?\[[^\]]*\]\([^)]*\)|<[^>\s]+>`)
+)
+
+// bodyRepository returns the repository that the references and relative links
+// in the body of the provided pull request are relative to, from its URL, or
+// the repository of the provided commander, if known.
+func bodyRepository(ctx context.Context, cmd commander, pullRequest gitPullRequest) (Repository, bool) {
+	if repositoryURL, _, ok := strings.Cut(pullRequest.URL, "/pull/"); ok {
+		if repository, ok := parseRemoteURL(repositoryURL); ok {
+			return repository, true
+		}
+	}
+	return cmd.knownRepository()
+}
+
+// issueURL returns the URL of the issue or pull request of the provided number
+// in the repository. GitHub redirects the URLs of issues to pull requests, and
+// GitLab is recognised by its host.
+func (r Repository) issueURL(number string) string {
+	if strings.Contains(r.Host, "gitlab") {
+		return fmt.Sprintf("%s/-/issues/%s", r.URL(), number)
+	}
+	return fmt.Sprintf("%s/issues/%s", r.URL(), number)
+}
+
+// autolinkReferences rewrites the bare references to issues and pull requests
+// in the provided markdown, i.e - `#123`, `GH-123`, and `owner/repo#123`, into
+// links on the forge of the provided repository. References within code,
+// links, and URLs are left as they are.
+func autolinkReferences(markdown string, repository Repository) string {
+	var (
+		lines   = strings.Split(markdown, "\n")
+		inFence bool
+	)
+	for idx, line := range lines {
+		// Skip the contents of fenced code blocks.
+		if reFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		// Mask inline code, links, and URLs, so the references within them
+		// are left as they are.
+		masked := line
+		for _, reMask := range []*regexp.Regexp{reInlineCode, reLinked, reBareURL} {
+			masked = reMask.ReplaceAllStringFunc(masked, func(match string) string {
+				return strings.Repeat(" ", len(match))
+			})
+		}
+
+		// Link each reference, working backwards so the links don't move the
+		// positions of earlier matches.
+		references := reAutolinkReference.FindAllStringSubmatchIndex(masked, -1)
+		for i := len(references) - 1; i >= 0; i-- {
+			var (
+				reference = references[i]
+				target    = repository
+				number    string
+			)
+			if reference[4] != -1 {
+				target.Owner, target.Name = masked[reference[4]:reference[5]], masked[reference[6]:reference[7]]
+			}
+			if reference[8] != -1 {
+				number = masked[reference[8]:reference[9]]
+			} else {
+				number = masked[reference[10]:reference[11]]
+			}
+
+			start, end := reference[2], reference[3]
+			line = line[:start] + fmt.Sprintf("[%s](%s)", line[start:end], target.issueURL(number)) + line[end:]
+		}
+
+		lines[idx] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// autolinkBody returns the body of the provided pull request with its bare
+// references rewritten into links, as described by autolinkReferences. The
+// body is returned as it is if the repository isn't known.
+func autolinkBody(ctx context.Context, cmd commander, pullRequest gitPullRequest) string {
+	repository, ok := bodyRepository(ctx, cmd, pullRequest)
+	if !ok {
+		return pullRequest.Body
+	}
+	return autolinkReferences(pullRequest.Body, repository)
+}
@@ -0,0 +1,57 @@
+package lorekeeper
+
+import "testing"
+
+func TestAutolinkReferences(t *testing.T) {
+	github := Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"}
+	tests := []struct {
+		name       string
+		markdown   string
+		repository Repository
+		want       string
+	}{
+		{
+			name:     "number",
+			markdown: "Fixes #12.",
+			want:     "Fixes [#12](https://github.com/riftspire/lorekeeper/issues/12).",
+		},
+		{
+			name:     "GH prefix",
+			markdown: "See GH-12",
+			want:     "See [GH-12](https://github.com/riftspire/lorekeeper/issues/12)",
+		},
+		{
+			name:     "cross-repository",
+			markdown: "Follows up on acme/widgets#7",
+			want:     "Follows up on [acme/widgets#7](https://github.com/acme/widgets/issues/7)",
+		},
+		{
+			name:     "several",
+			markdown: "#1 and #2",
+			want:     "[#1](https://github.com/riftspire/lorekeeper/issues/1) and [#2](https://github.com/riftspire/lorekeeper/issues/2)",
+		},
+		{
+			name:       "gitlab",
+			markdown:   "Fixes #12",
+			repository: Repository{Host: "gitlab.com", Owner: "group/subgroup", Name: "project"},
+			want:       "Fixes [#12](https://gitlab.com/group/subgroup/project/-/issues/12)",
+		},
+		{name: "inline code", markdown: "Run `git show #12`", want: "Run `git show #12`"},
+		{name: "link", markdown: "[#12](https://example.com)", want: "[#12](https://example.com)"},
+		{name: "url", markdown: "https://example.com/page#12", want: "https://example.com/page#12"},
+		{name: "html entity", markdown: "&#12;", want: "&#12;"},
+		{name: "heading", markdown: "## Notes", want: "## Notes"},
+		{name: "fenced code", markdown: "```\n#12\n```", want: "```\n#12\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := tt.repository
+			if repository.isZero() {
+				repository = github
+			}
+			if got := autolinkReferences(tt.markdown, repository); got != tt.want {
+				t.Errorf("autolinkReferences(%q) =\n%s\nwant\n%s", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package lorekeeper
+
+import "strings"
+
+// BadgeConfig represents a badge, or emoji, rendered on the entries with a
+// label.
+type BadgeConfig struct {
+	// Label is the pull request label that the badge is rendered for.
+	Label string `yaml:"label"`
+
+	// Badge is the markdown rendered after the title of the entry, i.e - an
+	// emoji like "🧪", or inline code like "`beta`".
+	Badge string `yaml:"badge"`
+}
+
+// badges returns the badges of the provided pull request, in the order they
+// are configured.
+func badges(pullRequest gitPullRequest, config []BadgeConfig) []string {
+	var matched []string
+	for _, badge := range config {
+		if badge.Badge != "" && pullRequest.hasLabel(badge.Label) {
+			matched = append(matched, badge.Badge)
+		}
+	}
+	return matched
+}
+
+// badgesSuffix returns the badges of the provided pull request as they are
+// rendered after its title, i.e - " 🧪 `beta`".
+func badgesSuffix(pullRequest gitPullRequest, config []BadgeConfig) string {
+	matched := badges(pullRequest, config)
+	if len(matched) == 0 {
+		return ""
+	}
+	return " " + strings.Join(matched, " ")
+}
@@ -0,0 +1,73 @@
+package lorekeeper
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// BlockConfig represents a block of markdown rendered around the release
+// notes, from the configuration or a file.
+type BlockConfig struct {
+	// Text is the text/template of the block, passed the same structure as
+	// markdown templates, i.e - `{{ .TagName }}`.
+	Text string `yaml:"text"`
+
+	// Path is the path to a file of the text/template of the block, relative
+	// to the root of the working tree, read if Text is empty.
+	Path string `yaml:"path"`
+}
+
+// inWorktree returns the block with its path resolved from the root of the
+// working tree, as described by worktreePath, so that it is found when run in
+// a subdirectory.
+func (c BlockConfig) inWorktree(cmd commander) BlockConfig {
+	if c.Path != "" {
+		c.Path = worktreePath(context.Background(), cmd, c.Path)
+	}
+	return c
+}
+
+// parse reads and parses the template of the block, or returns nil if it
+// isn't configured. The name identifies the block in errors.
+func (c BlockConfig) parse(name string, config Config) (*template.Template, error) {
+	text := c.Text
+	if text == "" && c.Path != "" {
+		data, err := os.ReadFile(c.Path)
+		if err != nil {
+			return nil, &TemplateError{Path: c.Path, Err: err}
+		}
+		text, name = string(data), c.Path
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs(config)).Parse(text)
+	if err != nil {
+		return nil, &TemplateError{Path: name, Err: err}
+	}
+	return tmpl, nil
+}
+
+// render writes the block to the writer, followed by a blank line, if it is
+// configured. The name identifies the block in errors.
+func (c BlockConfig) render(w io.Writer, name string, notes releaseNotes, config Config) error {
+	tmpl, err := c.parse(name, config)
+	if err != nil || tmpl == nil {
+		return err
+	}
+
+	var block strings.Builder
+	if err := tmpl.Execute(&block, newJSONReleaseNotes(notes, config)); err != nil {
+		return &TemplateError{Path: tmpl.Name(), Err: err}
+	}
+	if strings.TrimSpace(block.String()) == "" {
+		return nil
+	}
+
+	_, err = io.WriteString(w, strings.TrimSpace(block.String())+"\n\n")
+	return err
+}
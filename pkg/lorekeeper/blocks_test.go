@@ -0,0 +1,29 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderJSONBlocks(t *testing.T) {
+	config := Config{
+		Header: BlockConfig{Text: "Install {{ .TagName }}."},
+		Footer: BlockConfig{Text: "Thanks!"},
+	}
+	var rendered bytes.Buffer
+	if err := renderJSON(&rendered, releaseNotes{TagName: "v1.0.0"}, config); err != nil {
+		t.Fatalf("renderJSON() error = %v", err)
+	}
+
+	var document struct {
+		Header string `json:"header"`
+		Footer string `json:"footer"`
+	}
+	if err := json.Unmarshal(rendered.Bytes(), &document); err != nil {
+		t.Fatalf("renderJSON() output isn't JSON: %v", err)
+	}
+	if document.Header != "Install v1.0.0." || document.Footer != "Thanks!" {
+		t.Errorf("renderJSON() header = %q, footer = %q, want %q, %q", document.Header, document.Footer, "Install v1.0.0.", "Thanks!")
+	}
+}
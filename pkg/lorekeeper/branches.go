@@ -0,0 +1,141 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+var (
+	errNoTags               = errors.New("the repository has no tags")
+	errReleaseBranchAPIOnly = errors.New("release branches need a local clone")
+)
+
+// isReleaseBranch reports whether the provided branch is one of the
+// maintained release branches, matching a name or glob pattern.
+func (c Config) isReleaseBranch(name string) bool {
+	for _, pattern := range c.ReleaseBranches {
+		if matched, _ := path.Match(pattern, name); matched || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reMergeQueueBranch matches the temporary branch that a GitHub merge queue
+// tests a group of pull requests on, capturing the branch that they're merged
+// into, i.e - `gh-readonly-queue/main/pr-123-<sha>`.
+var reMergeQueueBranch = regexp.MustCompile(`^gh-readonly-queue/(.+)/pr-[0-9]+-[0-9a-f]+$`)
+
+// trimBranchName removes the `refs/heads/` prefix from the provided branch
+// name, i.e - as provided by CI in `github.event.base_ref`. The temporary
+// branch of a merge queue is replaced with the branch that it merges into.
+func trimBranchName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "refs/heads/")
+	if matches := reMergeQueueBranch.FindStringSubmatch(name); matches != nil {
+		return matches[1]
+	}
+	return name
+}
+
+// resolveBranches normalises the branch names of the options, detecting the
+// current and default branches if they weren't provided.
+func resolveBranches(ctx context.Context, o *options) {
+	o.currentBranchName = trimBranchName(o.currentBranchName)
+	o.defaultBranchName = trimBranchName(o.defaultBranchName)
+
+	if o.currentBranchName == "" && !o.apiOnly {
+		o.currentBranchName = detectCurrentBranch(ctx, o.cmd)
+		log.Debug("Detected the current branch", "branch", o.currentBranchName)
+	}
+
+	if o.defaultBranchName == "" {
+		o.defaultBranchName = detectDefaultBranch(ctx, o.cmd, o.provider, o.remote)
+		log.Debug("Detected the default branch", "branch", o.defaultBranchName)
+	}
+
+	// Without a local clone there is no current branch, so the tag is assumed
+	// to be on the default branch.
+	if o.currentBranchName == "" && o.apiOnly {
+		o.currentBranchName = o.defaultBranchName
+	}
+}
+
+// detectDefaultBranch returns the default branch of the repository, as
+// reported by the forge, or by the HEAD of the remote (or of a bare
+// repository), or an empty string if none are known.
+func detectDefaultBranch(ctx context.Context, cmd commander, provider Provider, remote string) string {
+	// Ask the forge.
+	if name, err := provider.DefaultBranch(ctx); err == nil && strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name)
+	}
+
+	// Fall back to the HEAD of the remote, i.e - `origin/main`.
+	name, err := cmd.run(ctx, "git", "symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(name), remote+"/")
+	}
+
+	// Fall back to the HEAD of a bare repository, i.e - a mirror, which has no
+	// remote-tracking branches. The HEAD of the repository is shared by its
+	// linked worktrees, which each have their own HEAD, so it is read from the
+	// common directory.
+	commonDir, err := cmd.run(ctx, "git", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return ""
+	}
+	gitDir := "--git-dir=" + strings.TrimSpace(commonDir)
+	if bare, err := cmd.run(ctx, "git", gitDir, "rev-parse", "--is-bare-repository"); err != nil || strings.TrimSpace(bare) != "true" {
+		return ""
+	}
+	name, err = cmd.run(ctx, "git", gitDir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// detectCurrentBranch returns the branch checked out in the local repository,
+// or linked worktree, or an empty string if HEAD is detached.
+func detectCurrentBranch(ctx context.Context, cmd commander) string {
+	name, err := cmd.run(ctx, "git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// DetectLatestTag returns the most recent tag reachable from HEAD in the local
+// repository, or the most recently created tag on the forge in the API-only
+// mode, i.e - to offer as the tag to make the release notes for.
+func DetectLatestTag(ctx context.Context, opts ...Option) (string, error) {
+	o := newOptions(opts)
+
+	if o.apiOnly {
+		tagJSON, err := latestTag(ctx, o, nil)
+		if err != nil {
+			return "", &OperationError{Op: "detect the latest tag", Err: err}
+		}
+		var tag gitReference
+		if err := json.Unmarshal([]byte(tagJSON), &tag); err != nil || tag.TagName == "" {
+			return "", &OperationError{Op: "detect the latest tag", Err: errNoTags}
+		}
+		return tag.TagName, nil
+	}
+
+	// The tags may be missing from a shallow clone.
+	if err := ensureFullHistory(ctx, o); err != nil {
+		return "", err
+	}
+
+	tagName, err := o.cmd.run(ctx, "git", "describe", "--tags", "--abbrev=0")
+	if err != nil {
+		return "", &OperationError{Op: "detect the latest tag", Err: err}
+	}
+	return strings.TrimSpace(tagName), nil
+}
@@ -0,0 +1,115 @@
+package lorekeeper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BumpConfig determines which files have their version strings updated by
+// BumpVersionFiles.
+type BumpConfig struct {
+	// Files is the list of files whose version strings are updated.
+	Files []BumpFileConfig `yaml:"files"`
+}
+
+// BumpFileConfig represents a file whose version string is updated.
+type BumpFileConfig struct {
+	// Path is the path to the file.
+	Path string `yaml:"path"`
+
+	// Pattern is a regex matching the version strings of the file, whose first
+	// capture group is replaced with the new version. Defaults to a pattern
+	// based on the name of the file, for VERSION, package.json,
+	// pyproject.toml, and Chart.yaml files.
+	Pattern string `yaml:"pattern"`
+}
+
+var (
+	// defaultBumpPatterns maps the names of well-known files to the patterns
+	// matching their version strings.
+	defaultBumpPatterns = map[string]string{
+		"VERSION":        `\A\s*(\S+)`,
+		"package.json":   `(?m)^(?:\t| {1,4})"version"\s*:\s*"([^"]*)"`,
+		"pyproject.toml": `(?m)^version\s*=\s*"([^"]*)"`,
+		"Chart.yaml":     `(?m)^(?:version|appVersion):\s*"?([^"\s]*)"?`,
+	}
+
+	errBumpPatternUnknown = errors.New("no pattern configured, and no default pattern for the file name")
+	errBumpNoMatch        = errors.New("no version string matched the pattern")
+)
+
+// compile returns the compiled pattern of the file, falling back to the
+// default pattern for its name.
+func (c BumpFileConfig) compile() (*regexp.Regexp, error) {
+	pattern := c.Pattern
+	if pattern == "" {
+		pattern = defaultBumpPatterns[filepath.Base(c.Path)]
+	}
+	if pattern == "" {
+		return nil, &BumpError{Path: c.Path, Err: errBumpPatternUnknown}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &BumpError{Path: c.Path, Err: err}
+	}
+	if re.NumSubexp() < 1 {
+		return nil, &BumpError{Path: c.Path, Err: errors.New("the pattern has no capture group")}
+	}
+
+	return re, nil
+}
+
+// BumpVersionFiles updates the version strings of the configured files to match
+// the provided tag, i.e - "1.2.0" for "v1.2.0", returning the paths of the files
+// that were changed.
+func BumpVersionFiles(tagName string, config BumpConfig) ([]string, error) {
+	var (
+		version = versionFromTag(tagName)
+		changed []string
+	)
+
+	for _, file := range config.Files {
+		re, err := file.compile()
+		if err != nil {
+			return changed, err
+		}
+
+		// Read the file.
+		content, err := os.ReadFile(file.Path)
+		if err != nil {
+			return changed, &BumpError{Path: file.Path, Err: err}
+		}
+
+		// Replace the first capture group of every match with the version.
+		matches := re.FindAllSubmatchIndex(content, -1)
+		if len(matches) == 0 {
+			return changed, &BumpError{Path: file.Path, Err: errBumpNoMatch}
+		}
+
+		var (
+			bumped strings.Builder
+			last   int
+		)
+		for _, match := range matches {
+			bumped.Write(content[last:match[2]])
+			bumped.WriteString(version)
+			last = match[3]
+		}
+		bumped.Write(content[last:])
+
+		// Write the file, if it has changed.
+		if bumped.String() == string(content) {
+			continue
+		}
+		if err := os.WriteFile(file.Path, []byte(bumped.String()), 0o644); err != nil {
+			return changed, &BumpError{Path: file.Path, Err: err}
+		}
+		changed = append(changed, file.Path)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,252 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// defaultCacheTTL is how long the cached responses are used for when no
+	// TTL has been configured.
+	defaultCacheTTL = 7 * 24 * time.Hour
+
+	// cacheVersion is the version of the layout, and contents, of the cache,
+	// which keys the cached responses, so that those of an earlier version,
+	// i.e - from before the pull requests were parsed differently, aren't
+	// used. Bump it when either changes.
+	cacheVersion = "v1"
+)
+
+// fieldsKey returns a short hash of the fields of the pull requests retrieved
+// from the forge, which keys the cached pull requests, so that a change of the
+// fields invalidates them.
+func fieldsKey() string {
+	hash := fnv.New32a()
+	hash.Write([]byte(pullRequestFields))
+	return strconv.FormatUint(uint64(hash.Sum32()), 36)
+}
+
+// CacheConfig determines whether, and for how long, the details of the pull
+// requests retrieved from the forge are cached between runs.
+type CacheConfig struct {
+	// Enabled caches the details of the pull requests on disk.
+	Enabled bool `yaml:"enabled"`
+
+	// Dir is the directory the cache is kept in. Defaults to `lorekeeper` in
+	// the cache directory of the user, i.e - `~/.cache/lorekeeper`.
+	Dir string `yaml:"dir"`
+
+	// TTL is how long a cached response is used for, i.e - `24h`, before it is
+	// retrieved again. Defaults to 7 days.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// dir returns the configured cache directory, or the default if it has not
+// been configured.
+func (c CacheConfig) dir() (string, error) {
+	if c.Dir != "" {
+		return c.Dir, nil
+	}
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", &CacheError{Err: err}
+	}
+	return filepath.Join(userCacheDir, "lorekeeper"), nil
+}
+
+// ttl returns the configured TTL, or the default if it has not been
+// configured.
+func (c CacheConfig) ttl() time.Duration {
+	if c.TTL == 0 {
+		return defaultCacheTTL
+	}
+	return c.TTL
+}
+
+// validate checks the cache configuration.
+func (c CacheConfig) validate() error {
+	if c.TTL < 0 {
+		return &CacheError{Err: errors.New("the ttl cannot be negative")}
+	}
+	return nil
+}
+
+// CacheStats describes the contents of the cache.
+type CacheStats struct {
+	// Dir is the directory the cache is kept in.
+	Dir string
+
+	// Entries is the number of cached responses.
+	Entries int
+
+	// Expired is the number of cached responses older than the TTL.
+	Expired int
+
+	// Size is the total size of the cached responses, in bytes.
+	Size int64
+}
+
+// GetCacheStats returns the number, and size, of the cached responses in the
+// configured cache directory.
+func GetCacheStats(config CacheConfig) (CacheStats, error) {
+	dir, err := config.dir()
+	if err != nil {
+		return CacheStats{}, err
+	}
+
+	stats := CacheStats{Dir: dir}
+	err = walkCache(dir, func(path string, info fs.FileInfo) error {
+		stats.Entries++
+		stats.Size += info.Size()
+		if time.Since(info.ModTime()) > config.ttl() {
+			stats.Expired++
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// ClearCache removes the cached responses from the configured cache
+// directory, or only those older than the TTL if expiredOnly is set. It
+// returns the number of responses removed.
+func ClearCache(config CacheConfig, expiredOnly bool) (int, error) {
+	dir, err := config.dir()
+	if err != nil {
+		return 0, err
+	}
+
+	var removed int
+	err = walkCache(dir, func(path string, info fs.FileInfo) error {
+		if expiredOnly && time.Since(info.ModTime()) <= config.ttl() {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walkCache calls fn with each cached response in the cache directory, which
+// may not exist yet.
+func walkCache(dir string, fn func(path string, info fs.FileInfo) error) error {
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, info)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return &CacheError{Err: err}
+	}
+	return nil
+}
+
+// cachingProvider is a Provider that caches the details of the pull requests
+// retrieved from the wrapped Provider on disk, as they rarely change once
+// merged.
+type cachingProvider struct {
+	Provider
+	config     CacheConfig
+	repository Repository
+}
+
+// newCachingProvider returns a Provider that caches the details of the pull
+// requests retrieved from the provided Provider.
+func newCachingProvider(provider Provider, config CacheConfig) Provider {
+	return cachingProvider{Provider: provider, config: config}
+}
+
+func (p cachingProvider) bind(cmd commander) Provider {
+	p.Provider = bindProvider(p.Provider, cmd)
+	p.repository = cmd.repository
+	return p
+}
+
+func (p cachingProvider) PullRequest(ctx context.Context, number string) (string, error) {
+	// The responses are only cached when the repository is known, so that
+	// those of different repositories are kept apart.
+	repository := p.repository
+	if repository.isZero() {
+		return p.Provider.PullRequest(ctx, number)
+	}
+	dir, err := p.config.dir()
+	if err != nil {
+		return p.Provider.PullRequest(ctx, number)
+	}
+	path := filepath.Join(dir, cacheVersion, repository.Host, repository.Owner, repository.Name, "pull-"+number+"-"+fieldsKey()+".json")
+
+	// Use the cached response, if it hasn't expired.
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) <= p.config.ttl() {
+		if data, err := os.ReadFile(path); err == nil {
+			log.Debug("Using the cached pull request", "number", number, "path", path)
+			return string(data), nil
+		}
+	}
+
+	pullRequestJSON, err := p.Provider.PullRequest(ctx, number)
+	if err != nil {
+		return "", err
+	}
+
+	// Only cache the merged pull requests, as the open ones, i.e - checked by
+	// `check-pr`, are still changing.
+	var merged struct {
+		MergedAt time.Time `json:"mergedAt"`
+	}
+	if err := json.Unmarshal([]byte(pullRequestJSON), &merged); err != nil || merged.MergedAt.IsZero() {
+		return pullRequestJSON, nil
+	}
+
+	// Cache the response, failing to do so being no reason to stop.
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err == nil {
+		err = writeCacheFile(path, []byte(pullRequestJSON))
+	}
+	if err != nil {
+		log.Debug("Failed to cache the pull request", "number", number, "err", err)
+	}
+
+	return pullRequestJSON, nil
+}
+
+// writeCacheFile writes a cached response to the provided path, through a
+// temporary file in the same directory that replaces it, so that a concurrent
+// run never reads a partially written response.
+func writeCacheFile(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,88 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+)
+
+// pullRequestProvider is a Provider that returns the provided pull request.
+type pullRequestProvider struct {
+	Provider
+	pullRequestJSON string
+}
+
+func (p pullRequestProvider) PullRequest(ctx context.Context, number string) (string, error) {
+	return p.pullRequestJSON, nil
+}
+
+func TestCachingProviderPullRequest(t *testing.T) {
+	const pullRequestJSON = `{"number":1,"mergedAt":"2026-01-02T00:00:00Z"}`
+	repository := Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"}
+
+	t.Run("cached", func(t *testing.T) {
+		dir := t.TempDir()
+		p := cachingProvider{
+			Provider:   pullRequestProvider{pullRequestJSON: pullRequestJSON},
+			config:     CacheConfig{Enabled: true, Dir: dir},
+			repository: repository,
+		}
+
+		if _, err := p.PullRequest(context.Background(), "1"); err != nil {
+			t.Fatalf("PullRequest() error = %v", err)
+		}
+
+		// Check only the response is left behind, and not the temporary file
+		// it was written through.
+		entries, err := os.ReadDir(filepath.Join(dir, cacheVersion, repository.Host, repository.Owner, repository.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".json" {
+			t.Fatalf("cache entries = %v, want a single response", entries)
+		}
+
+		// Check the cached response is used, rather than the provider.
+		p.Provider = pullRequestProvider{}
+		got, err := p.PullRequest(context.Background(), "1")
+		if err != nil || got != pullRequestJSON {
+			t.Errorf("PullRequest() = %q, %v, want the cached %q", got, err, pullRequestJSON)
+		}
+	})
+
+	t.Run("unwritable", func(t *testing.T) {
+		// Use a file as the cache directory, so the response can't be cached.
+		dir := filepath.Join(t.TempDir(), "cache")
+		if err := os.WriteFile(dir, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p := cachingProvider{
+			Provider:   pullRequestProvider{pullRequestJSON: pullRequestJSON},
+			config:     CacheConfig{Enabled: true, Dir: dir},
+			repository: repository,
+		}
+
+		var output bytes.Buffer
+		logger := log.Default()
+		level := logger.GetLevel()
+		logger.SetOutput(&output)
+		logger.SetLevel(log.DebugLevel)
+		t.Cleanup(func() {
+			logger.SetOutput(os.Stderr)
+			logger.SetLevel(level)
+		})
+
+		got, err := p.PullRequest(context.Background(), "1")
+		if err != nil || got != pullRequestJSON {
+			t.Errorf("PullRequest() = %q, %v, want %q", got, err, pullRequestJSON)
+		}
+		if want := "Failed to cache the pull request"; !strings.Contains(output.String(), want) {
+			t.Errorf("log output = %q, want it to contain %q", output.String(), want)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var errCandidatesAPIOnly = errors.New("earlier release candidates need a local clone")
+
+// CandidateEntries determines how the pull requests already published in the
+// earlier release candidates of the same release are handled.
+type CandidateEntries string
+
+const (
+	// CandidateEntriesInclude includes the pull requests as any other. It is
+	// the default.
+	CandidateEntriesInclude CandidateEntries = "include"
+
+	// CandidateEntriesExclude leaves the pull requests out, so that only what
+	// is new since the previous release candidate is listed.
+	CandidateEntriesExclude CandidateEntries = "exclude"
+
+	// CandidateEntriesMark includes the pull requests, marked with the
+	// release candidate they were first published in.
+	CandidateEntriesMark CandidateEntries = "mark"
+)
+
+// GetCandidateEntries returns the ways of handling the pull requests already
+// published in earlier release candidates.
+func GetCandidateEntries() []CandidateEntries {
+	return []CandidateEntries{CandidateEntriesInclude, CandidateEntriesExclude, CandidateEntriesMark}
+}
+
+// validate checks the handling of the pull requests.
+func (c CandidateEntries) validate() error {
+	if c != "" && !slices.Contains(GetCandidateEntries(), c) {
+		return &CandidateEntriesInvalidError{Value: c}
+	}
+	return nil
+}
+
+// ReleaseCandidatesConfig determines the content of the release notes of
+// release candidates.
+type ReleaseCandidatesConfig struct {
+	// Published determines how the pull requests already published in the
+	// earlier release candidates of the same release are handled, either
+	// "include" (default), "exclude", or "mark". Needs a local clone.
+	Published CandidateEntries `yaml:"published"`
+
+	// Collapse lists everything since the previous release in the release
+	// notes of a final release, once each, marked with the release candidate
+	// it was first published in. Needs a local clone.
+	Collapse bool `yaml:"collapse"`
+}
+
+// earlierCandidates returns the release candidates reachable from the provided
+// tag, other than the tag itself, since the previous release, oldest first,
+// along with the previous release, if any.
+func earlierCandidates(ctx context.Context, cmd commander, tagName string, reReleaseCandidate *regexp.Regexp) ([]gitReference, gitReference, error) {
+	tags, err := cmd.run(ctx, "git",
+		"for-each-ref", "refs/tags",
+		"--merged="+tagName,
+		"--sort=-creatordate",
+		tagFormat,
+	)
+	if err != nil {
+		return nil, gitReference{}, err
+	}
+
+	// Walk back through the tags, newest first, until the previous release.
+	var (
+		candidates []gitReference
+		release    gitReference
+	)
+	for tagJSON := range strings.SplitSeq(strings.TrimSpace(tags), "\n") {
+		if tagJSON == "" {
+			continue
+		}
+		var tag gitReference
+		if err := json.Unmarshal([]byte(tagJSON), &tag); err != nil {
+			return nil, gitReference{}, err
+		}
+		if tag.TagName == tagName {
+			continue
+		}
+		if !reReleaseCandidate.MatchString(tag.TagName) {
+			release = tag
+			break
+		}
+		candidates = append(candidates, tag)
+	}
+	slices.Reverse(candidates)
+
+	return candidates, release, nil
+}
+
+// publishedCandidates returns the earliest of the earlier release candidates
+// of the same release that each pull request was published in, by number.
+func publishedCandidates(ctx context.Context, o options, tagName string, reReleaseCandidate *regexp.Regexp, location *time.Location) (map[string]string, error) {
+	if o.apiOnly {
+		return nil, errCandidatesAPIOnly
+	}
+
+	candidates, release, err := earlierCandidates(ctx, o.cmd, tagName, reReleaseCandidate)
+	if err != nil {
+		return nil, err
+	}
+	return candidatePullRequests(ctx, o, candidates, release, location)
+}
+
+// candidatePullRequests returns the earliest of the provided release
+// candidates, oldest first, that each pull request was published in, by
+// number, since the provided previous release. With the timestamp strategy,
+// the pull requests of a release candidate are those merged after the one
+// before it was published, but not after it was.
+func candidatePullRequests(ctx context.Context, o options, candidates []gitReference, release gitReference, location *time.Location) (map[string]string, error) {
+	// List the pull requests merged since each reference, with the timestamp
+	// strategy.
+	mergedSince := func(ref gitReference) (map[string]bool, error) {
+		numbers, err := o.provider.MergedPullRequests(ctx, ref.PublishedAt.In(location).Format(time.RFC3339))
+		if err != nil {
+			return nil, err
+		}
+		merged := map[string]bool{}
+		for number := range strings.SplitSeq(numbers, "\n") {
+			merged[number] = true
+		}
+		return merged, nil
+	}
+	var (
+		previousMerged map[string]bool
+		err            error
+	)
+	if o.config.Strategy == StrategyTimestamp {
+		if previousMerged, err = mergedSince(release); err != nil {
+			return nil, err
+		}
+	}
+
+	// List the pull requests of each release candidate, since the one before.
+	var (
+		published = map[string]string{}
+		previous  = release.TagName
+	)
+	for _, candidate := range candidates {
+		var numbers []string
+		if o.config.Strategy == StrategyTimestamp {
+			merged, err := mergedSince(candidate)
+			if err != nil {
+				return nil, err
+			}
+			for number := range previousMerged {
+				if !merged[number] {
+					numbers = append(numbers, number)
+				}
+			}
+			previousMerged = merged
+		} else {
+			list, err := mergeBasePullRequests(ctx, o, previous, candidate.TagName)
+			if err != nil {
+				return nil, err
+			}
+			numbers = strings.Split(list, "\n")
+		}
+		for _, number := range numbers {
+			if _, ok := published[number]; number != "" && !ok {
+				published[number] = candidate.TagName
+			}
+		}
+		previous = candidate.TagName
+	}
+
+	return published, nil
+}
+
+// dedupeCandidates excludes, or marks, the listed pull requests already
+// published in the earlier release candidates of the same release, as
+// configured.
+func dedupeCandidates(ctx context.Context, o options, tagName string, reReleaseCandidate *regexp.Regexp, location *time.Location, listing *pullRequestListing) error {
+	handling := o.config.ReleaseCandidates.Published
+	if err := handling.validate(); err != nil {
+		return err
+	}
+	if handling == "" || handling == CandidateEntriesInclude {
+		return nil
+	}
+
+	published, err := publishedCandidates(ctx, o, tagName, reReleaseCandidate, location)
+	if err != nil {
+		return &OperationError{Op: "find the earlier release candidates", Ref: tagName, Err: err}
+	}
+
+	if handling == CandidateEntriesMark {
+		listing.published = published
+		return nil
+	}
+
+	var numbers []string
+	for number := range strings.SplitSeq(listing.numbers, "\n") {
+		if _, ok := published[number]; !ok {
+			numbers = append(numbers, number)
+		}
+	}
+	listing.numbers = strings.Join(numbers, "\n")
+	if listing.numbers == "" {
+		return &NoPullRequestsFoundError{Mode: o.mode, LatestRef: listing.latestRef}
+	}
+	return nil
+}
+
+// collapseCandidates lists the pull requests of the provided final release
+// since the previous release, with the configured strategy, as described by
+// ReleaseCandidatesConfig.Collapse.
+func collapseCandidates(ctx context.Context, o options, tagName string, reReleaseCandidate *regexp.Regexp, location *time.Location) (pullRequestListing, error) {
+	if o.apiOnly {
+		return pullRequestListing{}, &OperationError{Op: "find the earlier release candidates", Ref: tagName, Err: errCandidatesAPIOnly}
+	}
+
+	candidates, release, err := earlierCandidates(ctx, o.cmd, tagName, reReleaseCandidate)
+	if err != nil {
+		return pullRequestListing{}, &OperationError{Op: "find the earlier release candidates", Ref: tagName, Err: err}
+	}
+
+	// List everything since the previous release, once each.
+	numbers, err := pullRequestsSince(ctx, o, release, tagName, location)
+	if err != nil {
+		return pullRequestListing{}, &OperationError{Op: "list the merged pull requests", Ref: release.TagName, Err: err}
+	}
+	if numbers == "" {
+		return pullRequestListing{}, &NoPullRequestsFoundError{Mode: o.mode, LatestRef: release}
+	}
+
+	// Mark each with the release candidate it was first published in.
+	published, err := candidatePullRequests(ctx, o, candidates, release, location)
+	if err != nil {
+		return pullRequestListing{}, &OperationError{Op: "list the pull requests of the release candidates", Ref: tagName, Err: err}
+	}
+
+	listing := pullRequestListing{
+		numbers:   numbers,
+		latestRef: release,
+		published: published,
+	}
+	for _, candidate := range candidates {
+		listing.candidates = append(listing.candidates, candidate.TagName)
+	}
+	return listing, nil
+}
+
+// renderMarkdownCandidates writes the release candidates collapsed into a
+// final release to the writer as markdown, if any.
+func renderMarkdownCandidates(w io.Writer, candidates []string) {
+	if len(candidates) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "_Collects the changes of the release candidates %s._\n\n", strings.Join(candidates, ", "))
+}
@@ -0,0 +1,197 @@
+package lorekeeper
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// defaultCategory is the category assigned to pull requests that do not match
+// any of the configured categories. It is always ordered last.
+var defaultCategory = CategoryConfig{
+	Title:  "Other Changes",
+	Weight: math.MaxInt,
+}
+
+// CategoryConfig represents a section of the release notes that pull requests
+// are assigned to.
+type CategoryConfig struct {
+	// Title is the title of the category.
+	Title string `yaml:"title"`
+
+	// Labels is the list of pull request labels that assign a pull request to
+	// this category. It is shorthand for a rule matching any of the labels,
+	// with a priority of 0.
+	Labels []string `yaml:"labels"`
+
+	// Rules is the list of rules that assign a pull request to this category.
+	Rules []CategoryRule `yaml:"rules"`
+
+	// Weight determines the order of the categories, with lower weights
+	// ordered first.
+	Weight int `yaml:"weight"`
+}
+
+// CategoryRule represents a set of conditions that assign a pull request to a
+// category. A pull request matches the rule if it satisfies every condition
+// that has been set.
+type CategoryRule struct {
+	// Labels matches pull requests that have any of the labels.
+	Labels []string `yaml:"labels"`
+
+	// Title matches pull requests whose title matches the regular expression.
+	Title string `yaml:"title"`
+
+	// Branch matches pull requests whose head branch starts with the prefix,
+	// i.e - `feature/`.
+	Branch string `yaml:"branch"`
+
+	// Files matches pull requests that touch any file matching any of the glob
+	// patterns, i.e - `docs/**`.
+	Files []string `yaml:"files"`
+
+	// Priority determines which rule wins when a pull request matches rules
+	// from multiple categories, with higher priorities winning. Rules with the
+	// same priority are resolved in the order they are configured.
+	Priority int `yaml:"priority"`
+}
+
+// compiledRule is a CategoryRule with its patterns compiled, along with the
+// category that it assigns pull requests to.
+type compiledRule struct {
+	CategoryRule
+	category CategoryConfig
+	reTitle  *regexp.Regexp
+	reFiles  []*regexp.Regexp
+}
+
+// categoriser assigns pull requests to categories.
+type categoriser struct {
+	rules []compiledRule
+}
+
+// newCategoriser compiles the rules of the provided categories into a
+// categoriser.
+func newCategoriser(categories []CategoryConfig) (*categoriser, error) {
+	var c categoriser
+
+	for _, category := range categories {
+		// The labels shorthand is a rule of its own.
+		rules := category.Rules
+		if len(category.Labels) > 0 {
+			rules = append([]CategoryRule{{Labels: category.Labels}}, rules...)
+		}
+
+		for _, rule := range rules {
+			compiled := compiledRule{CategoryRule: rule, category: category}
+
+			if rule.Title != "" {
+				reTitle, err := regexp.Compile(rule.Title)
+				if err != nil {
+					return nil, &CategoryRuleInvalidError{Category: category.Title, Err: err}
+				}
+				compiled.reTitle = reTitle
+			}
+
+			for _, pattern := range rule.Files {
+				reFile, err := globToRegexp(pattern)
+				if err != nil {
+					return nil, &CategoryRuleInvalidError{Category: category.Title, Err: err}
+				}
+				compiled.reFiles = append(compiled.reFiles, reFile)
+			}
+
+			c.rules = append(c.rules, compiled)
+		}
+	}
+
+	return &c, nil
+}
+
+// categorise returns the category of the highest priority rule that the pull
+// request matches, or the default category if it matches none of them.
+func (c *categoriser) categorise(pullRequest gitPullRequest) CategoryConfig {
+	var (
+		matched  = defaultCategory
+		priority = math.MinInt
+	)
+
+	for _, rule := range c.rules {
+		if rule.Priority > priority && rule.matches(pullRequest) {
+			matched = rule.category
+			priority = rule.Priority
+		}
+	}
+
+	return matched
+}
+
+// matches reports whether the pull request satisfies every condition of the
+// rule.
+func (r compiledRule) matches(pullRequest gitPullRequest) bool {
+	if len(r.Labels) > 0 && !matchesAny(r.Labels, pullRequest.hasLabel) {
+		return false
+	}
+
+	if r.reTitle != nil && !r.reTitle.MatchString(pullRequest.Title) {
+		return false
+	}
+
+	if r.Branch != "" && !strings.HasPrefix(pullRequest.HeadRefName, r.Branch) {
+		return false
+	}
+
+	if len(r.reFiles) > 0 {
+		touched := func(reFile *regexp.Regexp) bool {
+			for _, file := range pullRequest.Files {
+				if reFile.MatchString(file.Path) {
+					return true
+				}
+			}
+			return false
+		}
+		if !matchesAny(r.reFiles, touched) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAny reports whether the match function returns true for any of the
+// provided values.
+func matchesAny[T any](values []T, match func(T) bool) bool {
+	for _, value := range values {
+		if match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp converts a glob pattern into a regular expression. In addition
+// to the `*` and `?` wildcards, `**` matches any number of directories.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,59 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// normaliseReleaseNotes normalises the provided release notes in place, so that
+// generating them repeatedly produces identical output, regardless of the
+// order that the forge returned the details in, or the local timezone.
+func normaliseReleaseNotes(notes *releaseNotes, location *time.Location) {
+	// Normalise the timestamps to the configured timezone, at a precision of
+	// seconds.
+	notes.Date = notes.Date.In(location).Truncate(time.Second)
+	notes.PreviousRef.PublishedAt = notes.PreviousRef.PublishedAt.In(location)
+	for i := range notes.PullRequests {
+		notes.PullRequests[i].MergedAt = notes.PullRequests[i].MergedAt.In(location)
+	}
+	for i := range notes.Advisories {
+		notes.Advisories[i].PublishedAt = notes.Advisories[i].PublishedAt.In(location)
+	}
+
+	// Order the security advisories by the date they were published, then by
+	// their identifier.
+	slices.SortStableFunc(notes.Advisories, func(a, b securityAdvisory) int {
+		return cmp.Or(
+			a.PublishedAt.Compare(b.PublishedAt),
+			strings.Compare(a.GHSAID, b.GHSAID),
+		)
+	})
+}
+
+// checkDeterministic renders both the provided release notes and their
+// regenerated counterpart, returning an error if the output differs.
+func checkDeterministic(ctx context.Context, cmd commander, notes releaseNotes, regenerated releaseNotes, config Config) error {
+	var generated, again bytes.Buffer
+	if err := config.Format.render(ctx, cmd, &generated, notes, config); err != nil {
+		return err
+	}
+	if err := config.Format.render(ctx, cmd, &again, regenerated, config); err != nil {
+		return err
+	}
+
+	// Output the differences in the error.
+	var diff strings.Builder
+	changed, err := writeUnifiedDiff(&diff, "generated/"+notes.TagName, "regenerated/"+notes.TagName, generated.String(), again.String())
+	if err != nil {
+		return err
+	}
+	if changed {
+		return &NondeterministicOutputError{TagName: notes.TagName, Diff: diff.String()}
+	}
+
+	return nil
+}
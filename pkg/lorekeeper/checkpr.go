@@ -0,0 +1,87 @@
+package lorekeeper
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/riftspire/lorekeeper/pkg/conventional"
+)
+
+// CheckPullRequest checks that the provided pull request will produce a sane
+// entry in the release notes, because it is assigned one of the configured
+// categories, i.e - by a label, has a conventional commit title, or adds a
+// fragment file, when fragments are configured. Pull requests excluded from
+// the release notes by the filter pass too.
+//
+// It returns a description of the first signal found, i.e - for the output
+// of a CI check, or a PullRequestSignalError if there is none.
+func CheckPullRequest(ctx context.Context, number int, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	config := o.config
+
+	// Check the title rewrite rules, so that the rewritten title is checked.
+	if o.titleRewritesErr != nil {
+		return "", o.titleRewritesErr
+	}
+
+	pullRequest, err := fetchPullRequest(ctx, o, strconv.Itoa(number))
+	if err != nil {
+		return "", err
+	}
+
+	// Pull requests left out of the release notes need no entry.
+	filter, err := newEntryFilter(config.Filter)
+	if err != nil {
+		return "", err
+	}
+	included, err := filter.includes(pullRequest, config.Authors)
+	if err != nil {
+		return "", err
+	}
+	if !included {
+		return "excluded from the release notes by the filter", nil
+	}
+
+	// Follow-ups are folded into the entry of the pull request they follow up
+	// on.
+	if config.Fixups.enabled() {
+		reTitle, err := config.Fixups.titlePattern()
+		if err != nil {
+			return "", err
+		}
+		if number, ok := fixupParent(pullRequest, config.Fixups, reTitle); ok {
+			return "follows up on #" + strconv.Itoa(number), nil
+		}
+	}
+
+	// Check for each of the signals, in turn, recording those missing.
+	var missing []string
+	if len(config.Categories) > 0 {
+		categoriser, err := newCategoriser(config.Categories)
+		if err != nil {
+			return "", err
+		}
+		if category := categoriser.categorise(pullRequest); category.Title != defaultCategory.Title {
+			return "assigned the " + strconv.Quote(category.Title) + " category", nil
+		}
+		missing = append(missing, "category label")
+	}
+
+	if title, ok := conventional.Parse(pullRequest.Title, ""); ok {
+		return "conventional title of type " + strconv.Quote(title.Type), nil
+	}
+	missing = append(missing, "conventional title")
+
+	if config.Fragments.Format != "" {
+		dir := strings.TrimSuffix(config.Fragments.dir(), "/") + "/"
+		for _, file := range pullRequest.Files {
+			if strings.HasPrefix(file.Path, dir) && file.Additions > 0 {
+				return "adds the fragment " + file.Path, nil
+			}
+		}
+		missing = append(missing, "notes fragment in "+dir)
+	}
+
+	return "", &PullRequestSignalError{Number: number, Missing: missing}
+}
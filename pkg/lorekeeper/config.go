@@ -0,0 +1,276 @@
+package lorekeeper
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigPath is the path of the configuration file that is loaded when
+// no path is explicitly provided.
+const DefaultConfigPath = ".lorekeeper.yaml"
+
+// Config represents the contents of a lorekeeper configuration file.
+type Config struct {
+	// Authors determines how pull request authors are attributed in the
+	// release notes.
+	Authors AuthorsConfig `yaml:"authors"`
+
+	// Categories is the list of categories that pull requests are assigned
+	// to.
+	Categories []CategoryConfig `yaml:"categories"`
+
+	// GenerateNotes determines whether the release notes generated by GitHub
+	// are merged, for their categories and new contributors.
+	GenerateNotes GenerateNotesConfig `yaml:"generateNotes"`
+
+	// Fragments determines whether the entries are read from fragment files,
+	// added alongside the changes, instead of from the pull request bodies.
+	Fragments FragmentsConfig `yaml:"fragments"`
+
+	// Sort determines the order of the release note entries.
+	Sort SortConfig `yaml:"sort"`
+
+	// ReleaseBranches is the list of names, or glob patterns, of the
+	// long-lived release branches, i.e - `1.x` or `release/*`. Tags on them
+	// are released like those on the default branch, compared with the
+	// previous tag of the same branch.
+	ReleaseBranches []string `yaml:"releaseBranches"`
+
+	// ReleaseCandidates determines the content of the release notes of
+	// release candidates.
+	ReleaseCandidates ReleaseCandidatesConfig `yaml:"releaseCandidates"`
+
+	// Train determines the schedule of the release train, for the train mode.
+	Train TrainConfig `yaml:"train"`
+
+	// GoReleaser determines how the release notes are handed to GoReleaser.
+	GoReleaser GoReleaserConfig `yaml:"goreleaser"`
+
+	// GoModule determines whether the tags of a Go module are checked against
+	// the major version suffix of its module path.
+	GoModule GoModuleConfig `yaml:"goModule"`
+
+	// Strategy is how the pull requests merged since the previous release are
+	// found, either "merge-base" (default), from the commit graph, or
+	// "timestamp", from the date the previous release was published.
+	Strategy Strategy `yaml:"strategy"`
+
+	// Filter is a CEL expression, evaluated for each pull request, that
+	// excludes the pull request from the release notes when false, i.e -
+	// `pr.labels.exists(l, l == "public") && !pr.draft`.
+	Filter string `yaml:"filter"`
+
+	// GroupByScope sub-groups the entries within each category by their
+	// conventional commit scope, i.e - `feat(api): ...` under "api".
+	GroupByScope bool `yaml:"groupByScope"`
+
+	// StripPrefixes determines whether the conventional commit prefixes of the
+	// titles are stripped from the displayed entries.
+	StripPrefixes StripPrefixesConfig `yaml:"stripPrefixes"`
+
+	// NormaliseTitles determines whether the displayed titles are normalised,
+	// so that they read consistently across the entries.
+	NormaliseTitles NormaliseTitlesConfig `yaml:"normaliseTitles"`
+
+	// TitleRewrites is the list of rules rewriting the titles of the entries,
+	// in turn, before they are categorised, filtered, or rendered, i.e - to
+	// strip ticket prefixes.
+	TitleRewrites []TitleRewriteConfig `yaml:"titleRewrites"`
+
+	// Stacks determines whether stacked pull requests are nested beneath the
+	// entry of the bottom of their stack.
+	Stacks StacksConfig `yaml:"stacks"`
+
+	// Fixups determines whether follow-up pull requests are folded into the
+	// entry of the pull request they follow up on.
+	Fixups FixupsConfig `yaml:"fixups"`
+
+	// Commits lists the commits of each pull request under its entry, by
+	// their short SHA and subject, linked to the commit where possible.
+	Commits bool `yaml:"commits"`
+
+	// Header is the markdown rendered before the release notes in every
+	// release, i.e - install instructions.
+	Header BlockConfig `yaml:"header"`
+
+	// Footer is the markdown rendered after the release notes in every
+	// release, i.e - support links.
+	Footer BlockConfig `yaml:"footer"`
+
+	// Install determines the content of the installation section, with the
+	// commands to install the release.
+	Install InstallConfig `yaml:"install"`
+
+	// Badges is the list of badges, or emoji, rendered on the entries with
+	// their labels, i.e - to flag experimental changes.
+	Badges []BadgeConfig `yaml:"badges"`
+
+	// Size determines how the entries are annotated with the weight of their
+	// change.
+	Size SizeConfig `yaml:"size"`
+
+	// Thanks determines the thank-you paragraph that closes the release
+	// notes.
+	Thanks ThanksConfig `yaml:"thanks"`
+
+	// LinkedIssues resolves the titles of the issues that each pull request
+	// closes, i.e - with `Fixes #45`, into its entry.
+	LinkedIssues bool `yaml:"linkedIssues"`
+
+	// Autolinks rewrites the bare references to issues and pull requests in
+	// the bodies of the entries, i.e - `#123`, `GH-123`, and `owner/repo#123`,
+	// into links on the forge.
+	Autolinks bool `yaml:"autolinks"`
+
+	// AbsoluteLinks rewrites the relative links in the bodies of the entries,
+	// i.e - `./docs/foo.md`, into absolute URLs of the repository, pinned to
+	// the release tag.
+	AbsoluteLinks bool `yaml:"absoluteLinks"`
+
+	// Security determines the content of the security section.
+	Security SecurityConfig `yaml:"security"`
+
+	// SBOM determines the content of the dependency changes section.
+	SBOM SBOMConfig `yaml:"sbom"`
+
+	// Submodules determines the content of the submodules section.
+	Submodules SubmodulesConfig `yaml:"submodules"`
+
+	// Vendored is the list of vendored directories, whose changes are
+	// summarised separately from the rest of the release notes.
+	Vendored []VendoredConfig `yaml:"vendored"`
+
+	// Publish determines whether, and how, the release notes are published to
+	// the release.
+	Publish PublishConfig `yaml:"publish"`
+
+	// Format is the format that the release notes are output in. Defaults to
+	// "markdown".
+	Format Format `yaml:"format"`
+
+	// Lint determines whether, and how, the rendered markdown is linted.
+	Lint LintConfig `yaml:"lint"`
+
+	// Report determines the thresholds of the notes quality report.
+	Report ReportConfig `yaml:"report"`
+
+	// Pagination determines how very large sets of entries are split up.
+	Pagination PaginationConfig `yaml:"pagination"`
+
+	// Stream renders and outputs each entry as markdown as soon as its pull
+	// request is fetched, rather than collecting every entry first, keeping
+	// the memory used flat for very large releases. The entries are output in
+	// the order they are listed, so it can't be combined with the features
+	// that need every entry up front, i.e - categories and sorting.
+	Stream bool `yaml:"stream"`
+
+	// Notify determines where the release notes are posted once they have
+	// been generated, or published.
+	Notify NotifyConfig `yaml:"notify"`
+
+	// Site determines whether, and where, a static-site fragment of the
+	// release notes is written.
+	Site SiteConfig `yaml:"site"`
+
+	// Docs determines whether, and where, a versioned docs directory of
+	// release notes is maintained.
+	Docs DocsConfig `yaml:"docs"`
+
+	// Provenance embeds a provenance section (the builder, workflow ref, and
+	// commit SHA) when running in a supported CI provider (GitHub Actions or
+	// GitLab CI).
+	Provenance bool `yaml:"provenance"`
+
+	// Sign determines whether, and how, the rendered release notes are
+	// signed.
+	Sign SignConfig `yaml:"sign"`
+
+	// Sigstore determines whether the uploaded release notes are signed and
+	// attested with Sigstore.
+	Sigstore SigstoreConfig `yaml:"sigstore"`
+
+	// Plugins determines the external binaries that add custom output formats,
+	// or data sources.
+	Plugins PluginsConfig `yaml:"plugins"`
+
+	// Bump determines which files have their version strings updated by the
+	// bump command.
+	Bump BumpConfig `yaml:"bump"`
+
+	// Tag determines how tags are created by the tag command.
+	Tag TagConfig `yaml:"tag"`
+
+	// Cache determines whether, and for how long, the details of the pull
+	// requests are cached between runs.
+	Cache CacheConfig `yaml:"cache"`
+
+	// Retry determines how the transient failures of the forge, the remote,
+	// and the notification webhooks are retried.
+	Retry RetryConfig `yaml:"retry"`
+
+	// Timezone is the IANA name of the timezone, i.e - `Europe/London`, that
+	// dates are displayed and compared in. Defaults to "UTC".
+	Timezone string `yaml:"timezone"`
+
+	// DateFormat is how dates are displayed, either as a Go time layout, i.e -
+	// `02 Jan 2006`, or as one of the "date" (default), "datetime", "rfc3339",
+	// "long", or "short" presets.
+	DateFormat string `yaml:"dateFormat"`
+
+	// Template is the path to a Go text/template file that the markdown
+	// release notes are rendered with, in place of the built-in layout.
+	Template string `yaml:"template"`
+
+	// Progress reports the progress of collecting the release notes on
+	// stderr, if it is a terminal.
+	Progress bool `yaml:"-"`
+
+	// Diff outputs a unified diff of the published release notes against the
+	// generated release notes, instead of the release notes themselves. Nothing
+	// is published, written, or notified.
+	Diff bool `yaml:"-"`
+
+	// Check regenerates the release notes, and fails if the output is not
+	// identical to the first generation.
+	Check bool `yaml:"-"`
+
+	// templateFuncs are the custom functions available to the templates, as
+	// provided with WithTemplateFunc.
+	templateFuncs template.FuncMap
+}
+
+// LoadConfig reads and parses the configuration file at the provided path.
+//
+// If the path is empty, DefaultConfigPath is used instead, and an empty Config
+// is returned if it does not exist.
+func LoadConfig(path string) (Config, error) {
+	var (
+		config   Config
+		optional = path == ""
+	)
+
+	// Fall back to the default configuration file path.
+	if optional {
+		path = DefaultConfigPath
+	}
+
+	// Read the configuration file.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return config, nil
+		}
+		return config, &ConfigReadError{Path: path, Err: err}
+	}
+
+	// Parse the configuration file.
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, &ConfigParseError{Path: path, Err: err}
+	}
+
+	return config, nil
+}
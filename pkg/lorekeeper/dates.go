@@ -0,0 +1,43 @@
+package lorekeeper
+
+import (
+	"time"
+)
+
+// location returns the configured timezone, or UTC if it has not been
+// configured.
+func (c Config) location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, &TimezoneInvalidError{Timezone: c.Timezone, Err: err}
+	}
+
+	return location, nil
+}
+
+// dateFormatPresets maps the names of the date format presets to their Go time
+// layouts.
+var dateFormatPresets = map[string]string{
+	"date":     time.DateOnly,
+	"datetime": "2006-01-02 15:04 MST",
+	"rfc3339":  time.RFC3339,
+	"long":     "January 2, 2006",
+	"short":    "Jan 2, 2006",
+}
+
+// dateLayout returns the Go time layout of the configured date format, which is
+// either the name of a preset or a layout itself. Defaults to the "date"
+// preset.
+func (c Config) dateLayout() string {
+	if c.DateFormat == "" {
+		return time.DateOnly
+	}
+	if layout, ok := dateFormatPresets[c.DateFormat]; ok {
+		return layout
+	}
+	return c.DateFormat
+}
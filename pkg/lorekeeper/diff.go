@@ -0,0 +1,222 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+// in a unified diff.
+const diffContextLines = 3
+
+// diffOp is the operation applied to a line in a diff.
+type diffOp byte
+
+const (
+	diffEqual  diffOp = ' '
+	diffDelete diffOp = '-'
+	diffInsert diffOp = '+'
+)
+
+// diffLine represents a single line of a diff.
+type diffLine struct {
+	Op   diffOp
+	Text string
+}
+
+// diffLines returns the shortest edit script transforming the lines of a into
+// the lines of b, using the Myers diff algorithm.
+func diffLines(a, b []string) []diffLine {
+	var (
+		n, m  = len(a), len(b)
+		max   = n + m
+		v     = make([]int, 2*max+2)
+		trace [][]int
+	)
+
+	// Find the furthest reaching path for each number of edits, recording each
+	// step so the path can be backtracked.
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v...))
+				break search
+			}
+		}
+	}
+
+	// Backtrack through the recorded steps to build the edit script.
+	var (
+		lines []diffLine
+		x, y  = n, m
+	)
+	for d := len(trace) - 2; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			lines = append(lines, diffLine{Op: diffEqual, Text: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				lines = append(lines, diffLine{Op: diffInsert, Text: b[y]})
+			} else {
+				x--
+				lines = append(lines, diffLine{Op: diffDelete, Text: a[x]})
+			}
+		}
+	}
+
+	// The edit script was built backwards.
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines
+}
+
+// writeUnifiedDiff writes the unified diff between the provided texts to the
+// writer, reporting whether they differ.
+func writeUnifiedDiff(w io.Writer, fromName, toName, from, to string) (bool, error) {
+	var (
+		lines   = diffLines(splitLines(from), splitLines(to))
+		changed bool
+	)
+	for _, line := range lines {
+		if line.Op != diffEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", fromName, toName); err != nil {
+		return true, err
+	}
+
+	// Output each hunk of changes, with the surrounding context.
+	for start := 0; start < len(lines); {
+		// Find the next change.
+		for start < len(lines) && lines[start].Op == diffEqual {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk until there are more unchanged lines than would be
+		// shown as context on both sides.
+		end, unchanged := start, 0
+		for end < len(lines) && unchanged <= 2*diffContextLines {
+			if lines[end].Op == diffEqual {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+			end++
+		}
+		end -= max(0, unchanged-diffContextLines)
+		hunkStart := max(0, start-diffContextLines)
+
+		// Count the lines of each side before, and within, the hunk.
+		var fromLine, toLine, fromCount, toCount int
+		for _, line := range lines[:hunkStart] {
+			if line.Op != diffInsert {
+				fromLine++
+			}
+			if line.Op != diffDelete {
+				toLine++
+			}
+		}
+		for _, line := range lines[hunkStart:end] {
+			if line.Op != diffInsert {
+				fromCount++
+			}
+			if line.Op != diffDelete {
+				toCount++
+			}
+		}
+
+		var hunk strings.Builder
+		fmt.Fprintf(&hunk, "@@ -%d,%d +%d,%d @@\n", fromLine+1, fromCount, toLine+1, toCount)
+		for _, line := range lines[hunkStart:end] {
+			fmt.Fprintf(&hunk, "%c%s\n", line.Op, line.Text)
+		}
+		if _, err := io.WriteString(w, hunk.String()); err != nil {
+			return true, err
+		}
+
+		start = end
+	}
+
+	return true, nil
+}
+
+// splitLines splits the provided text into lines, ignoring any trailing
+// whitespace so it doesn't show up as a change.
+func splitLines(text string) []string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), " \t\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffReleaseNotes writes a unified diff of the published release notes
+// against the generated release notes to the writer.
+func diffReleaseNotes(ctx context.Context, cmd commander, w io.Writer, notes releaseNotes, mode mode, config Config) error {
+	// Get the currently published release notes.
+	published, err := fetchPublishedBody(ctx, cmd, notes.TagName, mode)
+	if err != nil {
+		return err
+	}
+
+	// Render the release notes exactly as they would be published, without
+	// creating a gist for the full release notes.
+	config.Publish.Truncate.Gist = false
+	generated, err := renderPublishedMarkdown(ctx, cmd, notes, config)
+	if err != nil {
+		return err
+	}
+
+	// Output the diff.
+	changed, err := writeUnifiedDiff(w, "published/"+notes.TagName, "generated/"+notes.TagName, published, string(generated))
+	if err != nil {
+		return err
+	}
+	if !changed {
+		log.Info("Published release notes are up to date", "tag", notes.TagName)
+	}
+
+	return nil
+}
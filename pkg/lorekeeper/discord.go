@@ -0,0 +1,86 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// discordMaxDescriptionLength is the maximum length of the description of a
+	// Discord embed.
+	discordMaxDescriptionLength = 4096
+
+	// discordMaxMessageLength is the maximum combined length of the embeds of
+	// a Discord message.
+	discordMaxMessageLength = 6000
+
+	// discordMaxEmbeds is the maximum number of embeds in a Discord message.
+	discordMaxEmbeds = 10
+
+	// discordEmbedColor is the color of the embeds, matching the purple of the
+	// lorekeeper branding.
+	discordEmbedColor = 0x8E44AD
+)
+
+type discordEmbed struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+// NotifyDiscord posts the provided markdown release notes for the tag to each
+// of the Discord webhook URLs as embeds, chunking long release notes across as
+// many embeds and messages as required.
+func NotifyDiscord(ctx context.Context, webhookURLs []string, tagName string, markdown string, opts ...Option) error {
+	o := newOptions(opts)
+	return notifyDiscord(ctx, o.cmd, webhookURLs, tagName, markdown)
+}
+
+// notifyDiscord posts the release notes as described by NotifyDiscord, with the
+// provided commander.
+func notifyDiscord(ctx context.Context, cmd commander, webhookURLs []string, tagName string, markdown string) error {
+	var (
+		title    = fmt.Sprintf("Release %s", tagName)
+		messages []discordMessage
+		current  discordMessage
+		length   int
+	)
+
+	// Images are not rendered in embeds.
+	text := strings.TrimSpace(reMarkdownImage.ReplaceAllString(markdown, "$1"))
+
+	for _, chunk := range chunkText(text, discordMaxDescriptionLength) {
+		embed := discordEmbed{Description: chunk, Color: discordEmbedColor}
+		if len(messages) == 0 && len(current.Embeds) == 0 {
+			embed.Title = title
+		}
+
+		// Start a new message if the embed would exceed the limits.
+		embedLength := len(embed.Title) + len(embed.Description)
+		if len(current.Embeds) == discordMaxEmbeds || length+embedLength > discordMaxMessageLength {
+			messages = append(messages, current)
+			current, length = discordMessage{}, 0
+		}
+
+		current.Embeds = append(current.Embeds, embed)
+		length += embedLength
+	}
+	if len(current.Embeds) > 0 {
+		messages = append(messages, current)
+	}
+
+	for _, webhookURL := range webhookURLs {
+		for _, message := range messages {
+			if err := postJSON(ctx, cmd, webhookURL, message, nil); err != nil {
+				return &NotifyError{Destination: "discord", Err: err}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// docsIndexName is the file name of the index page of the docs directory.
+	docsIndexName = "index.md"
+
+	// defaultDocsIndexTitle is the title of the index page when none has been
+	// configured.
+	defaultDocsIndexTitle = "Releases"
+)
+
+// DocsConfig determines whether, and where, a versioned docs directory of
+// release notes is maintained.
+type DocsConfig struct {
+	// Dir is the directory containing a file per release, and an index page
+	// linking to each of them, i.e - `docs/releases`. If empty, no docs
+	// directory is maintained.
+	Dir string `yaml:"dir"`
+
+	// IndexTitle is the title of the index page. Defaults to "Releases".
+	IndexTitle string `yaml:"indexTitle"`
+}
+
+// writeDocs writes the release notes to a file in the configured docs
+// directory, and regenerates the index page from every release file in it.
+func writeDocs(notes releaseNotes, config Config) error {
+	dir := config.Docs.Dir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return &DocsError{Path: dir, Err: err}
+	}
+
+	// Write the release file.
+	var release bytes.Buffer
+	fmt.Fprintf(&release, "# %s\n\n", notes.TagName)
+	fmt.Fprintf(&release, "_Released %s._\n\n", notes.Date.Format(config.dateLayout()))
+
+	// The release notes are nested beneath the title of the release file.
+	var markdown bytes.Buffer
+	if err := renderLintedMarkdown(&markdown, notes, config); err != nil {
+		return err
+	}
+	release.WriteString(demoteHeadings(markdown.String(), 1))
+
+	path := filepath.Join(dir, notes.TagName+".md")
+	if err := os.WriteFile(path, release.Bytes(), 0o644); err != nil {
+		return &DocsError{Path: path, Err: err}
+	}
+
+	// Regenerate the index page.
+	return writeDocsIndex(config.Docs)
+}
+
+// writeDocsIndex regenerates the index page of the docs directory, linking to
+// every release file in it, newest version first. The index only depends on
+// the release files, so regenerating it is deterministic.
+func writeDocsIndex(config DocsConfig) error {
+	entries, err := os.ReadDir(config.Dir)
+	if err != nil {
+		return &DocsError{Path: config.Dir, Err: err}
+	}
+
+	var tagNames []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == docsIndexName || filepath.Ext(name) != ".md" {
+			continue
+		}
+		tagNames = append(tagNames, strings.TrimSuffix(name, ".md"))
+	}
+	slices.SortFunc(tagNames, func(a, b string) int {
+		return compareVersions(b, a)
+	})
+
+	title := config.IndexTitle
+	if title == "" {
+		title = defaultDocsIndexTitle
+	}
+
+	var index bytes.Buffer
+	fmt.Fprintf(&index, "# %s\n\n", title)
+	for _, tagName := range tagNames {
+		fmt.Fprintf(&index, "- [%s](%s.md)\n", tagName, tagName)
+	}
+
+	path := filepath.Join(config.Dir, docsIndexName)
+	if err := os.WriteFile(path, index.Bytes(), 0o644); err != nil {
+		return &DocsError{Path: path, Err: err}
+	}
+
+	return nil
+}
+
+// compareVersions compares two tags as semantic versions. Tags that are not
+// valid semantic versions are ordered before those that are, and compared as
+// strings.
+func compareVersions(a, b string) int {
+	var (
+		versionA = "v" + versionFromTag(a)
+		versionB = "v" + versionFromTag(b)
+		validA   = semver.IsValid(versionA)
+		validB   = semver.IsValid(versionB)
+	)
+
+	switch {
+	case validA && validB:
+		if c := semver.Compare(versionA, versionB); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case validA:
+		return 1
+	case validB:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// demoteHeadings increases the level of every heading in the provided
+// markdown by the provided number of levels, outside of fenced code blocks.
+func demoteHeadings(markdown string, levels int) string {
+	var (
+		lines   = strings.Split(markdown, "\n")
+		inFence bool
+	)
+
+	for idx, line := range lines {
+		if reFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && reHeading.MatchString(line) {
+			lines[idx] = strings.Repeat("#", levels) + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,146 @@
+package lorekeeper
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Entry is a pull request included in the release notes.
+type Entry struct {
+	Number       int           `json:"number"`
+	Title        string        `json:"title"`
+	DisplayTitle string        `json:"displayTitle,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	Category     string        `json:"category"`
+	Labels       []string      `json:"labels,omitempty"`
+	Authors      []string      `json:"authors,omitempty"`
+	MergedAt     time.Time     `json:"mergedAt"`
+	Body         string        `json:"body"`
+	PublishedIn  string        `json:"publishedIn,omitempty"`
+	Commits      []EntryCommit `json:"commits,omitempty"`
+	Issues       []string      `json:"issues,omitempty"`
+	Size         string        `json:"size,omitempty"`
+	Areas        []string      `json:"areas,omitempty"`
+	Badges       []string      `json:"badges,omitempty"`
+	Stacked      []int         `json:"stacked,omitempty"`
+	Fixups       []int         `json:"fixups,omitempty"`
+}
+
+// EntryCommit is a commit of the pull request of an entry.
+type EntryCommit struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	URL     string `json:"url,omitempty"`
+}
+
+// newEntry returns the entry for the provided pull request.
+func newEntry(pullRequest gitPullRequest, config Config) Entry {
+	entry := Entry{
+		Number:      pullRequest.Number,
+		Title:       pullRequest.Title,
+		URL:         pullRequest.URL,
+		Category:    pullRequest.category.Title,
+		MergedAt:    pullRequest.MergedAt,
+		Body:        pullRequest.Body,
+		PublishedIn: pullRequest.publishedIn,
+		Badges:      badges(pullRequest, config.Badges),
+	}
+	for _, label := range pullRequest.Labels {
+		entry.Labels = append(entry.Labels, label.Name)
+	}
+	for _, author := range pullRequestAuthors(pullRequest, config.Authors) {
+		entry.Authors = append(entry.Authors, author.Login)
+	}
+	for _, issue := range pullRequest.issues {
+		entry.Issues = append(entry.Issues, issue.String())
+	}
+	if config.StripPrefixes.Enabled || config.NormaliseTitles.Enabled {
+		entry.DisplayTitle = pullRequest.displayTitle(config)
+	}
+	if config.Size.Enabled {
+		entry.Size = config.Size.size(pullRequest)
+	}
+	if config.Size.Areas {
+		entry.Areas = areas(pullRequest)
+	}
+	if config.Commits {
+		for _, commit := range pullRequest.Commits {
+			url, _ := pullRequest.commitURL(commit.OID)
+			entry.Commits = append(entry.Commits, EntryCommit{SHA: commit.OID, Subject: commit.MessageHeadline, URL: url})
+		}
+	}
+	for _, layer := range pullRequest.stacked {
+		entry.Stacked = append(entry.Stacked, layer.Number)
+	}
+	for _, fixup := range pullRequest.fixups {
+		entry.Fixups = append(entry.Fixups, fixup.Number)
+	}
+	return entry
+}
+
+// Entries returns an iterator over the entries of the release notes, yielding
+// each as soon as its pull request is fetched, so that huge releases can be
+// rendered progressively without waiting for, or holding, every entry.
+//
+// The entries are yielded in the order the pull requests are listed, before
+// they are sorted, or the vendored changes are separated out. Iteration stops
+// after the first error is yielded.
+func (g *Generator) Entries(ctx context.Context) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		config := g.options.config
+
+		// The timezone that dates are compared in.
+		location, err := config.location()
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+
+		// List the pull requests to include.
+		listing, err := listPullRequests(ctx, g.tagName, g.options, location)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+
+		// Compile the category rules.
+		categoriser, err := newCategoriser(config.Categories)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+
+		// Compile the filter expression.
+		filter, err := newEntryFilter(config.Filter)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+
+		// The ref that the relative links are pinned to.
+		var ref string
+		if config.AbsoluteLinks {
+			ref = linksRef(ctx, g.options, g.tagName)
+		}
+
+		// Yield the entry of each pull request, as it is fetched.
+		progress := newProgress(config.Progress)
+		defer progress.done()
+		for pullRequest, err := range fetchPullRequests(ctx, g.options, listing, categoriser, filter, progress) {
+			if err != nil {
+				yield(Entry{}, err)
+				return
+			}
+			if config.AbsoluteLinks {
+				pullRequest.Body = absoluteBody(ctx, g.options.cmd, pullRequest, ref)
+			}
+			if config.Autolinks {
+				pullRequest.Body = autolinkBody(ctx, g.options.cmd, pullRequest)
+			}
+			if !yield(newEntry(pullRequest, config), nil) {
+				return
+			}
+		}
+	}
+}
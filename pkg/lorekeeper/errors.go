@@ -1,6 +1,15 @@
 package lorekeeper
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoPullRequests is matched, with errors.Is, by the errors returned when no
+// pull requests are found to include in the release notes.
+var ErrNoPullRequests = errors.New("no pull requests found")
 
 type DefaultBranchReleaseCandidateError struct {
 	TagName       string
@@ -44,6 +53,833 @@ type NoPullRequestsFoundError struct {
 func (e *NoPullRequestsFoundError) Error() string {
 	return fmt.Sprintf(
 		"no pull requests merged since latest %s date (%s @ %s) found",
-		e.Mode, e.LatestRef.TagName, e.LatestRef.TagName,
+		e.Mode.Name, e.LatestRef.TagName, e.LatestRef.PublishedAt.Format(time.RFC3339),
+	)
+}
+
+func (e *NoPullRequestsFoundError) Is(target error) bool {
+	return target == ErrNoPullRequests
+}
+
+type ConfigReadError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConfigReadError) Error() string {
+	return fmt.Sprintf("failed to read config file (%s): %v", e.Path, e.Err)
+}
+
+func (e *ConfigReadError) Unwrap() error {
+	return e.Err
+}
+
+type ConfigParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConfigParseError) Error() string {
+	return fmt.Sprintf("failed to parse config file (%s): %v", e.Path, e.Err)
+}
+
+func (e *ConfigParseError) Unwrap() error {
+	return e.Err
+}
+
+type SortKeyInvalidError struct {
+	Key SortKey
+}
+
+func (e *SortKeyInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid sort key: expected one of %s, %s, %s, %s, got %s",
+		SortByMerged, SortByNumber, SortByTitle, SortByCategory, e.Key,
 	)
 }
+
+type CategoryRuleInvalidError struct {
+	Category string
+	Err      error
+}
+
+func (e *CategoryRuleInvalidError) Error() string {
+	return fmt.Sprintf("invalid rule for category (%s): %v", e.Category, e.Err)
+}
+
+func (e *CategoryRuleInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type SecurityAdvisoriesError struct {
+	Err error
+}
+
+func (e *SecurityAdvisoriesError) Error() string {
+	return fmt.Sprintf("failed to get security advisories: %v", e.Err)
+}
+
+func (e *SecurityAdvisoriesError) Unwrap() error {
+	return e.Err
+}
+
+type SBOMError struct {
+	Path string
+	Err  error
+}
+
+func (e *SBOMError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("failed to diff SBOMs: %v", e.Err)
+	}
+	return fmt.Sprintf("failed to read SBOM (%s): %v", e.Path, e.Err)
+}
+
+func (e *SBOMError) Unwrap() error {
+	return e.Err
+}
+
+type ReleaseAssetsError struct {
+	TagName string
+	Err     error
+}
+
+func (e *ReleaseAssetsError) Error() string {
+	return fmt.Sprintf("failed to get the assets of release (%s): %v", e.TagName, e.Err)
+}
+
+func (e *ReleaseAssetsError) Unwrap() error {
+	return e.Err
+}
+
+type PublishError struct {
+	TagName string
+	Err     error
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("failed to publish the release notes (%s): %v", e.TagName, e.Err)
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+type FormatInvalidError struct {
+	Format Format
+}
+
+func (e *FormatInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid format: expected one of %s, got %s",
+		getFormatNamesString(), e.Format,
+	)
+}
+
+type SubmodulesError struct {
+	Ref string
+	Err error
+}
+
+func (e *SubmodulesError) Error() string {
+	if e.Ref == "" {
+		return fmt.Sprintf("failed to get submodule changes: %v", e.Err)
+	}
+	return fmt.Sprintf("failed to get submodule changes (%s): %v", e.Ref, e.Err)
+}
+
+func (e *SubmodulesError) Unwrap() error {
+	return e.Err
+}
+
+type VendoredInvalidError struct {
+	Vendored VendoredConfig
+}
+
+func (e *VendoredInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid vendored directory: both a name and path are required, got name %q and path %q",
+		e.Vendored.Name, e.Vendored.Path,
+	)
+}
+
+type ReleaseCandidateRegexInvalidError struct {
+	Regex string
+	Err   error
+}
+
+func (e *ReleaseCandidateRegexInvalidError) Error() string {
+	return fmt.Sprintf("invalid release candidate regex (%s): %v", e.Regex, e.Err)
+}
+
+func (e *ReleaseCandidateRegexInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type InputInvalidError struct {
+	Input  string
+	Value  string
+	Reason string
+}
+
+func (e *InputInvalidError) Error() string {
+	return fmt.Sprintf("invalid %s (%q): %s", e.Input, e.Value, e.Reason)
+}
+
+type ReleaseCandidatePresetGetByNameError struct {
+	Name string
+}
+
+func (e *ReleaseCandidatePresetGetByNameError) Error() string {
+	return fmt.Sprintf(
+		"invalid release candidate preset: expected one of %s, got %s",
+		getReleaseCandidatePresetNamesString(), e.Name,
+	)
+}
+
+type RetryConfigInvalidError struct {
+	Reason string
+}
+
+func (e *RetryConfigInvalidError) Error() string {
+	return fmt.Sprintf("invalid retry configuration: %s", e.Reason)
+}
+
+type RetryCanceledError struct {
+	Op      string
+	Err     error
+	LastErr error
+}
+
+func (e *RetryCanceledError) Error() string {
+	return fmt.Sprintf("%s canceled: %v, after: %v", e.Op, e.Err, e.LastErr)
+}
+
+func (e *RetryCanceledError) Unwrap() []error {
+	return []error{e.Err, e.LastErr}
+}
+
+type HTTPStatusError struct {
+	Status     string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %s: %s", e.Status, e.Body)
+}
+
+type CacheError struct {
+	Err error
+}
+
+func (e *CacheError) Error() string {
+	return fmt.Sprintf("failed to access the cache: %v", e.Err)
+}
+
+func (e *CacheError) Unwrap() error {
+	return e.Err
+}
+
+type ShallowCloneError struct {
+	Remote string
+}
+
+func (e *ShallowCloneError) Error() string {
+	return fmt.Sprintf(
+		"the repository is a shallow clone, so its history and tags are incomplete: fetch them with `git fetch --unshallow --tags %s` (or `fetch-depth: 0` on actions/checkout), or deepen it automatically with --deepen",
+		e.Remote,
+	)
+}
+
+type RepositoryInvalidError struct {
+	Repository string
+}
+
+func (e *RepositoryInvalidError) Error() string {
+	return fmt.Sprintf("invalid repository: expected a remote URL or owner/name, got %s", e.Repository)
+}
+
+type ForgeUnavailableError struct {
+	Err error
+}
+
+func (e *ForgeUnavailableError) Error() string {
+	return fmt.Sprintf("failed to reach the forge, or the credentials are invalid: %v", e.Err)
+}
+
+func (e *ForgeUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+type LintError struct {
+	Issues int
+}
+
+func (e *LintError) Error() string {
+	return fmt.Sprintf("the rendered markdown has %d unfixed lint issue(s)", e.Issues)
+}
+
+type BodyTooLongError struct {
+	MaxLength int
+}
+
+func (e *BodyTooLongError) Error() string {
+	return fmt.Sprintf(
+		"the release notes exceed the maximum length (%d) even without any entries",
+		e.MaxLength,
+	)
+}
+
+type PaginationError struct {
+	Path string
+	Err  error
+}
+
+func (e *PaginationError) Error() string {
+	return fmt.Sprintf("failed to write release notes part (%s): %v", e.Path, e.Err)
+}
+
+func (e *PaginationError) Unwrap() error {
+	return e.Err
+}
+
+type PaginationModeInvalidError struct {
+	Mode PaginationMode
+}
+
+func (e *PaginationModeInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid pagination mode: expected one of %s, %s, got %s",
+		PaginationParts, PaginationDetails, e.Mode,
+	)
+}
+
+type ReleaseBranchInvalidError struct {
+	Pattern string
+	Err     error
+}
+
+func (e *ReleaseBranchInvalidError) Error() string {
+	return fmt.Sprintf("invalid release branch pattern %q: %v", e.Pattern, e.Err)
+}
+
+func (e *ReleaseBranchInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type TrainScheduleInvalidError struct {
+	Schedule string
+	Reason   string
+}
+
+func (e *TrainScheduleInvalidError) Error() string {
+	if e.Schedule == "" {
+		return fmt.Sprintf("invalid train schedule: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid train schedule %q: %s", e.Schedule, e.Reason)
+}
+
+type CandidateEntriesInvalidError struct {
+	Value CandidateEntries
+}
+
+func (e *CandidateEntriesInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid handling of the entries of earlier release candidates: expected one of %s, %s, %s, got %s",
+		CandidateEntriesInclude, CandidateEntriesExclude, CandidateEntriesMark, e.Value,
+	)
+}
+
+type TitleRewriteInvalidError struct {
+	Pattern string
+	Err     error
+}
+
+func (e *TitleRewriteInvalidError) Error() string {
+	return fmt.Sprintf("invalid title rewrite pattern %q: %v", e.Pattern, e.Err)
+}
+
+func (e *TitleRewriteInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type TitleMoodInvalidError struct {
+	Mood TitleMood
+}
+
+func (e *TitleMoodInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid title mood: expected one of %s, %s, got %s",
+		TitleMoodImperative, TitleMoodPast, e.Mood,
+	)
+}
+
+type StackBranchInvalidError struct {
+	Pattern string
+	Reason  string
+	Err     error
+}
+
+func (e *StackBranchInvalidError) Error() string {
+	reason := e.Reason
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	return fmt.Sprintf("invalid stack branch pattern %q: %s", e.Pattern, reason)
+}
+
+func (e *StackBranchInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type FixupTitleInvalidError struct {
+	Pattern string
+	Reason  string
+	Err     error
+}
+
+func (e *FixupTitleInvalidError) Error() string {
+	reason := e.Reason
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	return fmt.Sprintf("invalid fixup title pattern %q: %s", e.Pattern, reason)
+}
+
+func (e *FixupTitleInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type StrategyInvalidError struct {
+	Strategy Strategy
+}
+
+func (e *StrategyInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid strategy: expected one of %s, %s, got %s",
+		StrategyMergeBase, StrategyTimestamp, e.Strategy,
+	)
+}
+
+type CommitLookupsExceededError struct {
+	Commits int
+	Max     int
+}
+
+func (e *CommitLookupsExceededError) Error() string {
+	return fmt.Sprintf("too many commits without a pull request in their subject: %d, more than the %d asked of the forge", e.Commits, e.Max)
+}
+
+type StreamIncompatibleError struct {
+	Features []string
+}
+
+func (e *StreamIncompatibleError) Error() string {
+	return fmt.Sprintf("streaming cannot be combined with: %s", strings.Join(e.Features, ", "))
+}
+
+type NotifyError struct {
+	Destination string
+	Err         error
+}
+
+func (e *NotifyError) Error() string {
+	return fmt.Sprintf("failed to post the release notes to %s: %v", e.Destination, e.Err)
+}
+
+func (e *NotifyError) Unwrap() error {
+	return e.Err
+}
+
+type SiteError struct {
+	Path string
+	Err  error
+}
+
+func (e *SiteError) Error() string {
+	return fmt.Sprintf("failed to write the static-site fragment (%s): %v", e.Path, e.Err)
+}
+
+func (e *SiteError) Unwrap() error {
+	return e.Err
+}
+
+type DocsError struct {
+	Path string
+	Err  error
+}
+
+func (e *DocsError) Error() string {
+	return fmt.Sprintf("failed to write the release docs (%s): %v", e.Path, e.Err)
+}
+
+func (e *DocsError) Unwrap() error {
+	return e.Err
+}
+
+type FetchError struct {
+	TagName string
+	Err     error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("failed to fetch the published release notes (%s): %v", e.TagName, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+type NondeterministicOutputError struct {
+	TagName string
+	Diff    string
+}
+
+func (e *NondeterministicOutputError) Error() string {
+	return fmt.Sprintf("regenerating the release notes (%s) produced different output:\n%s", e.TagName, e.Diff)
+}
+
+type TimezoneInvalidError struct {
+	Timezone string
+	Err      error
+}
+
+func (e *TimezoneInvalidError) Error() string {
+	return fmt.Sprintf("invalid timezone (%s): %v", e.Timezone, e.Err)
+}
+
+func (e *TimezoneInvalidError) Unwrap() error {
+	return e.Err
+}
+
+type FundingError struct {
+	Path string
+	Err  error
+}
+
+func (e *FundingError) Error() string {
+	return fmt.Sprintf("failed to read the funding file (%s): %v", e.Path, e.Err)
+}
+
+func (e *FundingError) Unwrap() error {
+	return e.Err
+}
+
+type SizeThresholdsInvalidError struct {
+	Thresholds []int
+}
+
+func (e *SizeThresholdsInvalidError) Error() string {
+	return fmt.Sprintf("invalid size thresholds: expected %d ascending, positive numbers of lines, got %v", len(sizes)-1, e.Thresholds)
+}
+
+type GoModuleCheckInvalidError struct {
+	Check GoModuleCheck
+}
+
+func (e *GoModuleCheckInvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid go module check: expected one of %s, %s, got %s",
+		GoModuleCheckWarn, GoModuleCheckFail, e.Check,
+	)
+}
+
+type GoModuleMajorError struct {
+	TagName    string
+	ModulePath string
+	Reason     string
+	Err        error
+}
+
+func (e *GoModuleMajorError) Error() string {
+	reason := e.Reason
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	if e.ModulePath == "" {
+		return fmt.Sprintf("failed to check the go module at %s: %s", e.TagName, reason)
+	}
+	return fmt.Sprintf("the tag %s does not match the go module %s: %s", e.TagName, e.ModulePath, reason)
+}
+
+func (e *GoModuleMajorError) Unwrap() error {
+	return e.Err
+}
+
+type GoReleaserConfigError struct {
+	Path string
+	Err  error
+}
+
+func (e *GoReleaserConfigError) Error() string {
+	return fmt.Sprintf("failed to read the goreleaser config (%s): %v", e.Path, e.Err)
+}
+
+func (e *GoReleaserConfigError) Unwrap() error {
+	return e.Err
+}
+
+type GoReleaserNotesError struct {
+	Path string
+	Err  error
+}
+
+func (e *GoReleaserNotesError) Error() string {
+	return fmt.Sprintf("failed to write the release notes for goreleaser (%s): %v", e.Path, e.Err)
+}
+
+func (e *GoReleaserNotesError) Unwrap() error {
+	return e.Err
+}
+
+type FragmentsFormatInvalidError struct {
+	Format FragmentsFormat
+}
+
+func (e *FragmentsFormatInvalidError) Error() string {
+	var formats []string
+	for _, format := range GetFragmentsFormats() {
+		formats = append(formats, string(format))
+	}
+	return fmt.Sprintf("invalid fragments format: expected one of %s, got %s", strings.Join(formats, ", "), e.Format)
+}
+
+type FragmentsDeleteInvalidError struct {
+	Format FragmentsFormat
+}
+
+func (e *FragmentsDeleteInvalidError) Error() string {
+	return fmt.Sprintf("the %s fragments are kept across releases, so can't be deleted", e.Format)
+}
+
+type FragmentError struct {
+	Path string
+	Err  error
+}
+
+func (e *FragmentError) Error() string {
+	return fmt.Sprintf("failed to read the fragment (%s): %v", e.Path, e.Err)
+}
+
+func (e *FragmentError) Unwrap() error {
+	return e.Err
+}
+
+type ChangelogError struct {
+	Path string
+	Err  error
+}
+
+func (e *ChangelogError) Error() string {
+	return fmt.Sprintf("failed to write the changelog (%s): %v", e.Path, e.Err)
+}
+
+func (e *ChangelogError) Unwrap() error {
+	return e.Err
+}
+
+type ChangelogOutdatedError struct {
+	Path    string
+	Missing []string
+}
+
+func (e *ChangelogOutdatedError) Error() string {
+	return fmt.Sprintf("the changelog (%s) is missing the entries of the pending release: %s", e.Path, strings.Join(e.Missing, ", "))
+}
+
+type NextVersionError struct {
+	TagName string
+}
+
+func (e *NextVersionError) Error() string {
+	return fmt.Sprintf("failed to predict the next version: the latest tag (%s) is not a semantic version", e.TagName)
+}
+
+type ReportThresholdInvalidError struct {
+	Name  string
+	Value int
+}
+
+func (e *ReportThresholdInvalidError) Error() string {
+	return fmt.Sprintf("invalid report threshold %s: expected a percentage from 0 to 100, got %d", e.Name, e.Value)
+}
+
+type NotesQualityError struct {
+	Failures []string
+}
+
+func (e *NotesQualityError) Error() string {
+	return fmt.Sprintf("the notes quality is below the thresholds: %s", strings.Join(e.Failures, ", "))
+}
+
+type PullRequestSignalError struct {
+	Number  int
+	Missing []string
+}
+
+func (e *PullRequestSignalError) Error() string {
+	missing := strings.Join(e.Missing, " or ")
+	if len(e.Missing) > 2 {
+		missing = strings.Join(e.Missing[:len(e.Missing)-1], ", ") + ", or " + e.Missing[len(e.Missing)-1]
+	}
+	return fmt.Sprintf("pull request #%d will not produce a sane release notes entry: it has no %s", e.Number, missing)
+}
+
+type TemplateError struct {
+	Path string
+	Err  error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("failed to render the template (%s): %v", e.Path, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+type BumpError struct {
+	Path string
+	Err  error
+}
+
+func (e *BumpError) Error() string {
+	return fmt.Sprintf("failed to bump the version (%s): %v", e.Path, e.Err)
+}
+
+func (e *BumpError) Unwrap() error {
+	return e.Err
+}
+
+type TagError struct {
+	TagName string
+	Err     error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("failed to create the tag (%s): %v", e.TagName, e.Err)
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+type SignMethodInvalidError struct {
+	Method SignMethod
+}
+
+func (e *SignMethodInvalidError) Error() string {
+	return fmt.Sprintf("invalid signing method: expected one of %s, %s, got %s", SignGPG, SignSSH, e.Method)
+}
+
+type SignError struct {
+	Path string
+	Err  error
+}
+
+func (e *SignError) Error() string {
+	return fmt.Sprintf("failed to sign the release notes (%s): %v", e.Path, e.Err)
+}
+
+func (e *SignError) Unwrap() error {
+	return e.Err
+}
+
+type SigstoreError struct {
+	Path string
+	Err  error
+}
+
+func (e *SigstoreError) Error() string {
+	return fmt.Sprintf("failed to attest the release notes (%s): %v", e.Path, e.Err)
+}
+
+func (e *SigstoreError) Unwrap() error {
+	return e.Err
+}
+
+type FilterError struct {
+	Expression string
+	Err        error
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter expression (%s): %v", e.Expression, e.Err)
+}
+
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+type PluginError struct {
+	Name string
+	Err  error
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("plugin failed (%s): %v", e.Name, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+type OperationError struct {
+	Op  string
+	Ref string
+	Err error
+}
+
+func (e *OperationError) Error() string {
+	if e.Ref == "" {
+		return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("failed to %s (%s): %v", e.Op, e.Ref, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+type CommandError struct {
+	Command string
+	Stderr  string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("command failed (%s): %v", e.Command, e.Err)
+	}
+	return fmt.Sprintf("command failed (%s): %v: %s", e.Command, e.Err, e.Stderr)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("the forge rejected the credentials: %v", e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+type RateLimitError struct {
+	Secondary bool
+	Err       error
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("the forge rate limit was exceeded: %v", e.Err)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
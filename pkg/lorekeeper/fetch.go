@@ -0,0 +1,207 @@
+package lorekeeper
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// reEntryHeading matches the heading of a rendered release note entry,
+	// capturing its level, title, and pull request number.
+	reEntryHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)\s+\(#(\d+)\)\s*$`)
+
+	// reAuthorAvatar matches a rendered author avatar, capturing the login and
+	// avatar URL.
+	reAuthorAvatar = regexp.MustCompile(`!\[@([^\]]+)\]\(([^)]+)\)`)
+
+	// reAuthorsLine matches a rendered line of author avatars, or anonymised
+	// author names.
+	reAuthorsLine = regexp.MustCompile(`^\s*((!\[@[^\]]+\]\([^)]+\)|[\w.\[\]-]+)\s*)+$`)
+)
+
+// sectionTitles are the titles of the rendered sections that are not
+// categories of entries.
+var sectionTitles = []string{
+	"Upgrading",
+	"Security",
+	"Vendored",
+	"Submodules",
+	"Dependency Changes",
+	"Assets",
+	"Provenance",
+}
+
+// fetchPublishedBody returns the published release notes for the provided tag:
+// the body of the release in ModeRelease, or the annotation of the tag in
+// ModeTag.
+func fetchPublishedBody(ctx context.Context, cmd commander, tagName string, mode mode) (string, error) {
+	var (
+		body string
+		err  error
+	)
+
+	switch mode {
+	case ModeRelease:
+		body, err = cmd.runForge(ctx, "gh", "release", "view", tagName, "--json", "body", "--jq", ".body")
+	case ModeTag:
+		body, err = cmd.run(ctx, "git", "tag", "-l", "--format=%(contents)", tagName)
+	default:
+		return "", &ModeInvalidError{Mode: mode}
+	}
+	if err != nil {
+		return "", &FetchError{TagName: tagName, Err: err}
+	}
+
+	return body, nil
+}
+
+// parseMarkdownNotes parses markdown release notes, as rendered by lorekeeper,
+// back into the structured model. Only the entries, and the categories they
+// were rendered in, are recovered.
+func parseMarkdownNotes(tagName string, markdown string) releaseNotes {
+	var (
+		notes         = releaseNotes{TagName: tagName}
+		category      = defaultCategory
+		current       *gitPullRequest
+		entryLevel    int
+		expectAuthors bool
+		body          []string
+		inFence       bool
+		weights       = map[string]int{}
+	)
+
+	// Finish the current entry.
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		notes.PullRequests = append(notes.PullRequests, *current)
+		current, body, expectAuthors = nil, nil, false
+	}
+
+	for line := range strings.SplitSeq(markdown, "\n") {
+		if reFence.MatchString(line) {
+			inFence = !inFence
+		}
+
+		// Entry headings start a new entry.
+		if matches := reEntryHeading.FindStringSubmatch(line); matches != nil && !inFence {
+			flush()
+			number, _ := strconv.Atoi(matches[3])
+			entryLevel = len(matches[1])
+			current = &gitPullRequest{
+				Number:   number,
+				Title:    strings.TrimSpace(matches[2]),
+				category: category,
+			}
+			continue
+		}
+
+		// Other headings outside of an entry's body are categories, scopes, or
+		// sections.
+		if matches := reHeading.FindStringSubmatch(line); matches != nil && !inFence {
+			var (
+				level = len(matches[1])
+				title = strings.TrimSpace(matches[2])
+			)
+			if current == nil || level <= entryLevel {
+				flush()
+
+				// Only top level headings are categories or sections.
+				if level == 1 {
+					if _, exists := weights[title]; !exists {
+						weights[title] = len(weights)
+					}
+					category = CategoryConfig{Title: title, Weight: weights[title]}
+					if slices.Contains(sectionTitles, title) {
+						category = defaultCategory
+					}
+				}
+				continue
+			}
+
+			// The authors heading, and the authors line following it, are not
+			// part of the body.
+			if title == "Authors" && len(body) == 0 {
+				expectAuthors = true
+				continue
+			}
+		}
+
+		if current == nil {
+			continue
+		}
+
+		// Recover the authors from their avatars, or their names if they were
+		// anonymised.
+		if expectAuthors && strings.TrimSpace(line) != "" {
+			expectAuthors = false
+
+			// Without any authors, the line is the start of the body.
+			if !reAuthorsLine.MatchString(line) {
+				body = append(body, line)
+				continue
+			}
+
+			var authors []gitAuthor
+			if avatars := reAuthorAvatar.FindAllStringSubmatch(line, -1); len(avatars) > 0 {
+				for _, avatar := range avatars {
+					// Undo the avatar sizing added when rendering.
+					avatarURL := strings.ReplaceAll(avatar[2], "s=64&amp;", "")
+					authors = append(authors, gitAuthor{Login: avatar[1], AvatarURL: avatarURL})
+				}
+			} else {
+				for _, login := range strings.Fields(line) {
+					authors = append(authors, gitAuthor{Login: login})
+				}
+			}
+			current.Commits = append(current.Commits, gitCommit{Authors: authors})
+			continue
+		}
+
+		if len(body) == 0 && strings.TrimSpace(line) == "" {
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	return notes
+}
+
+// FetchReleaseNotes retrieves the published release notes for the provided
+// tag (the body of the release in ModeRelease, or the annotation of the tag in
+// ModeTag), parses them back into the structured model, and re-renders them to
+// the configured writer in the configured format.
+func FetchReleaseNotes(ctx context.Context, tagName string, opts ...Option) error {
+	o := newOptions(opts)
+	config := o.config
+
+	body, err := fetchPublishedBody(ctx, o.cmd, tagName, o.mode)
+	if err != nil {
+		return err
+	}
+
+	notes := parseMarkdownNotes(tagName, body)
+
+	// Render the entries in the categories they were published in, unless
+	// categories have been configured.
+	if len(config.Categories) == 0 {
+		for _, pullRequest := range notes.PullRequests {
+			if !slices.ContainsFunc(config.Categories, func(c CategoryConfig) bool {
+				return c.Title == pullRequest.category.Title
+			}) {
+				config.Categories = append(config.Categories, pullRequest.category)
+			}
+		}
+		if len(config.Categories) == 1 && config.Categories[0].Title == defaultCategory.Title {
+			config.Categories = nil
+		}
+	}
+
+	return config.Format.render(ctx, o.cmd, o.writer, notes, config)
+}
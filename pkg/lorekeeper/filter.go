@@ -0,0 +1,88 @@
+package lorekeeper
+
+import (
+	"errors"
+
+	"github.com/google/cel-go/cel"
+)
+
+// entryFilter decides which pull requests are included in the release notes,
+// by evaluating the configured CEL expression for each.
+type entryFilter struct {
+	program cel.Program
+}
+
+// newEntryFilter compiles the provided CEL expression, returning a filter that
+// includes every pull request if the expression is empty.
+//
+// The expression is passed the pull request as `pr`, with the fields number,
+// title, body, url, labels, authors, branch, files, mergedAt, and draft, i.e -
+// `pr.labels.exists(l, l == "public") && !pr.draft`.
+func newEntryFilter(expression string) (*entryFilter, error) {
+	if expression == "" {
+		return &entryFilter{}, nil
+	}
+
+	env, err := cel.NewEnv(cel.Variable("pr", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		return nil, &FilterError{Expression: expression, Err: err}
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, &FilterError{Expression: expression, Err: issues.Err()}
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, &FilterError{Expression: expression, Err: errors.New("the expression must evaluate to a bool, got " + ast.OutputType().String())}
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, &FilterError{Expression: expression, Err: err}
+	}
+
+	return &entryFilter{program: program}, nil
+}
+
+// includes reports whether the provided pull request is included in the
+// release notes.
+func (f *entryFilter) includes(pullRequest gitPullRequest, config AuthorsConfig) (bool, error) {
+	if f.program == nil {
+		return true, nil
+	}
+
+	var labels, authors, files []string
+	for _, label := range pullRequest.Labels {
+		labels = append(labels, label.Name)
+	}
+	for _, author := range pullRequestAuthors(pullRequest, config) {
+		authors = append(authors, author.Login)
+	}
+	for _, file := range pullRequest.Files {
+		files = append(files, file.Path)
+	}
+
+	out, _, err := f.program.Eval(map[string]any{
+		"pr": map[string]any{
+			"number":   pullRequest.Number,
+			"title":    pullRequest.Title,
+			"body":     pullRequest.Body,
+			"url":      pullRequest.URL,
+			"labels":   labels,
+			"authors":  authors,
+			"branch":   pullRequest.HeadRefName,
+			"files":    files,
+			"mergedAt": pullRequest.MergedAt,
+			"draft":    pullRequest.IsDraft,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	included, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.New("the expression must evaluate to a bool")
+	}
+	return included, nil
+}
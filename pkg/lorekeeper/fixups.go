@@ -0,0 +1,183 @@
+package lorekeeper
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// reFixupTitle matches the title of a pull request following up on an
+	// earlier one, capturing its number, i.e - `Fixup of #123`, `fixup! #123`,
+	// or `Follow-up to #123: fix typo in new flag`.
+	reFixupTitle = regexp.MustCompile(`(?i)\b(?:fixup!?|follow[- ]?up)(?:\s+(?:of|to|for|on))?:?\s+#([0-9]+)\b`)
+
+	// reFixupReference matches a reference to the pull request followed up on
+	// in the body of a pull request, capturing its number, i.e - `Follow-up to
+	// #123` or `Fixup of #123`.
+	reFixupReference = regexp.MustCompile(`(?i)\b(?:fixup|follow[- ]?up)\s+(?:of|to|for|on)\s+#([0-9]+)\b`)
+
+	// reReference matches any reference to a pull request or issue of the same
+	// repository, capturing its number, i.e - `#123`.
+	reReference = regexp.MustCompile(`(?:^|[^&\w])#([0-9]+)\b`)
+)
+
+// FixupsConfig determines whether the pull requests following up on an earlier
+// pull request of the same release, i.e - "fix typo in new flag", are folded
+// into its entry, rather than listed as changes of their own.
+type FixupsConfig struct {
+	// Enabled detects the follow-up pull requests by their titles, i.e -
+	// `Fixup of #123`, or a reference in their bodies, i.e - `Follow-up to
+	// #123`.
+	Enabled bool `yaml:"enabled"`
+
+	// Labels is the list of labels that mark follow-up pull requests, which
+	// are folded into the first pull request they reference, i.e - `fixup`.
+	Labels []string `yaml:"labels"`
+
+	// Title is a regular expression matching the titles of follow-up pull
+	// requests, in place of the default, whose first capture group is the
+	// number of the pull request followed up on, i.e - `^Fixup #([0-9]+)`.
+	Title string `yaml:"title"`
+}
+
+// enabled returns whether follow-up pull requests are detected at all.
+func (c FixupsConfig) enabled() bool {
+	return c.Enabled || len(c.Labels) > 0
+}
+
+// validate checks the title pattern of the follow-ups, if any.
+func (c FixupsConfig) validate() error {
+	_, err := c.titlePattern()
+	return err
+}
+
+// titlePattern compiles the pattern matching the titles of follow-up pull
+// requests, returning the default if there is none, or a
+// FixupTitleInvalidError if it is invalid.
+func (c FixupsConfig) titlePattern() (*regexp.Regexp, error) {
+	if c.Title == "" {
+		return reFixupTitle, nil
+	}
+	reTitle, err := regexp.Compile(c.Title)
+	if err != nil {
+		return nil, &FixupTitleInvalidError{Pattern: c.Title, Err: err}
+	}
+	if reTitle.NumSubexp() < 1 {
+		return nil, &FixupTitleInvalidError{Pattern: c.Title, Reason: "the pattern has no capture group"}
+	}
+	return reTitle, nil
+}
+
+// fixupParent returns the number of the pull request that the provided pull
+// request follows up on, if it is a follow-up: by the title pattern, by a
+// follow-up reference in its body, or by a label, for the first pull request
+// it references.
+func fixupParent(pullRequest gitPullRequest, config FixupsConfig, reTitle *regexp.Regexp) (int, bool) {
+	var candidates [][]string
+	if config.Enabled {
+		candidates = append(candidates,
+			reTitle.FindStringSubmatch(pullRequest.Title),
+			reFixupReference.FindStringSubmatch(pullRequest.Body),
+		)
+	}
+	if slices.ContainsFunc(pullRequest.Labels, func(label gitLabel) bool {
+		return slices.Contains(config.Labels, label.Name)
+	}) {
+		candidates = append(candidates,
+			reReference.FindStringSubmatch(pullRequest.Title),
+			reReference.FindStringSubmatch(pullRequest.Body),
+		)
+	}
+
+	for _, matches := range candidates {
+		if matches == nil {
+			continue
+		}
+		if number, err := strconv.Atoi(matches[1]); err == nil && number != pullRequest.Number {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+// foldFixups folds the follow-up pull requests into the entry of the pull
+// request they follow up on, when it is part of the same release, adding their
+// commits and files to it. Follow-ups of a follow-up are folded into the pull
+// request first followed up on. Any other pull requests are returned as they
+// are.
+func foldFixups(pullRequests []gitPullRequest, config FixupsConfig) ([]gitPullRequest, error) {
+	if !config.enabled() {
+		return pullRequests, nil
+	}
+	reTitle, err := config.titlePattern()
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the pull request that each follow-up follows up on.
+	index := map[int]int{}
+	for idx, pullRequest := range pullRequests {
+		if pullRequest.Number != 0 {
+			index[pullRequest.Number] = idx
+		}
+	}
+	parents := map[int]int{}
+	for idx, pullRequest := range pullRequests {
+		if number, ok := fixupParent(pullRequest, config, reTitle); ok {
+			if parent, ok := index[number]; ok {
+				parents[idx] = parent
+			}
+		}
+	}
+
+	// Resolve the follow-ups of follow-ups to the pull request first followed
+	// up on. Pull requests following up on each other are left as they are.
+	root := func(idx int) int {
+		seen := map[int]bool{idx: true}
+		for current := idx; ; {
+			parent, ok := parents[current]
+			if !ok {
+				return current
+			}
+			if seen[parent] {
+				return idx
+			}
+			seen[parent] = true
+			current = parent
+		}
+	}
+
+	// Fold the follow-ups into their pull requests, keeping the order of the
+	// pull requests.
+	folded := map[int][]gitPullRequest{}
+	for idx, pullRequest := range pullRequests {
+		if parent := root(idx); parent != idx {
+			folded[parent] = append(folded[parent], pullRequest)
+		}
+	}
+	var merged []gitPullRequest
+	for idx, pullRequest := range pullRequests {
+		if root(idx) != idx {
+			continue
+		}
+		for _, fixup := range folded[idx] {
+			pullRequest.fixups = append(pullRequest.fixups, fixup)
+			pullRequest.Commits = append(slices.Clip(pullRequest.Commits), fixup.Commits...)
+			pullRequest.Files = append(slices.Clip(pullRequest.Files), fixup.Files...)
+		}
+		merged = append(merged, pullRequest)
+	}
+	return merged, nil
+}
+
+// fixupsSuffix returns the references to the follow-ups of the provided pull
+// request, as they are rendered after its own, i.e - `, #124, #125`.
+func fixupsSuffix(pullRequest gitPullRequest) string {
+	var rendered strings.Builder
+	for _, fixup := range pullRequest.fixups {
+		rendered.WriteString(", #" + strconv.Itoa(fixup.Number))
+	}
+	return rendered.String()
+}
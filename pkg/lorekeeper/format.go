@@ -0,0 +1,407 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Format is the format that the release notes are rendered in.
+type Format string
+
+const (
+	// FormatMarkdown renders the release notes as markdown.
+	FormatMarkdown Format = "markdown"
+
+	// FormatJSON renders the release notes as structured JSON.
+	FormatJSON Format = "json"
+
+	// FormatHTML renders the release notes as an HTML fragment.
+	FormatHTML Format = "html"
+
+	// FormatInToto renders the release notes as an in-toto statement, for
+	// policy engines that consume build attestations.
+	FormatInToto Format = "in-toto"
+
+	// FormatGitHub renders the release notes as markdown in the style of the
+	// release notes generated by GitHub, with a "What's Changed" list, the new
+	// contributors, and a link to the full changelog.
+	FormatGitHub Format = "github"
+)
+
+// GetFormats returns all the formats that the release notes can be rendered
+// in.
+func GetFormats() []Format {
+	return []Format{
+		FormatMarkdown,
+		FormatJSON,
+		FormatHTML,
+		FormatInToto,
+		FormatGitHub,
+	}
+}
+
+// extension returns the file extension for the format.
+func (f Format) extension() string {
+	switch f {
+	case FormatJSON:
+		return ".json"
+	case FormatHTML:
+		return ".html"
+	case FormatInToto:
+		return ".intoto.json"
+	default:
+		return ".md"
+	}
+}
+
+// render writes the provided release notes to the writer in the format.
+func (f Format) render(ctx context.Context, cmd commander, w io.Writer, notes releaseNotes, config Config) error {
+	// Use the categories generated by GitHub, if none are configured.
+	if len(config.Categories) == 0 {
+		config.Categories = notes.Categories
+	}
+
+	switch f {
+	case FormatMarkdown, "":
+		return renderLintedMarkdown(w, notes, config)
+	case FormatJSON:
+		return renderJSON(w, notes, config)
+	case FormatHTML:
+		return renderHTML(w, notes, config)
+	case FormatInToto:
+		return renderInToto(w, notes, config)
+	case FormatGitHub:
+		return renderGitHub(ctx, cmd, w, notes, config)
+	}
+
+	// Render the custom formats with their plugin.
+	plugin, ok := config.Plugins.formatPlugin(f)
+	if !ok {
+		return &FormatInvalidError{Format: f}
+	}
+	rendered, err := renderPlugin(ctx, cmd, plugin, notes, config)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, rendered)
+	return err
+}
+
+// builtin reports whether the format is one of the built-in formats.
+func (f Format) builtin() bool {
+	return slices.Contains(GetFormats(), f)
+}
+
+// formatExtension returns the file extension for the provided format, or its
+// format plugin.
+func (c Config) formatExtension(f Format) string {
+	if plugin, ok := c.Plugins.formatPlugin(f); ok && !f.builtin() {
+		if plugin.Extension == "" {
+			return ".txt"
+		}
+		return plugin.Extension
+	}
+	return f.extension()
+}
+
+type jsonReleaseNotes struct {
+	TagName          string                 `json:"tagName"`
+	Date             time.Time              `json:"date"`
+	ReleaseCandidate bool                   `json:"releaseCandidate"`
+	PreviousTagName  string                 `json:"previousTagName,omitempty"`
+	Candidates       []string               `json:"releaseCandidates,omitempty"`
+	Thanks           string                 `json:"thanks,omitempty"`
+	Part             int                    `json:"part,omitempty"`
+	Parts            int                    `json:"parts,omitempty"`
+	Entries          []Entry                `json:"entries"`
+	Upgrades         []jsonUpgradeNote      `json:"upgrades,omitempty"`
+	NewContributors  []jsonNewContributor   `json:"newContributors,omitempty"`
+	Advisories       []jsonSecurityAdvisory `json:"advisories,omitempty"`
+	SecurityNotes    []jsonSecurityNote     `json:"securityNotes,omitempty"`
+	DependencyCVEs   []jsonDependencyCVE    `json:"dependencyCVEs,omitempty"`
+	Dependencies     *jsonDependencyChanges `json:"dependencies,omitempty"`
+	Submodules       []jsonSubmoduleChange  `json:"submodules,omitempty"`
+	Vendored         []jsonVendoredChange   `json:"vendored,omitempty"`
+	Assets           []jsonReleaseAsset     `json:"assets,omitempty"`
+	Funding          []jsonFundingLink      `json:"funding,omitempty"`
+	Install          []string               `json:"install,omitempty"`
+	Provenance       *jsonProvenance        `json:"provenance,omitempty"`
+	Header           string                 `json:"header,omitempty"`
+	Footer           string                 `json:"footer,omitempty"`
+}
+
+type jsonUpgradeNote struct {
+	PullRequest int    `json:"pullRequest"`
+	Notes       string `json:"notes"`
+}
+
+type jsonNewContributor struct {
+	Login       string `json:"login"`
+	PullRequest int    `json:"pullRequest"`
+}
+
+type jsonSecurityAdvisory struct {
+	GHSAID      string    `json:"ghsaId"`
+	CVEID       string    `json:"cveId,omitempty"`
+	Summary     string    `json:"summary"`
+	Severity    string    `json:"severity"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+type jsonSecurityNote struct {
+	PullRequest int    `json:"pullRequest,omitempty"`
+	Notes       string `json:"notes"`
+}
+
+type jsonDependencyCVE struct {
+	CVEID       string `json:"cveId"`
+	PullRequest int    `json:"pullRequest"`
+}
+
+type jsonDependencyChanges struct {
+	Added   []jsonDependencyChange `json:"added,omitempty"`
+	Removed []jsonDependencyChange `json:"removed,omitempty"`
+	Updated []jsonDependencyChange `json:"updated,omitempty"`
+}
+
+type jsonDependencyChange struct {
+	Name            string `json:"name"`
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	CurrentVersion  string `json:"currentVersion,omitempty"`
+}
+
+type jsonSubmoduleChange struct {
+	Path           string   `json:"path"`
+	URL            string   `json:"url,omitempty"`
+	PreviousCommit string   `json:"previousCommit,omitempty"`
+	CurrentCommit  string   `json:"currentCommit,omitempty"`
+	Commits        []string `json:"commits,omitempty"`
+}
+
+type jsonVendoredChange struct {
+	Name           string `json:"name"`
+	Path           string `json:"path"`
+	UpstreamCommit string `json:"upstreamCommit,omitempty"`
+	PullRequests   []int  `json:"pullRequests"`
+}
+
+type jsonReleaseAsset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+type jsonFundingLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonProvenance struct {
+	Builder     string `json:"builder"`
+	WorkflowRef string `json:"workflowRef"`
+	CommitSHA   string `json:"commitSha"`
+	RunURL      string `json:"runUrl"`
+}
+
+// renderLintedMarkdown writes the provided release notes to the writer as
+// markdown, using the configured template if any, linted as configured.
+func renderLintedMarkdown(w io.Writer, notes releaseNotes, config Config) error {
+	var markdown strings.Builder
+	if err := config.Header.render(&markdown, "header", notes, config); err != nil {
+		return err
+	}
+	if config.Template != "" {
+		if err := renderTemplate(&markdown, notes, config); err != nil {
+			return err
+		}
+	} else {
+		renderMarkdown(&markdown, notes, config)
+	}
+	if err := config.Footer.render(&markdown, "footer", notes, config); err != nil {
+		return err
+	}
+
+	linted, err := applyLint(markdown.String(), config.Lint)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, linted)
+	return err
+}
+
+// renderJSON writes the provided release notes to the writer as indented JSON.
+func renderJSON(w io.Writer, notes releaseNotes, config Config) error {
+	document, err := newRenderedJSONReleaseNotes(notes, config)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// newRenderedJSONReleaseNotes converts the provided release notes into their
+// structured representation, as described by newJSONReleaseNotes, with the
+// header and footer rendered, for the formats that don't render them around
+// the markdown.
+func newRenderedJSONReleaseNotes(notes releaseNotes, config Config) (jsonReleaseNotes, error) {
+	document := newJSONReleaseNotes(notes, config)
+	var header, footer strings.Builder
+	if err := config.Header.render(&header, "header", notes, config); err != nil {
+		return jsonReleaseNotes{}, err
+	}
+	if err := config.Footer.render(&footer, "footer", notes, config); err != nil {
+		return jsonReleaseNotes{}, err
+	}
+	document.Header, document.Footer = strings.TrimSpace(header.String()), strings.TrimSpace(footer.String())
+	return document, nil
+}
+
+// newJSONReleaseNotes converts the provided release notes into their
+// structured representation, as rendered in JSON and passed to templates.
+func newJSONReleaseNotes(notes releaseNotes, config Config) jsonReleaseNotes {
+	document := jsonReleaseNotes{
+		TagName:          notes.TagName,
+		Date:             notes.Date,
+		ReleaseCandidate: notes.ReleaseCandidate,
+		PreviousTagName:  notes.PreviousRef.TagName,
+		Candidates:       notes.Candidates,
+		Thanks:           notes.Thanks,
+		Install:          config.Install.commands(notes.TagName, notes.ReleaseCandidate),
+		Part:             notes.Part,
+		Parts:            notes.Parts,
+		Entries:          []Entry{},
+	}
+
+	for _, pullRequest := range notes.PullRequests {
+		document.Entries = append(document.Entries, newEntry(pullRequest, config))
+	}
+
+	for _, upgrade := range notes.Upgrades {
+		document.Upgrades = append(document.Upgrades, jsonUpgradeNote{
+			PullRequest: upgrade.PullRequest.Number,
+			Notes:       upgrade.Notes,
+		})
+	}
+
+	for _, contributor := range notes.NewContributors {
+		document.NewContributors = append(document.NewContributors, jsonNewContributor{
+			Login:       contributor.Login,
+			PullRequest: contributor.PullRequest.Number,
+		})
+	}
+
+	for _, advisory := range notes.Advisories {
+		document.Advisories = append(document.Advisories, jsonSecurityAdvisory{
+			GHSAID:      advisory.GHSAID,
+			CVEID:       advisory.CVEID,
+			Summary:     advisory.Summary,
+			Severity:    advisory.Severity,
+			URL:         advisory.HTMLURL,
+			PublishedAt: advisory.PublishedAt,
+		})
+	}
+
+	for _, note := range notes.SecurityNotes {
+		document.SecurityNotes = append(document.SecurityNotes, jsonSecurityNote{
+			PullRequest: note.PullRequest.Number,
+			Notes:       note.Notes,
+		})
+	}
+
+	for _, cve := range notes.DependencyCVEs {
+		document.DependencyCVEs = append(document.DependencyCVEs, jsonDependencyCVE{
+			CVEID:       cve.CVEID,
+			PullRequest: cve.PullRequest.Number,
+		})
+	}
+
+	if !notes.SBOMDiff.empty() {
+		convert := func(changes []sbomChange) []jsonDependencyChange {
+			var converted []jsonDependencyChange
+			for _, change := range changes {
+				converted = append(converted, jsonDependencyChange(change))
+			}
+			return converted
+		}
+		document.Dependencies = &jsonDependencyChanges{
+			Added:   convert(notes.SBOMDiff.Added),
+			Removed: convert(notes.SBOMDiff.Removed),
+			Updated: convert(notes.SBOMDiff.Updated),
+		}
+	}
+
+	for _, change := range notes.Submodules {
+		submodule := jsonSubmoduleChange{
+			Path:           change.Path,
+			URL:            change.URL,
+			PreviousCommit: change.PreviousCommit,
+			CurrentCommit:  change.CurrentCommit,
+		}
+		for _, commit := range change.Commits {
+			submodule.Commits = append(submodule.Commits, commit.SHA+" "+commit.Subject)
+		}
+		document.Submodules = append(document.Submodules, submodule)
+	}
+
+	for _, change := range notes.Vendored {
+		vendored := jsonVendoredChange{
+			Name:           change.Vendored.Name,
+			Path:           change.Vendored.Path,
+			UpstreamCommit: change.UpstreamCommit,
+		}
+		for _, pullRequest := range change.PullRequests {
+			vendored.PullRequests = append(vendored.PullRequests, pullRequest.Number)
+		}
+		document.Vendored = append(document.Vendored, vendored)
+	}
+
+	for _, asset := range notes.Assets {
+		document.Assets = append(document.Assets, jsonReleaseAsset(asset))
+	}
+
+	for _, link := range notes.Funding {
+		document.Funding = append(document.Funding, jsonFundingLink(link))
+	}
+
+	if notes.Provenance != nil {
+		provenance := jsonProvenance(*notes.Provenance)
+		document.Provenance = &provenance
+	}
+
+	return document
+}
+
+// renderHTML writes the provided release notes to the writer as an HTML
+// fragment, converted from the markdown release notes.
+func renderHTML(w io.Writer, notes releaseNotes, config Config) error {
+	var markdown bytes.Buffer
+	if err := renderLintedMarkdown(&markdown, notes, config); err != nil {
+		return err
+	}
+
+	converter := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	if err := converter.Convert(markdown.Bytes(), w); err != nil {
+		return &OperationError{Op: "convert the release notes to html", Err: err}
+	}
+
+	return nil
+}
+
+func getFormatNamesString() string {
+	var formatNames []string
+	for _, format := range GetFormats() {
+		formatNames = append(formatNames, string(format))
+	}
+	return strings.Join(formatNames, ", ")
+}
@@ -0,0 +1,613 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"iter"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"gopkg.in/yaml.v3"
+)
+
+// FragmentsFormat is the format of the fragment files that the entries of the
+// release notes are read from.
+type FragmentsFormat string
+
+const (
+	// FragmentsChangesets reads the `.changeset/*.md` files of the changesets
+	// tool, categorised by their highest semver bump.
+	FragmentsChangesets FragmentsFormat = "changesets"
+
+	// FragmentsTowncrier reads the `newsfragments/<number>.<type>.md` files
+	// of the towncrier tool, categorised by their type.
+	FragmentsTowncrier FragmentsFormat = "towncrier"
+
+	// FragmentsReno reads the `releasenotes/notes/*.yaml` files of the reno
+	// tool that were added since the previous release, categorised by their
+	// sections.
+	FragmentsReno FragmentsFormat = "reno"
+)
+
+// GetFragmentsFormats returns the formats of the fragment files that the
+// entries can be read from.
+func GetFragmentsFormats() []FragmentsFormat {
+	return []FragmentsFormat{FragmentsChangesets, FragmentsTowncrier, FragmentsReno}
+}
+
+// fragmentsFormat describes how the fragments of a format are read.
+type fragmentsFormat struct {
+	// dir is the directory of the fragments, relative to the root of the
+	// repository, unless another is configured.
+	dir string
+
+	// parse parses the fragments of the file at the provided path, relative
+	// to the directory, returning none if the file isn't a fragment.
+	parse func(name string, data []byte) ([]fragment, error)
+
+	// categories are the categories of the fragments, in order.
+	categories []CategoryConfig
+
+	// ranged reads only the fragments added since the previous release, as
+	// they're kept across releases rather than consumed.
+	ranged bool
+}
+
+// fragmentsFormats are the formats of the fragment files, by name.
+var fragmentsFormats = map[FragmentsFormat]fragmentsFormat{
+	FragmentsChangesets: {
+		dir:        ".changeset",
+		parse:      parseChangeset,
+		categories: changesetCategories,
+	},
+	FragmentsTowncrier: {
+		dir:        "newsfragments",
+		parse:      parseNewsFragment,
+		categories: newsFragmentCategories,
+	},
+	FragmentsReno: {
+		dir:        "releasenotes/notes",
+		parse:      parseRenoNote,
+		categories: renoCategories,
+		ranged:     true,
+	},
+}
+
+// FragmentsConfig determines whether the entries of the release notes are
+// read from fragment files, added alongside the changes, instead of from the
+// bodies of the pull requests.
+type FragmentsConfig struct {
+	// Format is the format of the fragment files, i.e - "changesets". Each
+	// fragment is an entry, with the title and authors of the pull request
+	// that added it, if any, and its category from the fragment.
+	Format FragmentsFormat `yaml:"format"`
+
+	// Dir is the directory of the fragment files, relative to the root of the
+	// repository. Defaults to that of the format, i.e - `.changeset`.
+	Dir string `yaml:"dir"`
+
+	// Delete deletes the fragment files once the release notes have been
+	// made from them, ready to be committed with the release. It can't be
+	// used with formats whose fragments are kept across releases, i.e - reno.
+	Delete bool `yaml:"delete"`
+}
+
+// validate checks the format of the fragment files, and that they can be
+// deleted, if configured.
+func (c FragmentsConfig) validate() error {
+	format, ok := fragmentsFormats[c.Format]
+	if c.Format != "" && !ok {
+		return &FragmentsFormatInvalidError{Format: c.Format}
+	}
+	if c.Delete && format.ranged {
+		return &FragmentsDeleteInvalidError{Format: c.Format}
+	}
+	return nil
+}
+
+// dir returns the configured, or default, directory of the fragment files.
+func (c FragmentsConfig) dir() string {
+	if c.Dir == "" {
+		return fragmentsFormats[c.Format].dir
+	}
+	return c.Dir
+}
+
+// fragmentNotice is a section of the release notes, besides the entries, that
+// a fragment may belong to.
+type fragmentNotice string
+
+const (
+	// fragmentUpgrade is a fragment of the upgrade notes.
+	fragmentUpgrade fragmentNotice = "upgrade"
+
+	// fragmentSecurity is a fragment of the security section.
+	fragmentSecurity fragmentNotice = "security"
+)
+
+// fragment represents an entry of the release notes, read from a fragment
+// file.
+type fragment struct {
+	// path is the path of the fragment file, relative to the root of the
+	// repository.
+	path string
+
+	// category is the category of the entry.
+	category CategoryConfig
+
+	// body is the markdown of the entry.
+	body string
+
+	// pullRequest is the number of the pull request that the fragment
+	// names, if any, i.e - from its file name.
+	pullRequest int
+
+	// notice is the section of the release notes that the fragment belongs
+	// to instead of the entries, if any, i.e - the upgrade notes of a reno
+	// note.
+	notice fragmentNotice
+}
+
+// readFragments reads the fragments of the configured directory, ordered by
+// their path, as described by fragmentFiles. The fragments of a ranged format
+// are only read if they were added between the previous reference, if any,
+// and the tag.
+func readFragments(ctx context.Context, cmd commander, config FragmentsConfig, previousTagName, tagName string) ([]fragment, error) {
+	format := fragmentsFormats[config.Format]
+
+	// Find the fragments added since the previous release.
+	var added map[string]bool
+	if format.ranged {
+		var err error
+		added, err = fragmentsAdded(ctx, cmd, config.dir(), previousTagName, tagName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := fragmentFiles(ctx, cmd, config.dir(), tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	var fragments []fragment
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		path := filepath.Join(config.dir(), name)
+		if added != nil && !added[filepath.ToSlash(path)] {
+			continue
+		}
+		parsed, err := format.parse(name, files[name])
+		if err != nil {
+			return nil, &FragmentError{Path: path, Err: err}
+		}
+		for _, fragment := range parsed {
+			fragment.path = path
+			fragments = append(fragments, fragment)
+		}
+	}
+	return fragments, nil
+}
+
+// fragmentFiles returns the contents of the files in the provided directory,
+// relative to the root of the repository, by their slash separated paths
+// relative to the directory. The files are read at the tag, if it has been
+// created, or from the worktree otherwise, i.e - for the unreleased changes,
+// whose fragments may not have been committed yet.
+func fragmentFiles(ctx context.Context, cmd commander, dir, tagName string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	// Read the files at the tag.
+	if _, err := cmd.run(ctx, "git", "rev-parse", "--verify", "--quiet", tagName+"^{commit}"); err == nil {
+		paths, err := cmd.run(ctx, "git", "ls-tree", "-r", "-z", "--name-only", "--full-tree", tagName, "--", dir)
+		if err != nil {
+			return nil, &OperationError{Op: "list the fragments", Ref: tagName, Err: err}
+		}
+		prefix := path.Clean(filepath.ToSlash(dir)) + "/"
+		for file := range strings.SplitSeq(strings.TrimSuffix(paths, "\x00"), "\x00") {
+			if file == "" {
+				continue
+			}
+			data, err := cmd.run(ctx, "git", "show", tagName+":"+file)
+			if err != nil {
+				return nil, &FragmentError{Path: file, Err: err}
+			}
+			files[strings.TrimPrefix(file, prefix)] = []byte(data)
+		}
+		return files, nil
+	}
+
+	// Otherwise, read the files of the worktree.
+	root := worktreePath(ctx, cmd, dir)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(name)] = data
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// fragmentsAdded returns the paths, relative to the root of the repository, of
+// the files added to the provided directory between the previous tag, if any,
+// and the tag, or the HEAD commit if the tag hasn't been created yet, i.e - a
+// release train.
+func fragmentsAdded(ctx context.Context, cmd commander, dir, previousTagName, tagName string) (map[string]bool, error) {
+	revision := tagName
+	if _, err := cmd.run(ctx, "git", "rev-parse", "--verify", "--quiet", tagName+"^{commit}"); err != nil {
+		revision = "HEAD"
+	}
+	revisions := revision
+	if previousTagName != "" {
+		revisions = previousTagName + ".." + revision
+	}
+
+	paths, err := cmd.run(ctx, "git", "log", "--diff-filter=A", "--name-only", "--format=", revisions, "--", dir)
+	if err != nil {
+		return nil, &OperationError{Op: "list the added fragments", Ref: revisions, Err: err}
+	}
+
+	added := map[string]bool{}
+	for path := range strings.FieldsSeq(paths) {
+		added[path] = true
+	}
+	return added, nil
+}
+
+// fragmentFilePullRequest returns the details of the pull request that added
+// the file of the provided fragment, named by the fragment or found from its
+// history, reporting false if there is none. The number named by a fragment
+// may be that of an issue, i.e - with towncrier, so if no pull request of that
+// number is found, the fragment is read without one.
+func fragmentFilePullRequest(ctx context.Context, o options, fragment fragment) (gitPullRequest, bool, error) {
+	number := fragment.pullRequest
+	if number == 0 {
+		number = fragmentAddedBy(ctx, o, fragment.path)
+	}
+	if number == 0 {
+		return gitPullRequest{}, false, nil
+	}
+
+	pullRequest, err := fetchPullRequest(ctx, o, strconv.Itoa(number))
+	if err != nil && fragment.pullRequest != 0 {
+		log.Warn("No pull request found for the number of the fragment, reading it without one", "path", fragment.path, "number", number, "err", err)
+		return gitPullRequest{}, false, nil
+	}
+	if err != nil {
+		return gitPullRequest{}, false, err
+	}
+	return pullRequest, true, nil
+}
+
+// fragmentPullRequest returns the entry of the provided fragment, with the
+// details of the provided pull request that added it, if found. The body of
+// the pull request is replaced with that of the fragment. Otherwise, the first
+// line of the fragment is the title, unless it belongs to another section,
+// which has no titles.
+func fragmentPullRequest(o options, fragment fragment, pullRequest gitPullRequest, found bool) gitPullRequest {
+	pullRequest.notice = fragment.notice
+	if found || fragment.notice != "" {
+		pullRequest.Body = fragment.body
+		return pullRequest
+	}
+
+	pullRequest.Title, pullRequest.Body, _ = strings.Cut(fragment.body, "\n")
+	pullRequest.Body = strings.TrimSpace(pullRequest.Body)
+	return pullRequest.withRewrittenTitle(o.titleRewrites)
+}
+
+// fetchFragments returns an iterator over the entries of the provided
+// fragments, as described by fragmentPullRequest, in order. Like the pull
+// requests, up to the configured concurrency of entries are fetched in
+// parallel, and they are categorised, as described by fragmentCategory, and
+// filtered.
+func fetchFragments(ctx context.Context, o options, fragments []fragment, categoriser *categoriser, filter *entryFilter, progress *progress) iter.Seq2[gitPullRequest, error] {
+	return func(yield func(gitPullRequest, error) bool) {
+		// Fetch the pull request of each file once, as a file may hold
+		// several fragments.
+		var files sync.Map
+		fetch := func(ctx context.Context, i int) (gitPullRequest, error) {
+			type fetched struct {
+				pullRequest gitPullRequest
+				found       bool
+			}
+			file, _ := files.LoadOrStore(fragments[i].path, sync.OnceValues(func() (fetched, error) {
+				pullRequest, found, err := fragmentFilePullRequest(ctx, o, fragments[i])
+				return fetched{pullRequest: pullRequest, found: found}, err
+			}))
+			result, err := file.(func() (fetched, error))()
+			if err != nil {
+				return gitPullRequest{}, err
+			}
+			return withLinkedIssues(ctx, o, fragmentPullRequest(o, fragments[i], result.pullRequest, result.found)), nil
+		}
+		reached := func(i int) {
+			progress.report("Reading fragments %d/%d (%s)", i+1, len(fragments), fragments[i].path)
+		}
+		for i, result := range fetchOrdered(ctx, o.concurrency, len(fragments), fetch, reached) {
+			if result.err != nil {
+				yield(gitPullRequest{}, result.err)
+				return
+			}
+			pullRequest := result.value
+			pullRequest.category = fragmentCategory(fragments[i], pullRequest, o.config.Categories, categoriser)
+
+			// Skip the entry if it is filtered out.
+			included, err := filter.includes(pullRequest, o.config.Authors)
+			if err != nil {
+				yield(gitPullRequest{}, &FragmentError{Path: fragments[i].path, Err: err})
+				return
+			}
+			if !included {
+				log.Debug("Filtered out fragment", "path", fragments[i].path, "number", pullRequest.Number)
+				continue
+			}
+
+			if !yield(pullRequest, nil) {
+				return
+			}
+		}
+	}
+}
+
+// splitFragmentNotices splits the entries read from the fragment files that
+// belong to the upgrade notes, or the security section, from the provided pull
+// requests, returning the rest of the pull requests, and the notes of each
+// section, in order.
+func splitFragmentNotices(pullRequests []gitPullRequest) ([]gitPullRequest, []upgradeNote, []securityNote) {
+	var (
+		entries  []gitPullRequest
+		upgrades []upgradeNote
+		security []securityNote
+	)
+	for _, pullRequest := range pullRequests {
+		switch pullRequest.notice {
+		case fragmentUpgrade:
+			upgrades = append(upgrades, upgradeNote{PullRequest: pullRequest, Notes: pullRequest.Body})
+		case fragmentSecurity:
+			security = append(security, securityNote{PullRequest: pullRequest, Notes: pullRequest.Body})
+		default:
+			entries = append(entries, pullRequest)
+		}
+	}
+	return entries, upgrades, security
+}
+
+// fragmentCategory returns the category of the entry of the provided fragment:
+// that of the fragment, unless categories are configured, in which case the
+// configured category of the same title, if any, or else the category of its
+// pull request.
+func fragmentCategory(fragment fragment, pullRequest gitPullRequest, categories []CategoryConfig, categoriser *categoriser) CategoryConfig {
+	if len(categories) == 0 {
+		return fragment.category
+	}
+	for _, category := range categories {
+		if strings.EqualFold(category.Title, fragment.category.Title) {
+			return category
+		}
+	}
+	return categoriser.categorise(pullRequest)
+}
+
+// fragmentAddedBy returns the number of the pull request that added the
+// fragment at the provided path, or 0 if it can't be found, i.e - it hasn't
+// been committed.
+func fragmentAddedBy(ctx context.Context, o options, path string) int {
+	commit, err := o.cmd.run(ctx, "git", "log", "-n", "1", "--diff-filter=A", "--format=%H%x1f%s", "--", path)
+	if err != nil {
+		return 0
+	}
+	sha, subject, ok := strings.Cut(strings.TrimSpace(commit), "\x1f")
+	if !ok {
+		return 0
+	}
+
+	// Read the pull request from a squash merge commit, or ask the forge.
+	number, ok := pullRequestNumber(subject)
+	if !ok && !o.apiOnly {
+		numbers, err := o.provider.CommitPullRequests(ctx, sha)
+		if err != nil {
+			log.Debug("Failed to find the pull request of the fragment", "path", path, "err", err)
+			return 0
+		}
+		number, _, _ = strings.Cut(strings.TrimSpace(numbers), "\n")
+	}
+	n, _ := strconv.Atoi(number)
+	return n
+}
+
+// categories returns the categories of the fragments of the configured
+// format.
+func (c FragmentsConfig) categories() []CategoryConfig {
+	return fragmentsFormats[c.Format].categories
+}
+
+// deleteFragments deletes the fragment files of the release notes.
+func deleteFragments(ctx context.Context, cmd commander, paths []string) error {
+	for _, path := range paths {
+		if err := os.Remove(worktreePath(ctx, cmd, path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return &FragmentError{Path: path, Err: err}
+		}
+	}
+	return nil
+}
+
+// changesetCategories are the categories of changesets, by their highest
+// semver bump, as in the changelogs of the changesets tool.
+var changesetCategories = []CategoryConfig{
+	{Title: "Major Changes", Weight: 0},
+	{Title: "Minor Changes", Weight: 1},
+	{Title: "Patch Changes", Weight: 2},
+}
+
+// parseChangeset parses a changeset: markdown, with front matter of the
+// semver bump of each package, i.e - `"pkg": minor`. The README.md of the
+// directory, and empty changesets, aren't fragments.
+func parseChangeset(name string, data []byte) ([]fragment, error) {
+	if !strings.HasSuffix(name, ".md") || strings.EqualFold(name, "README.md") {
+		return nil, nil
+	}
+
+	// Split the front matter from the summary.
+	text := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	frontMatter, summary, ok := strings.Cut(strings.TrimPrefix(text, "---"), "\n---")
+	if !strings.HasPrefix(text, "---\n") || !ok {
+		return nil, errors.New("a changeset must start with front matter between --- lines")
+	}
+	summary = strings.TrimSpace(summary)
+
+	var bumps map[string]string
+	if err := yaml.Unmarshal([]byte(frontMatter), &bumps); err != nil {
+		return nil, err
+	}
+	if len(bumps) == 0 || summary == "" {
+		return nil, nil
+	}
+
+	// Categorise the changeset by its highest bump.
+	category := len(changesetCategories) - 1
+	for _, bump := range bumps {
+		idx := slices.Index([]string{"major", "minor", "patch"}, strings.ToLower(bump))
+		if idx == -1 {
+			return nil, errors.New("invalid bump " + strconv.Quote(bump) + ": expected major, minor, or patch")
+		}
+		category = min(category, idx)
+	}
+
+	return []fragment{{category: changesetCategories[category], body: summary}}, nil
+}
+
+// newsFragmentTypes are the types of towncrier news fragments, as in the
+// default configuration of towncrier.
+var newsFragmentTypes = []string{"feature", "bugfix", "doc", "removal", "misc"}
+
+// newsFragmentCategories are the categories of towncrier news fragments, in
+// the order of newsFragmentTypes.
+var newsFragmentCategories = []CategoryConfig{
+	{Title: "Features", Weight: 0},
+	{Title: "Bugfixes", Weight: 1},
+	{Title: "Improved Documentation", Weight: 2},
+	{Title: "Deprecations and Removals", Weight: 3},
+	{Title: "Misc", Weight: 4},
+}
+
+// parseNewsFragment parses a towncrier news fragment, named
+// `<number>.<type>[.<counter>][.md]`, i.e - `1234.feature.md`, whose number is
+// that of its pull request, or `+<name>.<type>.md` for fragments without one.
+// Files of other names, i.e - a `.gitignore`, aren't fragments.
+func parseNewsFragment(name string, data []byte) ([]fragment, error) {
+	base := path.Base(name)
+	for _, extension := range []string{".md", ".rst", ".txt"} {
+		base = strings.TrimSuffix(base, extension)
+	}
+
+	// Find the type, which may be followed by a counter.
+	parts := strings.Split(base, ".")
+	if len(parts) < 2 {
+		return nil, nil
+	}
+	category := slices.Index(newsFragmentTypes, parts[1])
+	if category == -1 || len(parts) > 3 {
+		return nil, nil
+	}
+	if len(parts) == 3 {
+		if _, err := strconv.Atoi(parts[2]); err != nil {
+			return nil, nil
+		}
+	}
+
+	number, err := strconv.Atoi(parts[0])
+	if err != nil && !strings.HasPrefix(parts[0], "+") {
+		return nil, nil
+	}
+
+	body := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if body == "" && number == 0 {
+		return nil, nil
+	}
+	return []fragment{{category: newsFragmentCategories[category], body: body, pullRequest: number}}, nil
+}
+
+// renoSections are the sections of reno notes, in the order of
+// renoCategories.
+var renoSections = []string{"prelude", "features", "issues", "upgrade", "deprecations", "critical", "security", "fixes", "other"}
+
+// renoNotices are the sections of the release notes that the notes of reno
+// sections belong to, instead of the entries.
+var renoNotices = map[string]fragmentNotice{
+	"upgrade":  fragmentUpgrade,
+	"security": fragmentSecurity,
+}
+
+// renoCategories are the categories of reno notes, by their sections, as in
+// the release notes of the reno tool.
+var renoCategories = []CategoryConfig{
+	{Title: "Prelude", Weight: 0},
+	{Title: "New Features", Weight: 1},
+	{Title: "Known Issues", Weight: 2},
+	{Title: "Upgrade Notes", Weight: 3},
+	{Title: "Deprecation Notes", Weight: 4},
+	{Title: "Critical Issues", Weight: 5},
+	{Title: "Security Issues", Weight: 6},
+	{Title: "Bug Fixes", Weight: 7},
+	{Title: "Other Notes", Weight: 8},
+}
+
+// parseRenoNote parses a reno note: YAML, of a list of notes for each section,
+// i.e - `features: [...]`, or a single note for the prelude. Each note is a
+// fragment. Files other than `.yaml` and `.yml` aren't notes.
+func parseRenoNote(name string, data []byte) ([]fragment, error) {
+	if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+		return nil, nil
+	}
+
+	var sections map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &sections); err != nil {
+		return nil, err
+	}
+
+	// Check the sections, then read the notes of each, in order.
+	for section := range sections {
+		if !slices.Contains(renoSections, section) {
+			return nil, errors.New("invalid section " + strconv.Quote(section) + ": expected one of " + strings.Join(renoSections, ", "))
+		}
+	}
+	var fragments []fragment
+	for i, section := range renoSections {
+		node, ok := sections[section]
+		if !ok {
+			continue
+		}
+		var notes []string
+		if node.Kind == yaml.ScalarNode {
+			notes = []string{node.Value}
+		} else if err := node.Decode(&notes); err != nil {
+			return nil, errors.New("invalid section " + strconv.Quote(section) + ": expected a list of notes")
+		}
+		for _, note := range notes {
+			if note = strings.TrimSpace(note); note != "" {
+				fragments = append(fragments, fragment{category: renoCategories[i], body: note, notice: renoNotices[section]})
+			}
+		}
+	}
+	return fragments, nil
+}
@@ -0,0 +1,203 @@
+package lorekeeper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseChangeset(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		want    []fragment
+		wantErr bool
+	}{
+		{
+			name: "minor",
+			file: "brave-cats-dance.md",
+			data: "---\n\"pkg\": minor\n---\n\nAdd a new option.\n",
+			want: []fragment{{category: changesetCategories[1], body: "Add a new option."}},
+		},
+		{
+			name: "highest bump",
+			file: "brave-cats-dance.md",
+			data: "---\n\"pkg\": patch\n\"other\": Major\n---\nRemove the old option.",
+			want: []fragment{{category: changesetCategories[0], body: "Remove the old option."}},
+		},
+		{
+			name: "carriage returns",
+			file: "brave-cats-dance.md",
+			data: "---\r\n\"pkg\": patch\r\n---\r\nFix a bug.\r\n",
+			want: []fragment{{category: changesetCategories[2], body: "Fix a bug."}},
+		},
+		{name: "readme", file: "README.md", data: "# Changesets"},
+		{name: "not markdown", file: "config.json", data: "{}"},
+		{name: "empty", file: "brave-cats-dance.md", data: "---\n---\n"},
+		{name: "no summary", file: "brave-cats-dance.md", data: "---\n\"pkg\": patch\n---\n"},
+		{name: "no front matter", file: "brave-cats-dance.md", data: "Fix a bug.", wantErr: true},
+		{name: "invalid bump", file: "brave-cats-dance.md", data: "---\n\"pkg\": huge\n---\nFix a bug.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChangeset(tt.file, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChangeset(%q) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseChangeset(%q) = %+v, want %+v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNewsFragment(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want []fragment
+	}{
+		{
+			name: "feature",
+			file: "1234.feature.md",
+			data: "Add a new option.\n",
+			want: []fragment{{category: newsFragmentCategories[0], body: "Add a new option.", pullRequest: 1234}},
+		},
+		{
+			name: "counter",
+			file: "newsfragments/1234.bugfix.2.rst",
+			data: "Fix a bug.",
+			want: []fragment{{category: newsFragmentCategories[1], body: "Fix a bug.", pullRequest: 1234}},
+		},
+		{
+			name: "orphan",
+			file: "+cleanup.misc.md",
+			data: "Tidy up.",
+			want: []fragment{{category: newsFragmentCategories[4], body: "Tidy up."}},
+		},
+		{
+			name: "empty with a number",
+			file: "1234.doc",
+			want: []fragment{{category: newsFragmentCategories[2], pullRequest: 1234}},
+		},
+		{name: "empty orphan", file: "+cleanup.misc.md"},
+		{name: "unknown type", file: "1234.chore.md", data: "Tidy up."},
+		{name: "invalid counter", file: "1234.feature.two.md", data: "Add a new option."},
+		{name: "no number", file: "cleanup.misc.md", data: "Tidy up."},
+		{name: "no type", file: ".gitignore"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNewsFragment(tt.file, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("parseNewsFragment(%q) error = %v", tt.file, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNewsFragment(%q) = %+v, want %+v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRenoNote(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		want    []fragment
+		wantErr bool
+	}{
+		{
+			name: "sections in order",
+			file: "add-option-0123456789abcdef.yaml",
+			data: "fixes:\n  - Fix a bug.\nfeatures:\n  - Add a new option.\n  - Add another.\n",
+			want: []fragment{
+				{category: renoCategories[1], body: "Add a new option."},
+				{category: renoCategories[1], body: "Add another."},
+				{category: renoCategories[7], body: "Fix a bug."},
+			},
+		},
+		{
+			name: "prelude",
+			file: "release-0123456789abcdef.yml",
+			data: "prelude: >\n  A big release.\n",
+			want: []fragment{{category: renoCategories[0], body: "A big release."}},
+		},
+		{
+			name: "notices",
+			file: "upgrade-0123456789abcdef.yaml",
+			data: "upgrade:\n  - Rename the option.\nsecurity:\n  - Escape the output.\n",
+			want: []fragment{
+				{category: renoCategories[3], body: "Rename the option.", notice: fragmentUpgrade},
+				{category: renoCategories[6], body: "Escape the output.", notice: fragmentSecurity},
+			},
+		},
+		{name: "blank notes", file: "blank-0123456789abcdef.yaml", data: "other:\n  - \"  \"\n"},
+		{name: "not yaml", file: "README.md", data: "# Notes"},
+		{name: "unknown section", file: "bad-0123456789abcdef.yaml", data: "chores:\n  - Tidy up.\n", wantErr: true},
+		{name: "invalid notes", file: "bad-0123456789abcdef.yaml", data: "fixes:\n  bug: Fix a bug.\n", wantErr: true},
+		{name: "invalid yaml", file: "bad-0123456789abcdef.yaml", data: "fixes: [", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRenoNote(tt.file, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRenoNote(%q) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRenoNote(%q) = %+v, want %+v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFragmentFiles(t *testing.T) {
+	dir := newTestRepository(t, []testCommit{
+		{
+			subject: "Add the fragments",
+			at:      "2026-01-01T00:00:00Z",
+			files:   map[string]string{"docs/changes/a.md": "a", "docs/changes/sub/b.md": "b"},
+			tag:     "v1.0.0",
+		},
+	})
+
+	// Add an uncommitted fragment, which is only read from the worktree.
+	if err := os.WriteFile(filepath.Join(dir, "docs", "changes", "sub", "c.md"), []byte("c"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The directory is given with the separator of the OS, i.e - backslashes
+	// on Windows, but the files are always keyed by their slash separated
+	// paths.
+	tests := []struct {
+		name    string
+		tagName string
+		want    map[string][]byte
+	}{
+		{
+			name:    "at the tag",
+			tagName: "v1.0.0",
+			want:    map[string][]byte{"a.md": []byte("a"), "sub/b.md": []byte("b")},
+		},
+		{
+			name:    "in the worktree",
+			tagName: "v1.1.0",
+			want:    map[string][]byte{"a.md": []byte("a"), "sub/b.md": []byte("b"), "sub/c.md": []byte("c")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fragmentFiles(context.Background(), commander{repoPath: dir}, filepath.Join("docs", "changes"), tt.tagName)
+			if err != nil {
+				t.Fatalf("fragmentFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fragmentFiles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
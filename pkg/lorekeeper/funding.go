@@ -0,0 +1,136 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultFundingPath is the path, relative to the root of the repository, of
+// the funding file when no path has been configured.
+const defaultFundingPath = ".github/FUNDING.yml"
+
+// FundingConfig determines the content of the sponsorship section of the
+// published release notes.
+type FundingConfig struct {
+	// Enabled appends a call to sponsor the project, with the links of the
+	// funding file, to the published release notes.
+	Enabled bool `yaml:"enabled"`
+
+	// Path is the path, relative to the root of the repository, of the
+	// funding file. Defaults to `.github/FUNDING.yml`.
+	Path string `yaml:"path"`
+}
+
+// fundingPlatform is a platform of the funding file, with the name it is
+// rendered with, and the URL of an account on it.
+type fundingPlatform struct {
+	name string
+	url  string
+}
+
+// fundingPlatforms are the platforms of the funding file, in the order they
+// are rendered, keyed by their key in the file.
+var fundingPlatforms = []struct {
+	key string
+	fundingPlatform
+}{
+	{"github", fundingPlatform{"GitHub Sponsors", "https://github.com/sponsors/%s"}},
+	{"open_collective", fundingPlatform{"Open Collective", "https://opencollective.com/%s"}},
+	{"patreon", fundingPlatform{"Patreon", "https://www.patreon.com/%s"}},
+	{"ko_fi", fundingPlatform{"Ko-fi", "https://ko-fi.com/%s"}},
+	{"liberapay", fundingPlatform{"Liberapay", "https://liberapay.com/%s"}},
+	{"buy_me_a_coffee", fundingPlatform{"Buy Me a Coffee", "https://buymeacoffee.com/%s"}},
+	{"polar", fundingPlatform{"Polar", "https://polar.sh/%s"}},
+	{"thanks_dev", fundingPlatform{"thanks.dev", "https://thanks.dev/%s"}},
+	{"tidelift", fundingPlatform{"Tidelift", "https://tidelift.com/funding/github/%s"}},
+	{"issuehunt", fundingPlatform{"IssueHunt", "https://issuehunt.io/r/%s"}},
+	{"lfx_crowdfunding", fundingPlatform{"LFX Crowdfunding", "https://crowdfunding.lfx.linuxfoundation.org/projects/%s"}},
+	{"community_bridge", fundingPlatform{"LFX Crowdfunding", "https://crowdfunding.lfx.linuxfoundation.org/projects/%s"}},
+	{"custom", fundingPlatform{"", "%s"}},
+}
+
+// fundingLink represents a link to sponsor the project.
+type fundingLink struct {
+	Name string
+	URL  string
+}
+
+// getFundingLinks reads the links to sponsor the project from the configured
+// funding file, in the order of fundingPlatforms.
+func getFundingLinks(ctx context.Context, cmd commander, config FundingConfig) ([]fundingLink, error) {
+	path := config.Path
+	if path == "" {
+		path = defaultFundingPath
+	}
+
+	if !filepath.IsAbs(path) {
+		path = worktreePath(ctx, cmd, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &FundingError{Path: path, Err: err}
+	}
+
+	// Each platform has an account, or a list of accounts.
+	var accounts map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &accounts); err != nil {
+		return nil, &FundingError{Path: path, Err: err}
+	}
+
+	var links []fundingLink
+	for _, platform := range fundingPlatforms {
+		node, ok := accounts[platform.key]
+		if !ok {
+			continue
+		}
+		var names []string
+		if node.Kind == yaml.SequenceNode {
+			err = node.Decode(&names)
+		} else {
+			var name string
+			err = node.Decode(&name)
+			names = []string{name}
+		}
+		if err != nil {
+			return nil, &FundingError{Path: path, Err: err}
+		}
+
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			// Tell apart multiple accounts on the same platform by name.
+			link := fundingLink{Name: platform.name, URL: fmt.Sprintf(platform.url, name)}
+			switch {
+			case link.Name == "":
+				link.Name = link.URL
+			case len(names) > 1:
+				link.Name = fmt.Sprintf("%s (%s)", platform.name, name)
+			}
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// renderMarkdownFunding writes the sponsorship section of the release notes to
+// the writer as markdown, if there are any links to sponsor the project.
+func renderMarkdownFunding(w io.Writer, links []fundingLink) {
+	if len(links) == 0 {
+		return
+	}
+
+	fmt.Fprint(w, "# Sponsor\n\n")
+	fmt.Fprint(w, "If this project is useful to you, please consider supporting its development:\n\n")
+	for _, link := range links {
+		fmt.Fprintf(w, "- [%s](%s)\n", link.Name, link.URL)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,143 @@
+package lorekeeper
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// reGeneratedEntry matches an entry of the release notes generated by
+	// GitHub, capturing the number of its pull request.
+	reGeneratedEntry = regexp.MustCompile(`^\* .* in \S+/pull/([0-9]+)\s*$`)
+
+	// reGeneratedNewContributor matches a new contributor of the release notes
+	// generated by GitHub, capturing their login and the number of their first
+	// pull request.
+	reGeneratedNewContributor = regexp.MustCompile(`^\* @(\S+) made their first contribution in \S+/pull/([0-9]+)\s*$`)
+)
+
+// GenerateNotesConfig determines whether the release notes generated by
+// GitHub are merged with lorekeeper's, for their categories and new
+// contributors.
+type GenerateNotesConfig struct {
+	// Enabled calls GitHub's release notes generation for the release. Its
+	// categories, from `.github/release.yml`, are assigned to the entries when
+	// no categories are configured, and its new contributors are used in place
+	// of searching for them.
+	Enabled bool `yaml:"enabled"`
+
+	// ConfigurationFile is the path, in the repository, of the configuration
+	// of the generated release notes. Defaults to `.github/release.yml`.
+	ConfigurationFile string `yaml:"configurationFile"`
+}
+
+// generatedNotes represents the parts of the release notes generated by GitHub
+// that are merged with lorekeeper's.
+type generatedNotes struct {
+	// categories is the title of the category of each pull request, by number.
+	categories map[int]string
+
+	// categoryTitles are the titles of the categories, in the order generated.
+	categoryTitles []string
+
+	// newContributors is the number of the first pull request of each new
+	// contributor, by login, in the order generated.
+	newContributors []generatedNewContributor
+}
+
+// generatedNewContributor is a new contributor of the generated release notes.
+type generatedNewContributor struct {
+	login  string
+	number int
+}
+
+// getGeneratedNotes calls GitHub's release notes generation for the provided
+// tag, since the previous tag, and parses its output.
+func getGeneratedNotes(ctx context.Context, cmd commander, tagName string, previousTagName string, targetBranch string, config GenerateNotesConfig) (generatedNotes, error) {
+	args := []string{
+		"api", "repos/{owner}/{repo}/releases/generate-notes",
+		"-f", "tag_name=" + tagName,
+		"--jq", ".body",
+	}
+	if previousTagName != "" && previousTagName != tagName {
+		args = append(args, "-f", "previous_tag_name="+previousTagName)
+	}
+	if targetBranch != "" {
+		args = append(args, "-f", "target_commitish="+targetBranch)
+	}
+	if config.ConfigurationFile != "" {
+		args = append(args, "-f", "configuration_file_path="+config.ConfigurationFile)
+	}
+
+	body, err := cmd.runForge(ctx, "gh", args...)
+	if err != nil {
+		return generatedNotes{}, &OperationError{Op: "generate the release notes with GitHub", Ref: tagName, Err: err}
+	}
+
+	return parseGeneratedNotes(body), nil
+}
+
+// parseGeneratedNotes parses the categories and new contributors of the
+// markdown release notes generated by GitHub.
+func parseGeneratedNotes(body string) generatedNotes {
+	var (
+		generated = generatedNotes{categories: map[int]string{}}
+		category  string
+	)
+	for line := range strings.SplitSeq(body, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "### "):
+			category = strings.TrimSpace(strings.TrimPrefix(line, "### "))
+			generated.categoryTitles = append(generated.categoryTitles, category)
+		case strings.HasPrefix(line, "## "):
+			category = ""
+		}
+
+		if matches := reGeneratedNewContributor.FindStringSubmatch(line); matches != nil {
+			number, _ := strconv.Atoi(matches[2])
+			generated.newContributors = append(generated.newContributors, generatedNewContributor{login: matches[1], number: number})
+			continue
+		}
+		if matches := reGeneratedEntry.FindStringSubmatch(line); matches != nil && category != "" {
+			number, _ := strconv.Atoi(matches[1])
+			generated.categories[number] = category
+		}
+	}
+	return generated
+}
+
+// merge assigns the generated categories to the provided pull requests,
+// returning the categories, if none are configured, and returns the new
+// contributors of the pull requests.
+func (g generatedNotes) merge(pullRequests []gitPullRequest, configured []CategoryConfig) ([]CategoryConfig, []newContributor) {
+	var categories []CategoryConfig
+	if len(configured) == 0 && len(g.categoryTitles) > 0 {
+		// Weight the categories in the order they were generated, followed by
+		// the pull requests that weren't generated.
+		weights := map[string]int{}
+		for i, title := range g.categoryTitles {
+			weights[title] = i
+			categories = append(categories, CategoryConfig{Title: title, Weight: i})
+		}
+		for i := range pullRequests {
+			if title, ok := g.categories[pullRequests[i].Number]; ok {
+				pullRequests[i].category = CategoryConfig{Title: title, Weight: weights[title]}
+			}
+		}
+	}
+
+	var contributors []newContributor
+	for _, contributor := range g.newContributors {
+		for _, pullRequest := range pullRequests {
+			if pullRequest.Number == contributor.number {
+				contributors = append(contributors, newContributor{Login: contributor.login, PullRequest: pullRequest})
+				break
+			}
+		}
+	}
+
+	return categories, contributors
+}
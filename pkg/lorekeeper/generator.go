@@ -0,0 +1,110 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Generator makes the release notes for a tag in stages, so that the collected
+// details can be reused, i.e - to render the release notes in multiple formats
+// without fetching them again.
+type Generator struct {
+	tagName string
+	options options
+	notes   *releaseNotes
+}
+
+// New returns a Generator for the release notes of the provided tag, with the
+// provided configuration and options.
+func New(tagName string, config Config, opts ...Option) *Generator {
+	return &Generator{
+		tagName: tagName,
+		options: newOptions(append([]Option{WithConfig(config)}, opts...)),
+	}
+}
+
+// Collect collects the details of the release notes from the repository and
+// the provider, replacing any previously collected details.
+func (g *Generator) Collect(ctx context.Context) error {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "collect", trace.WithAttributes(
+		attribute.String("lorekeeper.tag", g.tagName),
+		attribute.String("lorekeeper.mode", g.options.mode.Name),
+	))
+
+	notes, err := collectReleaseNotes(ctx, g.tagName, g.options)
+	if err == nil {
+		span.SetAttributes(attribute.Int("lorekeeper.entries", len(notes.PullRequests)))
+	}
+	endSpan(span, err)
+	if err != nil {
+		return err
+	}
+	log.Debug("Collected release notes", "tag", g.tagName, "entries", len(notes.PullRequests), "duration", time.Since(start))
+
+	g.notes = &notes
+	return nil
+}
+
+// collected returns the collected release notes, collecting them first if
+// they have not been yet.
+func (g *Generator) collected(ctx context.Context) (releaseNotes, error) {
+	if g.notes == nil {
+		if err := g.Collect(ctx); err != nil {
+			return releaseNotes{}, err
+		}
+	}
+	return *g.notes, nil
+}
+
+// Render returns the collected release notes rendered in the provided format.
+func (g *Generator) Render(ctx context.Context, format Format) ([]byte, error) {
+	notes, err := g.collected(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracer.Start(ctx, "render", trace.WithAttributes(
+		attribute.String("lorekeeper.format", string(format)),
+	))
+	var rendered bytes.Buffer
+	err = format.render(ctx, g.options.cmd, &rendered, notes, g.options.config)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// Publish publishes the collected release notes as the body of the release,
+// and uploads them as assets of the release, as configured.
+func (g *Generator) Publish(ctx context.Context) error {
+	notes, err := g.collected(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracer.Start(ctx, "publish")
+	err = publish(ctx, g.options.cmd, g.tagName, notes, g.options.config)
+	endSpan(span, err)
+	return err
+}
+
+// Notify posts the collected release notes to the configured destinations.
+func (g *Generator) Notify(ctx context.Context) error {
+	notes, err := g.collected(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracer.Start(ctx, "notify")
+	err = notify(ctx, g.options.cmd, notes, g.options.config)
+	endSpan(span, err)
+	return err
+}
@@ -0,0 +1,259 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newContributor represents an author whose first pull request to the
+// repository is in the release.
+type newContributor struct {
+	Login       string
+	PullRequest gitPullRequest
+}
+
+// renders reports whether the release notes are rendered in the provided
+// format, as the output or as an uploaded asset.
+func (c Config) renders(format Format) bool {
+	return c.Format == format || slices.Contains(c.Publish.Upload, format)
+}
+
+// newContributorsBatch is the most authors whose earlier pull requests are
+// searched for at once.
+const newContributorsBatch = 20
+
+// getNewContributors returns the authors of the provided pull requests who had
+// no pull request merged to the repository before them, with the first merged
+// of their pull requests, in the order of the pull requests. The pull requests
+// are attributed to their author, or the first author of their commits if the
+// forge doesn't provide one. Excluded and anonymised authors are skipped.
+//
+// The earlier pull requests of the authors are searched for in batches. The
+// authors of a batch whose search fails are left out, and the errors returned
+// along with the new contributors found.
+func getNewContributors(ctx context.Context, cmd commander, pullRequests []gitPullRequest, config AuthorsConfig) ([]newContributor, error) {
+	// The repository must be known to search its pull requests.
+	repository, ok := cmd.knownRepository()
+	if !ok || repository.isZero() {
+		return nil, &InputInvalidError{Input: "repository", Reason: "the repository is needed to find the new contributors"}
+	}
+
+	// Find the first merged pull request of each author, and the earliest
+	// merge of the release, before which any pull request is an earlier one.
+	var (
+		logins   []string
+		authors  = map[int]string{}
+		first    = map[string]gitPullRequest{}
+		earliest time.Time
+	)
+	for _, pullRequest := range pullRequests {
+		if pullRequest.Number == 0 {
+			continue
+		}
+		if earliest.IsZero() || pullRequest.MergedAt.Before(earliest) {
+			earliest = pullRequest.MergedAt
+		}
+
+		author := pullRequest.authorLogin()
+		login := strings.ToLower(author)
+		if login == "" || matchesLogin(config.Exclude, login) || matchesLogin(config.Anonymize, login) {
+			continue
+		}
+		authors[pullRequest.Number] = login
+		if firstPullRequest, ok := first[login]; !ok {
+			logins = append(logins, author)
+			first[login] = pullRequest
+		} else if pullRequest.MergedAt.Before(firstPullRequest.MergedAt) {
+			first[login] = pullRequest
+		}
+	}
+
+	// Search for the earlier pull requests of the authors, in batches.
+	var (
+		existing = map[string]bool{}
+		errs     []error
+	)
+	for batch := range slices.Chunk(logins, newContributorsBatch) {
+		found, err := earlierPullRequestAuthors(ctx, cmd, repository, batch, earliest)
+		if err != nil {
+			errs = append(errs, err)
+			for _, login := range batch {
+				existing[strings.ToLower(login)] = true
+			}
+			continue
+		}
+		for login := range found {
+			existing[login] = true
+		}
+	}
+
+	// List the new contributors at their first pull request.
+	var contributors []newContributor
+	for _, pullRequest := range pullRequests {
+		login, ok := authors[pullRequest.Number]
+		if ok && !existing[login] && first[login].Number == pullRequest.Number {
+			contributors = append(contributors, newContributor{Login: pullRequest.authorLogin(), PullRequest: pullRequest})
+		}
+	}
+
+	return contributors, errors.Join(errs...)
+}
+
+// authorLogin returns the login of the author of the pull request, or of the
+// first author of its commits if the forge doesn't provide one.
+func (pr gitPullRequest) authorLogin() string {
+	if pr.Author.Login != "" {
+		return pr.Author.Login
+	}
+	for _, commit := range pr.Commits {
+		if len(commit.Authors) > 0 {
+			return commit.Authors[0].Login
+		}
+	}
+	return ""
+}
+
+// earlierPullRequestAuthors returns the lowercased logins of the provided
+// authors who had a pull request merged to the repository before the provided
+// time. The authors found are left out of the next search until a search
+// returns less than a page, so that the pull requests of prolific authors
+// don't hide the others.
+func earlierPullRequestAuthors(ctx context.Context, cmd commander, repository Repository, authors []string, before time.Time) (map[string]bool, error) {
+	const perPage = 100
+
+	found := map[string]bool{}
+	for remaining := authors; len(remaining) > 0; {
+		query := fmt.Sprintf("repo:%s/%s is:pr is:merged merged:<%s", repository.Owner, repository.Name, before.UTC().Format(time.RFC3339))
+		for _, author := range remaining {
+			query += " author:" + author
+		}
+		logins, err := cmd.runForge(ctx, "gh",
+			"api", "-X", "GET", "search/issues",
+			"-f", "q="+query,
+			"-f", "per_page="+strconv.Itoa(perPage),
+			"--jq", ".items[].user.login",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var count int
+		for login := range strings.SplitSeq(strings.TrimSpace(logins), "\n") {
+			if login != "" {
+				found[strings.ToLower(login)] = true
+				count++
+			}
+		}
+		if count < perPage {
+			break
+		}
+		remaining = slices.DeleteFunc(slices.Clone(remaining), func(author string) bool {
+			return found[strings.ToLower(author)]
+		})
+	}
+	return found, nil
+}
+
+// renderGitHub writes the provided release notes to the writer in the style of
+// the release notes generated by GitHub: a "What's Changed" list, sub-divided
+// by any categories, followed by the new contributors, and a link to the full
+// changelog.
+func renderGitHub(ctx context.Context, cmd commander, w io.Writer, notes releaseNotes, config Config) error {
+	// Output the header, if configured.
+	if err := config.Header.render(w, "header", notes, config); err != nil {
+		return err
+	}
+
+	// Output the entries, under a heading per category, if configured.
+	fmt.Fprint(w, "## What's Changed\n")
+	if len(config.Categories) == 0 {
+		renderGitHubEntries(w, notes.PullRequests, config)
+	} else {
+		for _, category := range groupByCategory(notes.PullRequests) {
+			fmt.Fprintf(w, "### %s\n", category.Title)
+			renderGitHubEntries(w, category.PullRequests, config)
+		}
+	}
+	fmt.Fprint(w, "\n")
+
+	// Output the authors making their first contribution.
+	if len(notes.NewContributors) > 0 {
+		fmt.Fprint(w, "## New Contributors\n")
+		for _, contributor := range notes.NewContributors {
+			fmt.Fprintf(w, "* @%s made their first contribution in %s\n", contributor.Login, gitHubPullRequestLink(contributor.PullRequest))
+		}
+		fmt.Fprint(w, "\n")
+	}
+
+	// Link to the full changelog, since the previous tag if there is one.
+	if repository, ok := cmd.knownRepository(); ok && !repository.isZero() {
+		if notes.PreviousRef.TagName != "" && notes.PreviousRef.TagName != notes.TagName {
+			fmt.Fprintf(w, "**Full Changelog**: %s/compare/%s...%s\n", repository.URL(), notes.PreviousRef.TagName, notes.TagName)
+		} else {
+			fmt.Fprintf(w, "**Full Changelog**: %s/commits/%s\n", repository.URL(), notes.TagName)
+		}
+	}
+
+	// Close with the thank-you paragraph.
+	if notes.Thanks != "" {
+		fmt.Fprintf(w, "\n%s\n", notes.Thanks)
+	}
+
+	// Output the footer, if configured.
+	if config.Footer.Text != "" || config.Footer.Path != "" {
+		fmt.Fprint(w, "\n")
+		return config.Footer.render(w, "footer", notes, config)
+	}
+
+	return nil
+}
+
+// renderGitHubEntries writes a bullet per pull request to the writer, in the
+// style of the release notes generated by GitHub.
+func renderGitHubEntries(w io.Writer, pullRequests []gitPullRequest, config Config) {
+	for _, pullRequest := range pullRequests {
+		fmt.Fprintf(w, "* %s%s%s", pullRequest.displayTitle(config), linkedIssuesSuffix(pullRequest.issues), badgesSuffix(pullRequest, config.Badges))
+
+		// Attribute the pull request to its first author, as GitHub does.
+		if authors := pullRequestAuthors(pullRequest, config.Authors); len(authors) > 0 {
+			if authors[0].Login == config.Authors.anonymousName() && len(config.Authors.Anonymize) > 0 {
+				fmt.Fprintf(w, " by %s", authors[0].Login)
+			} else {
+				fmt.Fprintf(w, " by @%s", authors[0].Login)
+			}
+		}
+
+		if pullRequest.Number != 0 {
+			fmt.Fprintf(w, " in %s", gitHubPullRequestLink(pullRequest))
+		}
+		for _, fixup := range pullRequest.fixups {
+			fmt.Fprintf(w, ", %s", gitHubPullRequestLink(fixup))
+		}
+
+		// Mark the earlier release candidate the pull request was published in.
+		if pullRequest.publishedIn != "" {
+			fmt.Fprintf(w, " (previously in %s)", pullRequest.publishedIn)
+		}
+		fmt.Fprint(w, "\n")
+
+		// Nest the pull requests stacked on top of it.
+		for _, layer := range pullRequest.stacked {
+			fmt.Fprintf(w, "  * %s in %s\n", layer.displayTitle(config), gitHubPullRequestLink(layer))
+		}
+	}
+}
+
+// gitHubPullRequestLink returns the URL of the provided pull request, which
+// GitHub renders as a link, or its number if it has no URL, i.e - offline.
+func gitHubPullRequestLink(pullRequest gitPullRequest) string {
+	if pullRequest.URL != "" {
+		return pullRequest.URL
+	}
+	return fmt.Sprintf("#%d", pullRequest.Number)
+}
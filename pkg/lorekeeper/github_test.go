@@ -0,0 +1,93 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// searchRunner is a Runner that answers the searches for pull requests with
+// the logins of the provided existing authors that are searched for, and fails
+// the searches for any of the provided failing authors.
+type searchRunner struct {
+	existing []string
+	failing  []string
+}
+
+func (r searchRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	query := args[slices.Index(args, "-f")+1]
+	var logins []string
+	for _, author := range r.existing {
+		if strings.Contains(query, "author:"+author+" ") || strings.HasSuffix(query, "author:"+author) {
+			logins = append(logins, author)
+		}
+	}
+	for _, author := range r.failing {
+		if strings.Contains(query, "author:"+author) {
+			return "", errors.New("search failed")
+		}
+	}
+	return strings.Join(logins, "\n"), nil
+}
+
+func TestGetNewContributors(t *testing.T) {
+	merged := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pullRequest := func(number int, author string, days int) gitPullRequest {
+		return gitPullRequest{
+			Number:   number,
+			Author:   gitAuthor{Login: author},
+			Commits:  []gitCommit{{Authors: []gitAuthor{{Login: "committer"}}}},
+			MergedAt: merged.AddDate(0, 0, days),
+		}
+	}
+	pullRequests := []gitPullRequest{
+		pullRequest(4, "alice", 3),
+		pullRequest(3, "bob", 2),
+		pullRequest(2, "alice", 1),
+		pullRequest(1, "bot", 0),
+		{Number: 5, Commits: []gitCommit{{Authors: []gitAuthor{{Login: "carol"}}}}, MergedAt: merged},
+	}
+	cmd := commander{
+		runner:     searchRunner{existing: []string{"bob"}},
+		repository: Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"},
+		retry:      RetryConfig{Attempts: 1},
+	}
+
+	contributors, err := getNewContributors(context.Background(), cmd, pullRequests, AuthorsConfig{Exclude: []string{"bot"}})
+	if err != nil {
+		t.Fatalf("getNewContributors() error = %v", err)
+	}
+	var got []string
+	for _, contributor := range contributors {
+		got = append(got, fmt.Sprintf("%s#%d", contributor.Login, contributor.PullRequest.Number))
+	}
+	if want := []string{"alice#2", "carol#5"}; !slices.Equal(got, want) {
+		t.Errorf("getNewContributors() = %v, want %v", got, want)
+	}
+}
+
+func TestGetNewContributorsFailedBatch(t *testing.T) {
+	var pullRequests []gitPullRequest
+	for i := range newContributorsBatch + 1 {
+		pullRequests = append(pullRequests, gitPullRequest{Number: i + 1, Author: gitAuthor{Login: fmt.Sprintf("author%d", i)}})
+	}
+	cmd := commander{
+		runner:     searchRunner{failing: []string{"author0"}},
+		repository: Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"},
+		retry:      RetryConfig{Attempts: 1},
+	}
+
+	// The authors of the failed batch are left out, but not those of the
+	// next batch.
+	contributors, err := getNewContributors(context.Background(), cmd, pullRequests, AuthorsConfig{})
+	if err == nil {
+		t.Error("getNewContributors() error = nil, want the failed search")
+	}
+	if len(contributors) != 1 || contributors[0].Login != fmt.Sprintf("author%d", newContributorsBatch) {
+		t.Errorf("getNewContributors() = %+v, want the author of the last batch", contributors)
+	}
+}
@@ -0,0 +1,119 @@
+package lorekeeper
+
+import (
+	"context"
+	"path"
+	"slices"
+
+	"github.com/charmbracelet/log"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// defaultGoModPath is the path, relative to the root of the repository, of the
+// go.mod file when no path has been configured.
+const defaultGoModPath = "go.mod"
+
+// GoModuleCheck determines what happens when the major version of a tag
+// doesn't match the path of the Go module.
+type GoModuleCheck string
+
+const (
+	// GoModuleCheckWarn logs a warning, and carries on.
+	GoModuleCheckWarn GoModuleCheck = "warn"
+
+	// GoModuleCheckFail fails before the release notes are made.
+	GoModuleCheckFail GoModuleCheck = "fail"
+)
+
+// GetGoModuleChecks returns what can happen when the major version of a tag
+// doesn't match the path of the Go module.
+func GetGoModuleChecks() []GoModuleCheck {
+	return []GoModuleCheck{GoModuleCheckWarn, GoModuleCheckFail}
+}
+
+// GoModuleConfig determines whether the tags of a Go module are checked
+// against the major version suffix of its module path.
+type GoModuleConfig struct {
+	// Check checks that the major version of the tag matches the path of the
+	// module at the tag, i.e - that `v2.0.0` is tagged on a module path ending
+	// in `/v2`. Either "warn", or "fail". Needs a local clone.
+	Check GoModuleCheck `yaml:"check"`
+
+	// Path is the path, relative to the root of the repository, of the go.mod
+	// file. Defaults to `go.mod`. The module of a subdirectory is tagged with
+	// its directory as a prefix, i.e - `tools/v1.2.0`.
+	Path string `yaml:"path"`
+}
+
+// validate checks what happens when the major version doesn't match.
+func (c GoModuleConfig) validate() error {
+	if c.Check != "" && !slices.Contains(GetGoModuleChecks(), c.Check) {
+		return &GoModuleCheckInvalidError{Check: c.Check}
+	}
+	return nil
+}
+
+// checkMajorVersion checks that the major version of the provided tag matches
+// the module path of the go.mod file at the tag, as configured.
+func (c GoModuleConfig) checkMajorVersion(ctx context.Context, cmd commander, tagName string) error {
+	goModPath := c.Path
+	if goModPath == "" {
+		goModPath = defaultGoModPath
+	}
+
+	// Read the module path at the tag.
+	goMod, err := cmd.run(ctx, "git", "show", tagName+":"+goModPath)
+	if err != nil {
+		return &GoModuleMajorError{TagName: tagName, Err: err}
+	}
+	modulePath := modfile.ModulePath([]byte(goMod))
+	if modulePath == "" {
+		return &GoModuleMajorError{TagName: tagName, Reason: goModPath + " has no module directive"}
+	}
+
+	// The version follows the directory of the module, if any.
+	version := path.Base(tagName)
+	if !semver.IsValid(version) {
+		return &GoModuleMajorError{TagName: tagName, ModulePath: modulePath, Reason: "the tag is not a semantic version"}
+	}
+	if dir := path.Dir(goModPath); path.Dir(tagName) != dir {
+		reason := "the tag of the module at the root must not have a directory prefix"
+		if dir != "." {
+			reason = "the tag of the module in " + dir + " must start with " + dir + "/"
+		}
+		return &GoModuleMajorError{TagName: tagName, ModulePath: modulePath, Reason: reason}
+	}
+
+	// Check the major version against the suffix of the module path.
+	_, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return &GoModuleMajorError{TagName: tagName, ModulePath: modulePath, Reason: "the module path is invalid"}
+	}
+	if err := module.CheckPathMajor(version, pathMajor); err != nil {
+		return &GoModuleMajorError{TagName: tagName, ModulePath: modulePath, Err: err}
+	}
+	return nil
+}
+
+// checkGoModule checks the major version of the tag, as configured, returning
+// the error if it should fail, or logging it as a warning. Without a local
+// clone, the go.mod file can't be read, so the check fails in the same way.
+func checkGoModule(ctx context.Context, cmd commander, tagName string, config GoModuleConfig, local bool) error {
+	if config.Check == "" {
+		return nil
+	}
+
+	var err error
+	if local {
+		err = config.checkMajorVersion(ctx, cmd, tagName)
+	} else {
+		err = &GoModuleMajorError{TagName: tagName, Reason: "the go.mod file can't be read without a local clone"}
+	}
+	if err != nil && config.Check == GoModuleCheckWarn {
+		log.Warn("The tag doesn't match the Go module", "err", err)
+		return nil
+	}
+	return err
+}
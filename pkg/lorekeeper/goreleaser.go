@@ -0,0 +1,162 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultGoReleaserOutput is the path of the release notes written for
+// GoReleaser when no path has been configured.
+const defaultGoReleaserOutput = "release-notes.md"
+
+// goReleaserConfigPaths are the paths, relative to the root of the
+// repository, that GoReleaser reads its configuration from, in order.
+var goReleaserConfigPaths = []string{
+	".goreleaser.yml",
+	".goreleaser.yaml",
+	"goreleaser.yml",
+	"goreleaser.yaml",
+}
+
+// GoReleaserConfig determines how the release notes are handed to GoReleaser.
+type GoReleaserConfig struct {
+	// Enabled writes the release notes to a file, for `goreleaser release
+	// --release-notes`, instead of the output, leaving out the entries that
+	// the changelog filters of the GoReleaser configuration exclude.
+	Enabled bool `yaml:"enabled"`
+
+	// Output is the path of the file the release notes are written to.
+	// Defaults to `release-notes.md`. It should be outside of GoReleaser's
+	// `dist` directory, which GoReleaser cleans.
+	Output string `yaml:"output"`
+
+	// Config is the path of the GoReleaser configuration, relative to the root
+	// of the working tree. Defaults to the first of `.goreleaser.yml`, `.goreleaser.yaml`, `goreleaser.yml`, and
+	// `goreleaser.yaml` that exists.
+	Config string `yaml:"config"`
+}
+
+// output returns the configured, or default, path of the release notes.
+func (c GoReleaserConfig) output() string {
+	if c.Output == "" {
+		return defaultGoReleaserOutput
+	}
+	return c.Output
+}
+
+// writeGoReleaserNotes writes the rendered release notes to the provided path,
+// through a temporary file in the same directory that replaces it, so that
+// GoReleaser never reads a partially written file.
+func writeGoReleaserNotes(path string, rendered []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(rendered); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// goReleaserFilters represents the changelog filters of the GoReleaser
+// configuration, which are matched against the titles of the entries.
+type goReleaserFilters struct {
+	exclude []*regexp.Regexp
+	include []*regexp.Regexp
+}
+
+// getGoReleaserFilters reads the changelog filters of the configured, or
+// detected, GoReleaser configuration. There are no filters if there is no
+// configuration.
+func getGoReleaserFilters(ctx context.Context, cmd commander, config GoReleaserConfig) (goReleaserFilters, error) {
+	// Read the GoReleaser configuration.
+	var (
+		path string
+		data []byte
+		err  error
+	)
+	if config.Config != "" {
+		path = config.Config
+		data, err = os.ReadFile(worktreePath(ctx, cmd, path))
+	} else {
+		for _, path = range goReleaserConfigPaths {
+			data, err = os.ReadFile(worktreePath(ctx, cmd, path))
+			if !errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return goReleaserFilters{}, nil
+		}
+	}
+	if err != nil {
+		return goReleaserFilters{}, &GoReleaserConfigError{Path: path, Err: err}
+	}
+
+	var goReleaser struct {
+		Changelog struct {
+			Filters struct {
+				Exclude []string `yaml:"exclude"`
+				Include []string `yaml:"include"`
+			} `yaml:"filters"`
+		} `yaml:"changelog"`
+	}
+	if err := yaml.Unmarshal(data, &goReleaser); err != nil {
+		return goReleaserFilters{}, &GoReleaserConfigError{Path: path, Err: err}
+	}
+
+	// Compile the filters.
+	var filters goReleaserFilters
+	for _, list := range []struct {
+		patterns []string
+		compiled *[]*regexp.Regexp
+	}{
+		{goReleaser.Changelog.Filters.Exclude, &filters.exclude},
+		{goReleaser.Changelog.Filters.Include, &filters.include},
+	} {
+		for _, pattern := range list.patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return goReleaserFilters{}, &GoReleaserConfigError{Path: path, Err: err}
+			}
+			*list.compiled = append(*list.compiled, re)
+		}
+	}
+
+	return filters, nil
+}
+
+// includes reports whether the entry of the provided pull request is included
+// by the filters, matched against its title before it was rewritten, as in the
+// changelog of GoReleaser. As in GoReleaser, the include filters take
+// precedence over the exclude filters when there are any.
+func (f goReleaserFilters) includes(pullRequest gitPullRequest) bool {
+	matches := func(patterns []*regexp.Regexp) bool {
+		for _, re := range patterns {
+			if re.MatchString(pullRequest.unrewrittenTitle()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(f.include) > 0 {
+		return matches(f.include)
+	}
+	return !matches(f.exclude)
+}
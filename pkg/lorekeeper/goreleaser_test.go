@@ -0,0 +1,27 @@
+package lorekeeper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteGoReleaserNotes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "release-notes.md")
+	if err := os.WriteFile(path, []byte("old notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeGoReleaserNotes(path, []byte("new notes")); err != nil {
+		t.Fatalf("writeGoReleaserNotes() error = %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "new notes" {
+		t.Errorf("release notes = %q, %v, want %q", data, err, "new notes")
+	}
+
+	// The temporary file is renamed, leaving nothing else behind.
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Errorf("directory has %d entries, %v, want 1", len(entries), err)
+	}
+}
@@ -0,0 +1,63 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// InstallConfig determines the content of the installation section of the
+// release notes, with the commands to install the release.
+type InstallConfig struct {
+	// Go is the path of the Go package to install with `go install`, i.e -
+	// `github.com/owner/repo/cmd/tool`.
+	Go string `yaml:"go"`
+
+	// Brew is the Homebrew formula to install with `brew install`, i.e -
+	// `owner/tap/tool`. It is left out of release candidates, which Homebrew
+	// doesn't ship.
+	Brew string `yaml:"brew"`
+
+	// Docker is the container image to pull with `docker pull`, i.e -
+	// `ghcr.io/owner/repo`.
+	Docker string `yaml:"docker"`
+
+	// TrimV tags the container image with the version, without the `v`
+	// prefix of the tag, i.e - `1.2.0` for `v1.2.0`.
+	TrimV bool `yaml:"trimV"`
+}
+
+// commands returns the commands to install the release of the provided tag.
+func (c InstallConfig) commands(tagName string, releaseCandidate bool) []string {
+	var commands []string
+	if c.Go != "" {
+		commands = append(commands, fmt.Sprintf("go install %s@%s", c.Go, tagName))
+	}
+	if c.Brew != "" && !releaseCandidate {
+		commands = append(commands, "brew install "+c.Brew)
+	}
+	if c.Docker != "" {
+		imageTag := tagName
+		if c.TrimV {
+			imageTag = strings.TrimPrefix(imageTag, "v")
+		}
+		commands = append(commands, fmt.Sprintf("docker pull %s:%s", c.Docker, imageTag))
+	}
+	return commands
+}
+
+// renderMarkdownInstall writes the installation section of the release notes
+// to the writer as markdown, if there are any commands to install it with.
+func renderMarkdownInstall(w io.Writer, notes releaseNotes, config InstallConfig) {
+	commands := config.commands(notes.TagName, notes.ReleaseCandidate)
+	if len(commands) == 0 {
+		return
+	}
+
+	fmt.Fprint(w, "# Installation\n\n")
+	fmt.Fprint(w, "```sh\n")
+	for _, command := range commands {
+		fmt.Fprintf(w, "%s\n", command)
+	}
+	fmt.Fprint(w, "```\n\n")
+}
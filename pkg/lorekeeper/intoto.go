@@ -0,0 +1,72 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+)
+
+// inTotoStatementType is the type of in-toto v1 statements.
+const inTotoStatementType = "https://in-toto.io/Statement/v1"
+
+type inTotoStatement struct {
+	Type          string           `json:"_type"`
+	Subject       []inTotoSubject  `json:"subject"`
+	PredicateType string           `json:"predicateType"`
+	Predicate     jsonReleaseNotes `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// renderInToto writes the provided release notes to the writer as an in-toto
+// statement, whose predicate is the structured JSON release notes.
+//
+// The subjects are the markdown release notes (named as they are uploaded),
+// and each release asset with a known checksum.
+func renderInToto(w io.Writer, notes releaseNotes, config Config) error {
+	// Digest the markdown release notes.
+	var markdown bytes.Buffer
+	if err := renderLintedMarkdown(&markdown, notes, config); err != nil {
+		return err
+	}
+	digest := sha256.Sum256(markdown.Bytes())
+
+	predicate, err := newRenderedJSONReleaseNotes(notes, config)
+	if err != nil {
+		return err
+	}
+
+	uploadName := config.Publish.UploadName
+	if uploadName == "" {
+		uploadName = defaultUploadName
+	}
+
+	statement := inTotoStatement{
+		Type: inTotoStatementType,
+		Subject: []inTotoSubject{{
+			Name:   uploadName + FormatMarkdown.extension(),
+			Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])},
+		}},
+		PredicateType: releaseNotesPredicateType,
+		Predicate:     predicate,
+	}
+
+	for _, asset := range notes.Assets {
+		if asset.SHA256 == "" {
+			continue
+		}
+		statement.Subject = append(statement.Subject, inTotoSubject{
+			Name:   asset.Name,
+			Digest: map[string]string{"sha256": asset.SHA256},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statement)
+}
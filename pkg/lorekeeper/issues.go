@@ -0,0 +1,90 @@
+package lorekeeper
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// reClosingIssue matches a reference to an issue of the same repository with
+// one of GitHub's closing keywords, i.e - `Fixes #45`, capturing its number.
+var reClosingIssue = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+#([0-9]+)\b`)
+
+// linkedIssue represents an issue referenced by a pull request.
+type linkedIssue struct {
+	Number int
+	Title  string
+}
+
+// String returns the issue as it is rendered alongside its pull request.
+func (i linkedIssue) String() string {
+	return "#" + strconv.Itoa(i.Number) + ": " + i.Title
+}
+
+// linkedIssuesSuffix returns the provided issues as they are rendered after
+// the title of their pull request, i.e - ` (#45: Panic when config file
+// missing)`.
+func linkedIssuesSuffix(issues []linkedIssue) string {
+	var rendered strings.Builder
+	for _, issue := range issues {
+		rendered.WriteString(" (" + issue.String() + ")")
+	}
+	return rendered.String()
+}
+
+// linkedIssueNumbers returns the numbers of the issues that the provided pull
+// request references with a closing keyword, in the order referenced.
+func linkedIssueNumbers(pullRequest gitPullRequest) []int {
+	var numbers []int
+	for _, matches := range reClosingIssue.FindAllStringSubmatch(pullRequest.Body, -1) {
+		number, err := strconv.Atoi(matches[1])
+		if err != nil || number == pullRequest.Number || slices.Contains(numbers, number) {
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// issueTitles memoises the titles of the linked issues, by number, so that
+// each is fetched once, however many pull requests reference it, and however
+// many pull requests are fetched in parallel.
+type issueTitles struct {
+	titles sync.Map
+}
+
+// title returns the title of the issue of the provided number, fetching it the
+// first time. The title is empty if it can't be fetched, with a warning.
+func (t *issueTitles) title(ctx context.Context, cmd commander, number int) string {
+	title, _ := t.titles.LoadOrStore(number, sync.OnceValue(func() string {
+		output, err := cmd.runForge(ctx, "gh",
+			"api", "repos/{owner}/{repo}/issues/"+strconv.Itoa(number),
+			"--jq", ".title",
+		)
+		if err != nil {
+			log.Warn("Failed to get the title of the linked issue", "issue", number, "err", err)
+		}
+		return strings.TrimSpace(output)
+	}))
+	return title.(func() string)()
+}
+
+// withLinkedIssues returns the provided pull request with the titles of the
+// issues it references resolved, if configured, as it is fetched. Issues whose
+// title can't be fetched are left out.
+func withLinkedIssues(ctx context.Context, o options, pullRequest gitPullRequest) gitPullRequest {
+	if !o.config.LinkedIssues || o.offline {
+		return pullRequest
+	}
+	for _, number := range linkedIssueNumbers(pullRequest) {
+		if title := o.issueTitles.title(ctx, o.cmd, number); title != "" {
+			pullRequest.issues = append(pullRequest.issues, linkedIssue{Number: number, Title: title})
+		}
+	}
+	return pullRequest
+}
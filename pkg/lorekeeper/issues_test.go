@@ -0,0 +1,52 @@
+package lorekeeper
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// issueRunner is a Runner that answers the requests for the titles of issues,
+// counting them.
+type issueRunner struct {
+	requests *atomic.Int32
+}
+
+func (r issueRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	r.requests.Add(1)
+	number := args[1][strings.LastIndex(args[1], "/")+1:]
+	return "Issue " + number + "\n", nil
+}
+
+func TestWithLinkedIssues(t *testing.T) {
+	var requests atomic.Int32
+	o := options{
+		config:      Config{LinkedIssues: true},
+		cmd:         commander{runner: issueRunner{requests: &requests}, retry: RetryConfig{Attempts: 1}},
+		issueTitles: &issueTitles{},
+	}
+
+	pullRequests := []gitPullRequest{
+		{Number: 1, Body: "Fixes #45, and closes #46. Fixes #45 again."},
+		{Number: 2, Body: "Resolves: #45"},
+		{Number: 3, Body: "Fixes #3, which is this pull request."},
+	}
+	var got [][]string
+	for _, pullRequest := range pullRequests {
+		var issues []string
+		for _, issue := range withLinkedIssues(context.Background(), o, pullRequest).issues {
+			issues = append(issues, issue.String())
+		}
+		got = append(got, issues)
+	}
+
+	want := [][]string{{"#45: Issue 45", "#46: Issue 46"}, {"#45: Issue 45"}, nil}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("withLinkedIssues() = %q, want %q", got, want)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("withLinkedIssues() fetched %d titles, want 2", requests.Load())
+	}
+}
@@ -0,0 +1,221 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+var (
+	// reHeading matches an ATX heading, capturing its level and text.
+	reHeading = regexp.MustCompile(`^(#{1,6})(\s+.*|)$`)
+
+	// reFence matches the opening or closing line of a fenced code block.
+	reFence = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+
+	// reInlineCode matches an inline code span.
+	reInlineCode = regexp.MustCompile("`+[^`]*`+")
+
+	// reBareURL matches an absolute HTTP(S) URL.
+	reBareURL = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+
+	// reReferenceDefinition matches a link reference definition, capturing its
+	// label.
+	reReferenceDefinition = regexp.MustCompile(`^\s{0,3}\[([^\]]+)\]:\s*\S+`)
+
+	// reReferenceLink matches a full or collapsed reference link, capturing its
+	// text and label.
+	reReferenceLink = regexp.MustCompile(`\[([^\]]+)\]\[([^\]]*)\]`)
+)
+
+const (
+	lintRuleHeadingIncrement = "heading-increment"
+	lintRuleBareURL          = "no-bare-urls"
+	lintRuleReferenceLink    = "reference-links"
+)
+
+// LintConfig determines whether, and how, the rendered markdown release notes
+// are linted.
+type LintConfig struct {
+	// Enabled lints the rendered markdown, reporting any issues as warnings.
+	Enabled bool `yaml:"enabled"`
+
+	// Fix fixes the issues found, where possible.
+	Fix bool `yaml:"fix"`
+
+	// Strict fails the release notes generation if any issues remain after
+	// fixing.
+	Strict bool `yaml:"strict"`
+}
+
+// lintIssue represents an issue found in the rendered markdown.
+type lintIssue struct {
+	Line    int
+	Rule    string
+	Message string
+	Fixed   bool
+}
+
+// String returns a human readable representation of the issue.
+func (i lintIssue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Rule, i.Message)
+}
+
+// lintMarkdown checks the provided markdown for heading hierarchy issues, bare
+// URLs, and broken reference links, returning the issues found. If fix is
+// true, the issues are fixed where possible, and the fixed markdown returned.
+func lintMarkdown(markdown string, fix bool) (string, []lintIssue) {
+	var (
+		issues       []lintIssue
+		lines        = strings.Split(markdown, "\n")
+		inFence      bool
+		headingLevel int
+		definitions  = referenceDefinitions(lines)
+	)
+
+	for idx, line := range lines {
+		// Skip the contents of fenced code blocks.
+		if reFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		// Check headings only increment by one level at a time.
+		if matches := reHeading.FindStringSubmatch(line); matches != nil {
+			level := len(matches[1])
+			if headingLevel > 0 && level > headingLevel+1 {
+				issue := lintIssue{
+					Line:    idx + 1,
+					Rule:    lintRuleHeadingIncrement,
+					Message: fmt.Sprintf("heading level %d follows level %d", level, headingLevel),
+					Fixed:   fix,
+				}
+				issues = append(issues, issue)
+				if fix {
+					level = headingLevel + 1
+					line = strings.Repeat("#", level) + matches[2]
+				}
+			}
+			headingLevel = level
+		}
+
+		// Skip link reference definitions, as their URLs are not bare.
+		if reReferenceDefinition.MatchString(line) {
+			lines[idx] = line
+			continue
+		}
+
+		// Mask inline code, so its contents are not linted.
+		masked := reInlineCode.ReplaceAllStringFunc(line, func(code string) string {
+			return strings.Repeat(" ", len(code))
+		})
+
+		// Check for bare URLs, working backwards so fixes don't move the
+		// positions of earlier matches.
+		urls := reBareURL.FindAllStringIndex(masked, -1)
+		for i := len(urls) - 1; i >= 0; i-- {
+			start, end := urls[i][0], urls[i][1]
+
+			// URLs within links, autolinks, and HTML attributes are not bare.
+			if start > 0 && strings.ContainsRune("(<[\"'=", rune(masked[start-1])) {
+				continue
+			}
+
+			// Trailing punctuation is not part of the URL.
+			end = start + len(strings.TrimRight(masked[start:end], ".,;:!?"))
+
+			issues = append(issues, lintIssue{
+				Line:    idx + 1,
+				Rule:    lintRuleBareURL,
+				Message: fmt.Sprintf("bare URL %s", line[start:end]),
+				Fixed:   fix,
+			})
+			if fix {
+				line = line[:start] + "<" + line[start:end] + ">" + line[end:]
+				masked = masked[:start] + "<" + masked[start:end] + ">" + masked[end:]
+			}
+		}
+
+		// Check reference links have a definition.
+		links := reReferenceLink.FindAllStringSubmatchIndex(masked, -1)
+		for i := len(links) - 1; i >= 0; i-- {
+			var (
+				link  = links[i]
+				text  = masked[link[2]:link[3]]
+				label = masked[link[4]:link[5]]
+			)
+			if label == "" {
+				label = text
+			}
+			if definitions[strings.ToLower(label)] {
+				continue
+			}
+
+			issues = append(issues, lintIssue{
+				Line:    idx + 1,
+				Rule:    lintRuleReferenceLink,
+				Message: fmt.Sprintf("reference link [%s] has no definition", label),
+				Fixed:   fix,
+			})
+			if fix {
+				line = line[:link[0]] + line[link[2]:link[3]] + line[link[1]:]
+			}
+		}
+
+		lines[idx] = line
+	}
+
+	return strings.Join(lines, "\n"), issues
+}
+
+// referenceDefinitions returns the lower-cased labels of the link reference
+// definitions in the provided lines, outside of fenced code blocks.
+func referenceDefinitions(lines []string) map[string]bool {
+	var (
+		definitions = map[string]bool{}
+		inFence     bool
+	)
+
+	for _, line := range lines {
+		if reFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if matches := reReferenceDefinition.FindStringSubmatch(line); matches != nil && !inFence {
+			definitions[strings.ToLower(matches[1])] = true
+		}
+	}
+
+	return definitions
+}
+
+// applyLint lints the provided markdown as configured, reporting each issue as
+// a warning, and returning the fixed markdown if fixing is enabled.
+func applyLint(markdown string, config LintConfig) (string, error) {
+	if !config.Enabled {
+		return markdown, nil
+	}
+
+	linted, issues := lintMarkdown(markdown, config.Fix)
+
+	var unfixed int
+	for _, issue := range issues {
+		if issue.Fixed {
+			log.Info("Fixed markdown lint issue", "issue", issue)
+			continue
+		}
+		log.Warn("Markdown lint issue", "issue", issue)
+		unfixed++
+	}
+
+	if config.Strict && unfixed > 0 {
+		return linted, &LintError{Issues: unfixed}
+	}
+
+	return linted, nil
+}
@@ -1,13 +1,21 @@
 package lorekeeper
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os/exec"
+	"io"
+	"iter"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/charmbracelet/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const packageName = "lorekeeper"
@@ -29,6 +37,11 @@ var (
 		VarName:     "ModeTag",
 		Description: "Can be used with any Git repositories.",
 	}
+	ModeTrain = mode{
+		Name:        "train",
+		VarName:     "ModeTrain",
+		Description: "Includes everything merged since the last departure of the scheduled release train (train.schedule), whether or not it has been tagged.",
+	}
 	nilMode = mode{}
 )
 
@@ -36,6 +49,7 @@ func GetModes() []mode {
 	return []mode{
 		ModeRelease,
 		ModeTag,
+		ModeTrain,
 	}
 }
 
@@ -75,56 +89,215 @@ type gitAuthor struct {
 }
 
 type gitCommit struct {
-	Authors []gitAuthor `json:"authors"`
+	OID             string      `json:"oid"`
+	MessageHeadline string      `json:"messageHeadline"`
+	Authors         []gitAuthor `json:"authors"`
+}
+
+type gitLabel struct {
+	Name string `json:"name"`
+}
+
+type gitFile struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
 }
 
 type gitPullRequest struct {
-	Title   string      `json:"title"`
-	Body    string      `json:"body"`
-	Commits []gitCommit `json:"commits"`
+	Number      int         `json:"number"`
+	Title       string      `json:"title"`
+	URL         string      `json:"url"`
+	Author      gitAuthor   `json:"author"`
+	Body        string      `json:"body"`
+	Commits     []gitCommit `json:"commits"`
+	Labels      []gitLabel  `json:"labels"`
+	MergedAt    time.Time   `json:"mergedAt"`
+	HeadRefName string      `json:"headRefName"`
+	BaseRefName string      `json:"baseRefName"`
+	Files       []gitFile   `json:"files"`
+	IsDraft     bool        `json:"isDraft"`
+
+	// category is the category the pull request has been assigned to.
+	category CategoryConfig
+
+	// publishedIn is the earlier release candidate that the pull request was
+	// first published in, when marked.
+	publishedIn string
+
+	// issues are the issues referenced by the pull request, when resolved.
+	issues []linkedIssue
+
+	// stacked are the pull requests stacked on top of this one, the bottom of
+	// their stack, when nested.
+	stacked []gitPullRequest
+
+	// fixups are the pull requests following up on this one, when folded
+	// into its entry.
+	fixups []gitPullRequest
+
+	// notice is the section of the release notes, besides the entries, that
+	// the fragment read as this pull request belongs to, if any.
+	notice fragmentNotice
+
+	// originalTitle is the title before it was rewritten by the title rewrite
+	// rules, if it was.
+	originalTitle string
+}
+
+// unrewrittenTitle returns the title of the pull request before it was
+// rewritten by the title rewrite rules, i.e - as seen by GoReleaser.
+func (pr gitPullRequest) unrewrittenTitle() string {
+	if pr.originalTitle != "" {
+		return pr.originalTitle
+	}
+	return pr.Title
 }
 
-// MakeReleaseNotes queries the provided owner/repo with the provided tag to
-// build the release notes for a new release, whether it is a release candidate
-// or not.
+// commitURL returns the URL of the provided commit of the pull request, in the
+// repository of the pull request, if it is known.
+func (pr gitPullRequest) commitURL(sha string) (string, bool) {
+	repositoryURL, _, ok := strings.Cut(pr.URL, "/pull/")
+	if !ok || sha == "" {
+		return "", false
+	}
+	return repositoryURL + "/commit/" + sha, true
+}
+
+// hasLabel reports whether the pull request has the provided label.
+func (pr gitPullRequest) hasLabel(name string) bool {
+	for _, label := range pr.Labels {
+		if strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseNotes represents the content of the release notes.
+type releaseNotes struct {
+	TagName          string
+	Date             time.Time
+	ReleaseCandidate bool
+	PreviousRef      gitReference
+	PullRequests     []gitPullRequest
+	Advisories       []securityAdvisory
+	SecurityNotes    []securityNote
+	DependencyCVEs   []dependencyCVE
+	SBOMDiff         sbomDiff
+	Submodules       []submoduleChange
+	Vendored         []vendoredChange
+	Upgrades         []upgradeNote
+	NewContributors  []newContributor
+	Assets           []releaseAsset
+	Funding          []fundingLink
+	Provenance       *provenance
+
+	// Candidates are the release candidates collapsed into a final release,
+	// oldest first.
+	Candidates []string
+
+	// Thanks is the thank-you paragraph that closes the release notes.
+	Thanks string
+
+	// Fragments are the paths of the fragment files that the entries were
+	// read from.
+	Fragments []string
+
+	// Categories are the categories generated by GitHub, used when none are
+	// configured.
+	Categories []CategoryConfig
+
+	// Part and Parts are the number of this part, and the total number of
+	// parts, when the release notes are split into parts.
+	Part  int
+	Parts int
+}
+
+// MakeReleaseNotes queries the repository with the provided tag to build the
+// release notes for a new release, whether it is a release candidate or not.
 //
-// The release notes will be output to stdout.
-func MakeReleaseNotes(
-	ctx context.Context,
-
-	// tagName is the release tag to use when checking for relevant branches and
-	// pull requests.
-	tagName string,
-
-	// releaseCandidateRegex is the regex pattern to use to identify tags
-	// that are release candidates.
-	releaseCandidateRegex string,
-
-	// currentBranchName is the name of the current branch.
-	currentBranchName string,
-
-	// defaultBranchName is the name of the default branch in the specified
-	// repository (i.e - main, master, etc).
-	defaultBranchName string,
-
-	// mode determines whether GitHub Releases or Git Tags are being used to
-	// identify releases.
-	//
-	// Possible values are:
-	//	MODE_RELEASE	// Can only be used for GitHub repositories that utilise the GitHub Releases feature
-	//	MODE_TAG			// Can be used with any Git repositories.
-	mode mode,
-) error {
-	// The compiled regular expression to identify candidate release tags.
-	reReleaseCandidate := regexp.MustCompile(releaseCandidateRegex)
+// The release notes are output to stdout, unless WithWriter is provided. The
+// mode defaults to ModeRelease, which can only be used for GitHub
+// repositories that utilise the GitHub Releases feature, whereas ModeTag can
+// be used with any Git repositories.
+func MakeReleaseNotes(ctx context.Context, tagName string, opts ...Option) error {
+	return makeReleaseNotes(ctx, tagName, newOptions(opts))
+}
+
+// pullRequestListing represents the pull requests to include in the release
+// notes for a tag, and the reference that they are included since.
+type pullRequestListing struct {
+	numbers          string
+	latestRef        gitReference
+	releaseCandidate bool
+
+	// published is the earlier release candidate that each pull request was
+	// first published in, by number, when they are marked.
+	published map[string]string
+
+	// candidates are the release candidates collapsed into a final release,
+	// oldest first.
+	candidates []string
+}
+
+// listedNumbers returns the pull request numbers output by the forge, one per
+// line, without the blank lines, i.e - the trailing newline of `gh`.
+func listedNumbers(output string) string {
+	var numbers []string
+	for number := range strings.SplitSeq(output, "\n") {
+		if number = strings.TrimSpace(number); number != "" {
+			numbers = append(numbers, number)
+		}
+	}
+	return strings.Join(numbers, "\n")
+}
+
+// listPullRequests lists the pull requests to include in the release notes for
+// the provided tag, as described by MakeReleaseNotes.
+func listPullRequests(ctx context.Context, tagName string, o options, location *time.Location) (pullRequestListing, error) {
+	mode := o.mode
+
+	// Normalise the branch names, detecting the default branch if needed.
+	resolveBranches(ctx, &o)
+
+	if !o.apiOnly {
+		// Make sure the history and tags are complete, i.e - in a shallow CI
+		// checkout.
+		if err := ensureFullHistory(ctx, o); err != nil {
+			return pullRequestListing{}, err
+		}
+
+		// Compare with the tags of the canonical repository, if asked to,
+		// i.e - when a fork's `upstream` remote was provided.
+		if o.fetchTags && !o.offline {
+			if err := fetchRemoteTags(ctx, o.cmd, o.remote); err != nil {
+				return pullRequestListing{}, err
+			}
+		}
+	}
+
+	// Check the inputs up front, rather than failing part way through.
+	if err := validateInputs(ctx, tagName, o); err != nil {
+		return pullRequestListing{}, err
+	}
+
+	// The release train is cut on a schedule, regardless of the tags.
+	if mode == ModeTrain {
+		return listTrainPullRequests(ctx, o, location)
+	}
+
+	// The compiled regular expression to identify candidate release tags,
+	// which has already been validated.
+	reReleaseCandidate := regexp.MustCompile(o.releaseCandidateRegex)
 
 	// Check if the tag is a release candidate.
 	tagIsReleaseCandidate := reReleaseCandidate.MatchString(tagName)
 
-	// Check if the tag belongs to the default branch.
-	// TODO: May need to remove "refs/heads" from the current branch name, if it
-	// comes from github.event.base_ref
-	tagIsOnDefaultBranch := currentBranchName == defaultBranchName
+	// Check if the tag belongs to the default branch, or to one of the
+	// maintained release branches.
+	tagIsOnDefaultBranch := o.currentBranchName == o.defaultBranchName
+	tagIsOnReleaseBranch := !tagIsOnDefaultBranch && o.config.isReleaseBranch(o.currentBranchName)
 
 	// Initialise the latest reference variables.
 	var (
@@ -135,6 +308,40 @@ func MakeReleaseNotes(
 	)
 
 	switch {
+	case o.config.ReleaseCandidates.Collapse && !tagIsReleaseCandidate && (tagIsOnDefaultBranch || tagIsOnReleaseBranch):
+		// If the tag IS a final release, and the release candidates are
+		// collapsed, include the release notes from ALL pull requests since
+		// the previous final release, marked with their release candidate.
+		return collapseCandidates(ctx, o, tagName, reReleaseCandidate, location)
+	case tagIsOnReleaseBranch:
+		// If the tag IS on a maintained release branch, include the release
+		// notes from ALL pull requests since the previous tag of the same
+		// series, skipping release candidates unless the tag is one too.
+		if o.apiOnly {
+			return pullRequestListing{}, &OperationError{Op: "find the previous tag of the release branch", Ref: o.currentBranchName, Err: errReleaseBranchAPIOnly}
+		}
+		var reSkipped *regexp.Regexp
+		if !tagIsReleaseCandidate {
+			reSkipped = reReleaseCandidate
+		}
+		latestRefJSON, err = previousTag(ctx, o.cmd, tagName, reSkipped)
+		if err != nil {
+			return pullRequestListing{}, &OperationError{Op: "find the previous tag of the release branch", Ref: tagName, Err: err}
+		}
+		if latestRefJSON != "" {
+			if err := json.Unmarshal([]byte(latestRefJSON), &latestRef); err != nil {
+				return pullRequestListing{}, &OperationError{Op: "parse the previous tag", Ref: tagName, Err: err}
+			}
+		}
+
+		// Get all pull requests merged since the previous tag.
+		prList, err = pullRequestsSince(ctx, o, latestRef, tagName, location)
+		if err != nil {
+			return pullRequestListing{}, &OperationError{Op: "list the merged pull requests", Ref: latestRef.TagName, Err: err}
+		}
+		if prList == "" {
+			return pullRequestListing{}, &NoPullRequestsFoundError{Mode: mode, LatestRef: latestRef}
+		}
 	case !tagIsOnDefaultBranch && tagIsReleaseCandidate:
 		// If the tag IS NOT on the default branch, and IS a release candidate,
 		// include the release notes from the associated branch's pull request.
@@ -142,35 +349,24 @@ func MakeReleaseNotes(
 		// Get the SHA of the latest commit for the given tag.
 		//
 		// This also checks if the tag exists in the repository.
-		//
-		// `git ref-list` returns commits in reverse chronological order (newest to
-		// oldest)
-		latestTagCommit, err := runCmd(fmt.Sprintf(
-			"git rev-list -n 1 \"%s\"",
-			tagName,
-		))
+		latestTagCommit, err := tagCommit(ctx, o, tagName)
 		if err != nil {
-			// TODO: Handle error from running the command.
+			return pullRequestListing{}, &OperationError{Op: "get the commit of the tag", Ref: tagName, Err: err}
 		}
 
 		// Get the pull request associated with the latest commit for the given tag.
-		//
-		// `gh pr list` returns pull requests in reverse chronological order
-		// (newewst to oldest) sorted by createdAt, and doesn't let you change it.
-		//
-		// TODO: This uses the `gh` CLI app, so is locked to GitHub.
-		// Find another way to do this without `gh`.
-		prList, err = runCmd(fmt.Sprintf(
-			"gh pr list "+
-				"--search \"sha:%s\" "+
-				"--json number | jq '.[].number", latestTagCommit))
+		prList, err = o.provider.CommitPullRequests(ctx, latestTagCommit)
 		if err != nil {
-			// TODO: Handle error from running the command.
+			return pullRequestListing{}, &OperationError{Op: "list the pull requests of the commit", Ref: latestTagCommit, Err: err}
+		}
+		prList = listedNumbers(prList)
+		if prList == "" {
+			return pullRequestListing{}, &OperationError{Op: "list the pull requests of the commit", Ref: latestTagCommit, Err: ErrNoPullRequests}
 		}
 	case !tagIsOnDefaultBranch && !tagIsReleaseCandidate:
 		// If the tag IS NOT on the default branch, and IS NOT a release candidate,
 		// exit with an error as this is not permitted.
-		return &DefaultBranchReleaseCandidateError{}
+		return pullRequestListing{}, &DefaultBranchReleaseCandidateError{TagName: tagName, DefaultBranch: o.defaultBranchName}
 	case tagIsOnDefaultBranch:
 
 		if tagIsReleaseCandidate {
@@ -179,26 +375,17 @@ func MakeReleaseNotes(
 			// tag depending on the mode).
 			switch mode {
 			case ModeRelease:
-				// TODO: This uses the `gh` CLI app, so is locked to GitHub.
-				// Find another way to do this without `gh`.
-				latestRefJSON, err = runCmd(fmt.Sprintf(
-					"gh release view %s",
-					tagName,
-				))
+				latestRefJSON, err = o.provider.Release(ctx, tagName)
 				if err != nil {
-					// TODO: Handle error from running the command.
+					return pullRequestListing{}, &OperationError{Op: "get the release", Ref: tagName, Err: err}
 				}
 			case ModeTag:
-				latestRefJSON, err = runCmd(
-					"git for-each-ref refs/tags " +
-						"--sort=-creatordate " +
-						"--format '{\"publishedAt\":\"%(creatordate:iso-strict)\",\"tagName\":\"%(refname)\"} | head -n 1",
-				)
+				latestRefJSON, err = tagBefore(ctx, o, tagName, nil)
 				if err != nil {
-					// TODO: Handle error from running the command.
+					return pullRequestListing{}, &OperationError{Op: "get the previous tag", Ref: tagName, Err: err}
 				}
 			default:
-				return &ModeInvalidError{Mode: mode}
+				return pullRequestListing{}, &ModeInvalidError{Mode: mode}
 			}
 		} else {
 			// If the tag IS on the default branch, and IS NOT a release candidate,
@@ -206,147 +393,556 @@ func MakeReleaseNotes(
 			// non-release candidate ref (release or tag depending on the mode).
 			switch mode {
 			case ModeRelease:
-				// Get the latest ron-RC release date.
-				//
-				// `gh release list` returns releases in reverse chronological order
-				// (newest to oldest) sorted by createdAt.
-				//
-				// TODO: This uses the `gh` CLI app, so is locked to GitHub.
-				// Find another way to do this without `gh`.
-				var allReleases string
-				allReleases, err = runCmd(
-					"gh release list " +
-						"--json publishedAt,tagName",
-				)
+				// Get the latest non-RC release date.
+				var releases []gitReference
+				releases, err = listReleases(ctx, o)
 				if err != nil {
-					// TODO: Handle error from running the command.
+					return pullRequestListing{}, &OperationError{Op: "list the releases", Err: err}
 				}
 
-				// Iterate through the releases to find the latest non-RC release.
-				for releaseJSON := range strings.SplitSeq(allReleases, "\n") {
-					var release gitReference
-					err = json.Unmarshal([]byte(releaseJSON), &release)
-					if err != nil {
-						// TODO: Handle error from running the command.
-					}
-					if reReleaseCandidate.MatchString(release.TagName) {
-						latestRefJSON = releaseJSON
+				// Find the latest non-RC release, other than that of the tag,
+				// as the releases are newest first.
+				for _, release := range releases {
+					if release.TagName != tagName && !reReleaseCandidate.MatchString(release.TagName) {
+						latestRef = release
+						break
 					}
 				}
 			case ModeTag:
-				latestRefJSON, err = runCmd(
-					"git for-each-ref refs/tags " +
-						"--exclude=\"refs/tags/*-rc*\"" + // TODO: Use the regex here.
-						"--sort=-creatordate " +
-						"--format '{\"publishedAt\":\"%(creatordate:iso-strict)\",\"tagName\":\"%(refname)\"} | head -n 1",
-				)
+				latestRefJSON, err = tagBefore(ctx, o, tagName, reReleaseCandidate)
 				if err != nil {
-					// TODO: Handle error from running the command.
+					return pullRequestListing{}, &OperationError{Op: "get the previous tag", Ref: tagName, Err: err}
 				}
 			default:
-				return &ModeInvalidError{Mode: mode}
+				return pullRequestListing{}, &ModeInvalidError{Mode: mode}
 			}
 		}
-		// Marshal the latest ref JSON.
-		err = json.Unmarshal([]byte(latestRefJSON), &latestRef)
-		if err != nil {
-			// TODO: Handle error from running the command.
+		// Unmarshal the latest ref JSON, unless the latest ref was found from
+		// the releases.
+		if latestRefJSON != "" {
+			if err := json.Unmarshal([]byte(latestRefJSON), &latestRef); err != nil {
+				return pullRequestListing{}, &OperationError{Op: "parse the latest ref", Ref: tagName, Err: err}
+			}
 		}
 
-		// Get all pull requests merged after the latestRef.PublishedAt.
-		//
-		// `gh pr list` returns pull requests in reverse chronological order
-		// (newest to oldest) sorted by createdAt, and doesn't let you change it.
-		//
-		// TODO: This uses the `gh` CLI app, so is locked to GitHub.
-		// Find another way to do this without `gh`.
-		prList, err = runCmd(fmt.Sprintf(
-			"gh pr list "+
-				"--state \"merged\" "+
-				"--search \"merged:>%s\" "+
-				"--json number | jq '.[].number'",
-			latestRef.PublishedAt,
-		))
+		// Get all pull requests merged since the latestRef.
+		prList, err = pullRequestsSince(ctx, o, latestRef, tagName, location)
 		if err != nil {
-			// TODO: Handle error from running the command.
+			return pullRequestListing{}, &OperationError{Op: "list the merged pull requests", Ref: latestRef.TagName, Err: err}
 		}
 
 		// If there are no pull requests found, exit with an error.
 		if prList == "" {
-			return &NoPullRequestsFoundError{}
+			return pullRequestListing{}, &NoPullRequestsFoundError{Mode: mode, LatestRef: latestRef}
 		}
 	}
 
-	// Iterate over each pull request.
-	for pullRequestNumber := range strings.SplitSeq(prList, "\n") {
-		// Get the pull request details.
-		//
-		// TODO: This uses the `gh` CLI app, so is locked to GitHub.
-		// Find another way to do this without `gh`.
-		pullRequestJSON, err := runCmd(fmt.Sprintf(
-			"gh pr view \"%s\" "+
-				"--json title,body,commits",
-			pullRequestNumber,
-		))
+	listing := pullRequestListing{
+		numbers:          prList,
+		latestRef:        latestRef,
+		releaseCandidate: tagIsReleaseCandidate,
+	}
+
+	// Exclude, or mark, the pull requests already published in the earlier
+	// release candidates of the same release.
+	if tagIsReleaseCandidate {
+		if err := dedupeCandidates(ctx, o, tagName, reReleaseCandidate, location, &listing); err != nil {
+			return pullRequestListing{}, err
+		}
+	}
+
+	return listing, nil
+}
+
+// fetchResult is the result of fetching an entry, i.e - the details of a pull
+// request.
+type fetchResult[T any] struct {
+	value T
+	err   error
+}
+
+// fetchPullRequest fetches and parses the details of the provided pull request.
+func fetchPullRequest(ctx context.Context, o options, number string) (gitPullRequest, error) {
+	ctx, span := tracer.Start(ctx, "fetch pull request", trace.WithAttributes(
+		attribute.String("lorekeeper.pull_request", number),
+	))
+
+	// Get the pull request details.
+	pullRequestJSON, err := o.provider.PullRequest(ctx, number)
+	if err != nil {
+		err = &OperationError{Op: "get the pull request", Ref: "#" + number, Err: err}
+		endSpan(span, err)
+		return gitPullRequest{}, err
+	}
+
+	// Unmarshal the pull request JSON.
+	var pullRequest gitPullRequest
+	if err := json.Unmarshal([]byte(pullRequestJSON), &pullRequest); err != nil {
+		err = &OperationError{Op: "parse the pull request", Ref: "#" + number, Err: err}
+		endSpan(span, err)
+		return gitPullRequest{}, err
+	}
+
+	// Rewrite the title, before it is categorised, filtered, or rendered.
+	pullRequest = pullRequest.withRewrittenTitle(o.titleRewrites)
+	endSpan(span, nil)
+	return pullRequest, nil
+}
+
+// fetchOrdered returns an iterator over the results of the provided fetch
+// function for each of the provided number of entries, by their index, in
+// order. Up to the provided concurrency of entries are fetched in parallel, or
+// held until they are yielded, ahead of the one reached, which is passed to the
+// reached function before it is waited for, i.e - to report the progress.
+func fetchOrdered[T any](ctx context.Context, concurrency, count int, fetch func(ctx context.Context, i int) (T, error), reached func(i int)) iter.Seq2[int, fetchResult[T]] {
+	return func(yield func(int, fetchResult[T]) bool) {
+		// Stop fetching once the iteration stops.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Fetch the entries in parallel, each delivering its result to its
+		// own buffered channel, so that they are yielded in order. Each entry
+		// holds its slot of the semaphore until its result is taken, so that
+		// no more than the concurrency are held ahead of the one reached.
+		results := make([]chan fetchResult[T], count)
+		for i := range results {
+			results[i] = make(chan fetchResult[T], 1)
+		}
+		semaphore := make(chan struct{}, concurrency)
+		go func() {
+			for i := range count {
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					value, err := fetch(ctx, i)
+					results[i] <- fetchResult[T]{value: value, err: err}
+				}()
+			}
+		}()
+
+		for i := range count {
+			reached(i)
+			result := <-results[i]
+			<-semaphore
+			if !yield(i, result) {
+				return
+			}
+		}
+	}
+}
+
+// fetchPullRequests returns an iterator over the details of each of the listed
+// pull requests, in the order they are listed, with their linked issues, if
+// configured. Up to the configured concurrency of pull requests are fetched in
+// parallel, ahead of the one reached.
+func fetchPullRequests(ctx context.Context, o options, listing pullRequestListing, categoriser *categoriser, filter *entryFilter, progress *progress) iter.Seq2[gitPullRequest, error] {
+	return func(yield func(gitPullRequest, error) bool) {
+		pullRequestNumbers := strings.Split(listing.numbers, "\n")
+
+		fetch := func(ctx context.Context, i int) (gitPullRequest, error) {
+			pullRequest, err := fetchPullRequest(ctx, o, pullRequestNumbers[i])
+			if err != nil {
+				return gitPullRequest{}, err
+			}
+			return withLinkedIssues(ctx, o, pullRequest), nil
+		}
+		reached := func(i int) {
+			progress.report("Fetching pull requests %d/%d (#%s)", i+1, len(pullRequestNumbers), pullRequestNumbers[i])
+		}
+		for i, result := range fetchOrdered(ctx, o.concurrency, len(pullRequestNumbers), fetch, reached) {
+			if result.err != nil {
+				yield(gitPullRequest{}, result.err)
+				return
+			}
+			pullRequest, number := result.value, pullRequestNumbers[i]
+
+			// Assign the pull request to its category, and mark it with the
+			// earlier release candidate it was published in.
+			pullRequest.category = categoriser.categorise(pullRequest)
+			pullRequest.publishedIn = listing.published[number]
+
+			// Skip the pull request if it is filtered out.
+			included, err := filter.includes(pullRequest, o.config.Authors)
+			if err != nil {
+				yield(gitPullRequest{}, &OperationError{Op: "filter the pull request", Ref: "#" + number, Err: err})
+				return
+			}
+			if !included {
+				log.Debug("Filtered out pull request", "number", pullRequest.Number)
+				continue
+			}
+
+			if !yield(pullRequest, nil) {
+				return
+			}
+		}
+	}
+}
+
+// collectReleaseNotes collects the details of the release notes as described by
+// MakeReleaseNotes, without outputting them.
+func collectReleaseNotes(ctx context.Context, tagName string, o options) (releaseNotes, error) {
+	config := o.config
+
+	// The timezone that dates are displayed and compared in.
+	location, err := config.location()
+	if err != nil {
+		return releaseNotes{}, err
+	}
+
+	// Report the progress of the collection.
+	progress := newProgress(config.Progress)
+	defer progress.done()
+	progress.report("Listing pull requests for %s", tagName)
+
+	// List the pull requests to include. The fragment files are read even if
+	// no pull requests were merged, i.e - when they were committed directly.
+	listing, err := listPullRequests(ctx, tagName, o, location)
+	var noPullRequests *NoPullRequestsFoundError
+	if errors.As(err, &noPullRequests) && config.Fragments.Format != "" {
+		listing, err = pullRequestListing{latestRef: noPullRequests.LatestRef}, nil
+	}
+	if err != nil {
+		return releaseNotes{}, err
+	}
+	latestRef := listing.latestRef
+
+	// Compile the category rules.
+	categoriser, err := newCategoriser(config.Categories)
+	if err != nil {
+		return releaseNotes{}, err
+	}
+
+	// Compile the filter expression.
+	filter, err := newEntryFilter(config.Filter)
+	if err != nil {
+		return releaseNotes{}, err
+	}
+
+	// Collect the details of each pull request, or read the entries from the
+	// fragment files, if configured.
+	var (
+		pullRequests []gitPullRequest
+		fragments    []fragment
+	)
+	if config.Fragments.Format != "" {
+		progress.report("Reading fragments")
+		fragments, err = readFragments(ctx, o.cmd, config.Fragments, latestRef.TagName, tagName)
 		if err != nil {
-			// TODO: Handle error from running the command.
+			return releaseNotes{}, err
+		}
+		if len(fragments) == 0 && noPullRequests != nil {
+			return releaseNotes{}, noPullRequests
+		}
+		for pullRequest, err := range fetchFragments(ctx, o, fragments, categoriser, filter, progress) {
+			if err != nil {
+				return releaseNotes{}, err
+			}
+			pullRequests = append(pullRequests, pullRequest)
 		}
+	} else {
+		for pullRequest, err := range fetchPullRequests(ctx, o, listing, categoriser, filter, progress) {
+			if err != nil {
+				return releaseNotes{}, err
+			}
+			pullRequests = append(pullRequests, pullRequest)
+		}
+	}
 
-		// Unmarshal the pull request JSON.
-		var pullRequest gitPullRequest
-		err = json.Unmarshal([]byte(pullRequestJSON), &pullRequest)
+	// Leave out the pull requests excluded by the changelog filters of
+	// GoReleaser.
+	if config.GoReleaser.Enabled {
+		filters, err := getGoReleaserFilters(ctx, o.cmd, config.GoReleaser)
 		if err != nil {
-			// TODO: Handle error from running the command.
+			return releaseNotes{}, err
 		}
+		pullRequests = slices.DeleteFunc(pullRequests, func(pullRequest gitPullRequest) bool {
+			return !filters.includes(pullRequest)
+		})
+	}
 
-		// Output the pull request header.
-		fmt.Printf("# %s (#%s)\n\n", pullRequest.Title, pullRequestNumber)
+	// Order the pull requests as configured.
+	if err := sortPullRequests(pullRequests, config.Sort); err != nil {
+		return releaseNotes{}, err
+	}
 
-		// Output the pull request authors header.
-		fmt.Print("## Authors\n\n")
+	notes := releaseNotes{
+		TagName:          tagName,
+		Date:             getTagDate(ctx, o.cmd, tagName),
+		ReleaseCandidate: listing.releaseCandidate,
+		PreviousRef:      latestRef,
+		Candidates:       listing.candidates,
+	}
 
-		// Output the pull request authors.
-		var authors []string
-		for _, commit := range pullRequest.Commits {
-			for _, author := range commit.Authors {
-				var reUrl = regexp.MustCompile(`(v=[0-9]+)`)
-				avatarUrl := reUrl.ReplaceAllString(author.AvatarURL, "s=64&amp;$1")
-				authors = append(authors, fmt.Sprintf("!\"[@%s](%s)", author.Login, avatarUrl))
-			}
+	// Group the entries read from the fragment files by their categories,
+	// unless categories are configured. A file may hold several fragments.
+	for _, fragment := range fragments {
+		notes.Fragments = append(notes.Fragments, fragment.path)
+	}
+	notes.Fragments = slices.Compact(notes.Fragments)
+	if len(fragments) > 0 && len(config.Categories) == 0 {
+		notes.Categories = config.Fragments.categories()
+	}
+
+	// Gather the upgrade and security notes read from the fragment files into
+	// their sections, rather than the entries, and the upgrade notes of the
+	// pull requests into the same section.
+	pullRequests, notes.Upgrades, notes.SecurityNotes = splitFragmentNotices(pullRequests)
+	notes.Upgrades = append(extractUpgradeNotes(pullRequests), notes.Upgrades...)
+
+	// Fold the follow-up pull requests into the pull requests they follow up
+	// on, nest the stacked pull requests beneath the bottom of their stacks,
+	// and summarise the pull requests that only touch vendored code separately.
+	folded, err := foldFixups(pullRequests, config.Fixups)
+	if err != nil {
+		return releaseNotes{}, err
+	}
+	stacked, err := nestStacks(folded, config.Stacks, o.defaultBranchName)
+	if err != nil {
+		return releaseNotes{}, err
+	}
+	notes.PullRequests, notes.Vendored = splitVendored(ctx, o.cmd, stacked, config.Vendored, latestRef.TagName, tagName)
+
+	// Rewrite the relative links in the bodies into absolute URLs, and link
+	// the bare references to issues and pull requests.
+	if config.AbsoluteLinks {
+		ref := linksRef(ctx, o, tagName)
+		for i, pullRequest := range notes.PullRequests {
+			notes.PullRequests[i].Body = absoluteBody(ctx, o.cmd, pullRequest, ref)
+		}
+	}
+	if config.Autolinks {
+		for i, pullRequest := range notes.PullRequests {
+			notes.PullRequests[i].Body = autolinkBody(ctx, o.cmd, pullRequest)
 		}
-		fmt.Printf("%s\n\n", strings.Join(authors, " "))
+	}
 
-		// Output the pull request body.
-		fmt.Printf("%s\n\n", pullRequest.Body)
+	// Get the security advisories published since the latest reference.
+	if config.Security.Advisories {
+		progress.report("Fetching security advisories")
+		ctx, span := tracer.Start(ctx, "security advisories")
+		until, _ := releasePublishedAt(ctx, o, tagName)
+		notes.Advisories, err = getSecurityAdvisories(ctx, o.cmd, latestRef.PublishedAt, until)
+		endSpan(span, err)
+		if err != nil {
+			return releaseNotes{}, err
+		}
 	}
 
-	return nil
+	// Merge the categories and new contributors of the release notes generated
+	// by GitHub.
+	if config.GenerateNotes.Enabled {
+		progress.report("Generating the release notes with GitHub")
+		ctx, span := tracer.Start(ctx, "generate notes")
+		generated, err := getGeneratedNotes(ctx, o.cmd, tagName, latestRef.TagName, o.currentBranchName, config.GenerateNotes)
+		endSpan(span, err)
+		if err != nil {
+			return releaseNotes{}, err
+		}
+		notes.Categories, notes.NewContributors = generated.merge(notes.PullRequests, config.Categories)
+	}
+
+	// Find the authors making their first contribution, for the format in the
+	// style of GitHub.
+	if config.renders(FormatGitHub) && !config.GenerateNotes.Enabled && !o.offline {
+		progress.report("Finding new contributors")
+		ctx, span := tracer.Start(ctx, "new contributors")
+		notes.NewContributors, err = getNewContributors(ctx, o.cmd, notes.PullRequests, config.Authors)
+		endSpan(span, err)
+		if err != nil {
+			log.Warn("Failed to find some of the new contributors", "err", err)
+		}
+	}
+
+	// Thank the authors of the pull requests.
+	if config.Thanks.Enabled {
+		notes.Thanks, err = getThanks(ctx, o.cmd, tagName, pullRequests, config, !o.offline)
+		if err != nil {
+			return releaseNotes{}, err
+		}
+	}
+
+	// Get the CVEs fixed by dependency updates.
+	if config.Security.DependencyCVEs {
+		notes.DependencyCVEs = getDependencyCVEs(pullRequests, notes.Advisories, config.Security)
+	}
+
+	// Get the component-level differences between the SBOMs of the releases.
+	if config.SBOM.enabled() {
+		progress.report("Comparing SBOMs")
+		ctx, span := tracer.Start(ctx, "sbom diff")
+		notes.SBOMDiff, err = getSBOMDiff(ctx, o.cmd, config.SBOM, latestRef.TagName, tagName)
+		endSpan(span, err)
+		if err != nil {
+			return releaseNotes{}, err
+		}
+	}
+
+	// Get the submodules whose commit changed between the releases.
+	if config.Submodules.Enabled {
+		progress.report("Fetching submodule changes")
+		ctx, span := tracer.Start(ctx, "submodule changes")
+		notes.Submodules, err = getSubmoduleChanges(ctx, o.cmd, config.Submodules, latestRef.TagName, tagName)
+		endSpan(span, err)
+		if err != nil {
+			return releaseNotes{}, err
+		}
+	}
+
+	// List the assets of the release, if they are to be published.
+	if config.Publish.Enabled && config.Publish.Assets.Enabled {
+		progress.report("Checksumming release assets")
+		ctx, span := tracer.Start(ctx, "release assets")
+		notes.Assets, err = getReleaseAssets(ctx, o.cmd, tagName, config.Publish.Assets)
+		endSpan(span, err)
+		if err != nil {
+			return releaseNotes{}, err
+		}
+	}
+
+	// Read the links to sponsor the project, if they are to be published.
+	if config.Publish.Enabled && config.Publish.Funding.Enabled {
+		notes.Funding, err = getFundingLinks(ctx, o.cmd, config.Publish.Funding)
+		if err != nil {
+			return releaseNotes{}, err
+		}
+	}
+
+	// Get the provenance of the CI run generating the release notes.
+	if config.Provenance {
+		if provenance, ok := getProvenance(); ok {
+			notes.Provenance = &provenance
+		} else {
+			log.Warn("Provenance is only available when running in GitHub Actions or GitLab CI")
+		}
+	}
+
+	// Normalise the release notes, so that they are identical each time they
+	// are generated, with their dates in the configured timezone.
+	normaliseReleaseNotes(&notes, location)
+
+	return notes, nil
 }
 
-// ===
-// Helper Functions
+// makeReleaseNotes builds the release notes as described by MakeReleaseNotes,
+// outputting them to the configured writer.
+func makeReleaseNotes(ctx context.Context, tagName string, o options) error {
+	w, mode, config := o.writer, o.mode, o.config
+
+	// Output each entry as it is fetched, if configured.
+	if config.Stream {
+		return streamReleaseNotes(ctx, tagName, o)
+	}
 
-func runCmd(command string) (string, error) {
-	// Run the command.
-	cmd := exec.Command(command, strings.Split(command, " ")...)
-	err := cmd.Run()
+	// Collect the release notes.
+	generator := &Generator{tagName: tagName, options: o}
+	notes, err := generator.collected(ctx)
 	if err != nil {
-		// TODO: Handle error from running the command.
-		fmt.Printf("ERROR: [cmd.Run] %v", err)
-		return "", err
+		return err
 	}
 
-	// Get the output.
-	output, err := cmd.Output()
+	// Regenerate the release notes, and check that the output is identical.
+	if config.Check {
+		regenerated, err := collectReleaseNotes(ctx, tagName, o)
+		if err != nil {
+			return err
+		}
+		if err := checkDeterministic(ctx, o.cmd, notes, regenerated, config); err != nil {
+			return err
+		}
+	}
+
+	// Output the drift from the published release notes, and stop there.
+	if config.Diff {
+		return diffReleaseNotes(ctx, o.cmd, w, notes, mode, config)
+	}
+
+	// Keep the release notes for the file that GoReleaser reads, instead, so
+	// that it is only replaced once they have been rendered.
+	var goReleaserNotes bytes.Buffer
+	if config.GoReleaser.Enabled {
+		w = &goReleaserNotes
+	}
+
+	// Keep a copy of the output, to sign.
+	var rendered bytes.Buffer
+	if config.Sign.Method != "" && config.Sign.Output != "" {
+		w = io.MultiWriter(w, &rendered)
+	}
+
+	// Output the release notes in the configured format, split into parts if
+	// configured.
+	renderCtx, span := tracer.Start(ctx, "render", trace.WithAttributes(
+		attribute.String("lorekeeper.format", string(config.Format)),
+	))
+	if config.Pagination.Mode == PaginationParts {
+		err = writeReleaseNotesParts(renderCtx, o.cmd, w, notes, config)
+	} else {
+		err = config.Format.render(renderCtx, o.cmd, w, notes, config)
+	}
+	endSpan(span, err)
 	if err != nil {
-		// TODO: Handle error from running the command.
-		fmt.Printf("ERROR: [cmd.Output] %v", err)
-		return "", err
+		return err
+	}
+
+	// Write the release notes for GoReleaser.
+	if config.GoReleaser.Enabled {
+		if err := writeGoReleaserNotes(config.GoReleaser.output(), goReleaserNotes.Bytes()); err != nil {
+			return &GoReleaserNotesError{Path: config.GoReleaser.output(), Err: err}
+		}
+		log.Info("Wrote release notes for GoReleaser", "path", config.GoReleaser.output())
+	}
+
+	// Write the signed release notes.
+	if config.Sign.Method != "" && config.Sign.Output != "" {
+		if err := writeSignedReleaseNotes(ctx, o.cmd, rendered.Bytes(), config.Sign); err != nil {
+			return err
+		}
+		log.Info("Wrote signed release notes", "path", config.Sign.Output, "method", config.Sign.Method)
+	}
+
+	// Write the static-site fragment.
+	if config.Site.Dir != "" {
+		path, err := writeSiteFragment(notes, config)
+		if err != nil {
+			return err
+		}
+		log.Info("Wrote static-site fragment", "path", path)
+	}
+
+	// Maintain the versioned docs directory.
+	if config.Docs.Dir != "" {
+		if err := writeDocs(notes, config); err != nil {
+			return err
+		}
+		log.Info("Updated release docs", "dir", config.Docs.Dir)
 	}
 
-	// TODO: DEBUG: check the output.
-	fmt.Printf("DEBUG: %q output: %s", command, output)
+	// Publish the release notes.
+	if config.Publish.Enabled {
+		if err := generator.Publish(ctx); err != nil {
+			return err
+		}
+	}
 
-	// Return the output from running the command.
-	return string(output), nil
+	// Post the release notes to the configured destinations.
+	if err := generator.Notify(ctx); err != nil {
+		return err
+	}
+
+	// Delete the fragment files that have been consumed.
+	if config.Fragments.Delete && len(notes.Fragments) > 0 {
+		if err := deleteFragments(ctx, o.cmd, notes.Fragments); err != nil {
+			return err
+		}
+		log.Info("Deleted the consumed fragments", "count", len(notes.Fragments))
+	}
+
+	return nil
 }
+
+// ===
+// Helper Functions
@@ -0,0 +1,206 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// listingProvider is a Provider that outputs the provided tags, releases, and
+// pull requests merged since any time, as `gh` does, with a trailing newline.
+type listingProvider struct {
+	Provider
+	tags     string
+	releases string
+	merged   string
+}
+
+func (p listingProvider) TagCommit(ctx context.Context, tagName string) (string, error) {
+	return "c1\n", nil
+}
+
+func (p listingProvider) Tags(ctx context.Context) (string, error) {
+	return p.tags, nil
+}
+
+func (p listingProvider) Releases(ctx context.Context) (string, error) {
+	return p.releases, nil
+}
+
+func (p listingProvider) MergedPullRequests(ctx context.Context, since string) (string, error) {
+	return p.merged, nil
+}
+
+func TestListedNumbers(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "trailing newline", output: "3\n2\n", want: "3\n2"},
+		{name: "blank lines", output: "\n3\n\n 2 \n", want: "3\n2"},
+		{name: "empty", output: "\n", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listedNumbers(tt.output); got != tt.want {
+				t.Errorf("listedNumbers(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPullRequestsAPIOnly(t *testing.T) {
+	o := newOptions([]Option{
+		WithRepository(Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"}),
+		WithAPIOnly(true),
+		WithMode(ModeTag),
+		WithCurrentBranch("main"),
+		WithDefaultBranch("main"),
+		WithRunner(recordingRunner{commands: &[][]string{}}),
+		WithProvider(listingProvider{
+			tags: `{"publishedAt":"2026-01-04T00:00:00Z","tagName":"v1.1.0"}` + "\n" +
+				`{"publishedAt":"2026-01-02T00:00:00Z","tagName":"v1.0.0"}` + "\n",
+			merged: "3\n2\n",
+		}),
+	})
+
+	listing, err := listPullRequests(context.Background(), "v1.1.0", o, time.UTC)
+	if err != nil {
+		t.Fatalf("listPullRequests() error = %v", err)
+	}
+	if want := "3\n2"; listing.numbers != want {
+		t.Errorf("listPullRequests() = %q, want %q", listing.numbers, want)
+	}
+	if want := "v1.0.0"; listing.latestRef.TagName != want {
+		t.Errorf("listPullRequests() latest ref = %q, want %q", listing.latestRef.TagName, want)
+	}
+}
+
+func TestListPullRequestsReleaseMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		releases   string
+		wantLatest string
+		wantErr    bool
+	}{
+		{
+			name: "latest non-release candidate",
+			releases: `[{"publishedAt":"2026-01-04T00:00:00Z","tagName":"v1.1.0"},` +
+				`{"publishedAt":"2026-01-03T00:00:00Z","tagName":"v1.1.0-rc.1"},` +
+				`{"publishedAt":"2026-01-02T00:00:00Z","tagName":"v1.0.0"},` +
+				`{"publishedAt":"2026-01-01T00:00:00Z","tagName":"v0.9.0"}]` + "\n",
+			wantLatest: "v1.0.0",
+		},
+		{name: "no releases", releases: "[]\n", wantLatest: ""},
+		{name: "invalid", releases: "v1.0.0\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions([]Option{
+				WithRepository(Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"}),
+				WithAPIOnly(true),
+				WithMode(ModeRelease),
+				WithCurrentBranch("main"),
+				WithDefaultBranch("main"),
+				WithReleaseCandidateRegex(`-rc\.[0-9]+$`),
+				WithRunner(recordingRunner{commands: &[][]string{}}),
+				WithProvider(listingProvider{releases: tt.releases, merged: "3\n"}),
+			})
+
+			listing, err := listPullRequests(context.Background(), "v1.1.0", o, time.UTC)
+			if tt.wantErr {
+				var opErr *OperationError
+				if !errors.As(err, &opErr) {
+					t.Fatalf("listPullRequests() error = %v, want *OperationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("listPullRequests() error = %v", err)
+			}
+			if listing.latestRef.TagName != tt.wantLatest {
+				t.Errorf("listPullRequests() latest ref = %q, want %q", listing.latestRef.TagName, tt.wantLatest)
+			}
+		})
+	}
+}
+
+func TestFetchOrderedLookahead(t *testing.T) {
+	const concurrency, count = 2, 10
+
+	// The first entry is slow, so the others are fetched ahead of it.
+	var (
+		started atomic.Int32
+		release = make(chan struct{})
+	)
+	fetch := func(ctx context.Context, i int) (int, error) {
+		started.Add(1)
+		if i == 0 {
+			<-release
+		}
+		return i, nil
+	}
+
+	done := make(chan []int)
+	go func() {
+		var got []int
+		for i, result := range fetchOrdered(context.Background(), concurrency, count, fetch, func(int) {}) {
+			if result.value != i {
+				t.Errorf("fetchOrdered() = %d at %d", result.value, i)
+			}
+			got = append(got, result.value)
+		}
+		done <- got
+	}()
+
+	// Give the fetches ahead of the first time to start.
+	time.Sleep(50 * time.Millisecond)
+	if n := started.Load(); n > concurrency {
+		t.Errorf("fetchOrdered() started %d fetches ahead of the first, want at most %d", n, concurrency)
+	}
+
+	close(release)
+	if got := <-done; len(got) != count {
+		t.Errorf("fetchOrdered() yielded %d entries, want %d", len(got), count)
+	}
+}
+
+// spanProvider is a Provider that records the span of the context that each
+// pull request is retrieved with.
+type spanProvider struct {
+	Provider
+	spanID *trace.SpanID
+}
+
+func (p spanProvider) PullRequest(ctx context.Context, number string) (string, error) {
+	*p.spanID = trace.SpanFromContext(ctx).SpanContext().SpanID()
+	return `{"number":1,"title":"Add a flag"}`, nil
+}
+
+func TestFetchPullRequestSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var spanID trace.SpanID
+	if _, err := fetchPullRequest(context.Background(), options{provider: spanProvider{spanID: &spanID}}, "1"); err != nil {
+		t.Fatalf("fetchPullRequest() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "fetch pull request" {
+		t.Fatalf("fetchPullRequest() spans = %v, want one fetch pull request span", spans)
+	}
+	if want := spans[0].SpanContext().SpanID(); spanID != want {
+		t.Errorf("fetchPullRequest() retrieved the pull request in span %s, want %s", spanID, want)
+	}
+}
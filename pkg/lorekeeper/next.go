@@ -0,0 +1,172 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/riftspire/lorekeeper/pkg/conventional"
+	"golang.org/x/mod/semver"
+)
+
+// versionBump is the part of a semantic version that a change bumps, ordered
+// from the least to the most significant.
+type versionBump int
+
+const (
+	bumpPatch versionBump = iota
+	bumpMinor
+	bumpMajor
+)
+
+// String returns the name of the part of the version that is bumped.
+func (b versionBump) String() string {
+	return [...]string{"patch", "minor", "major"}[b]
+}
+
+// pullRequestBump returns the part of the version that the pull request bumps:
+// major for breaking changes, minor for features, and patch for anything else.
+// The bump is read from the conventional commit title and body of the pull
+// request, or from its changeset.
+func pullRequestBump(pullRequest gitPullRequest) versionBump {
+	// Changesets declare their bump, by which they're categorised.
+	for idx, category := range changesetCategories {
+		if pullRequest.category.Title == category.Title {
+			return versionBump(len(changesetCategories) - 1 - idx)
+		}
+	}
+
+	commit, ok := conventional.Parse(pullRequest.Title, pullRequest.Body)
+	switch {
+	case ok && commit.Breaking:
+		return bumpMajor
+	case ok && commit.Type == "feat":
+		return bumpMinor
+	default:
+		return bumpPatch
+	}
+}
+
+// nextVersion returns the tag of the version following the provided tag, with
+// its part bumped, keeping the directory prefix, i.e - `sub/`, and `v` prefix
+// of the tag. Before 1.0.0, breaking changes bump the minor version instead.
+// A pre-release is followed by its own version, i.e - 1.2.0 for 1.2.0-beta.1,
+// unless the bump is more significant. Without a previous tag, the version
+// follows 0.0.0.
+func nextVersion(tagName string, bump versionBump) (string, error) {
+	if tagName == "" {
+		tagName = "v0.0.0"
+	}
+
+	// Split the directory and `v` prefixes from the version.
+	dir, base := path.Split(tagName)
+	prefix := dir
+	if strings.HasPrefix(base, "v") {
+		prefix += "v"
+	}
+	version := "v" + strings.TrimPrefix(base, "v")
+	if !semver.IsValid(version) {
+		return "", &NextVersionError{TagName: tagName}
+	}
+
+	// Read the major, minor, and patch versions, without the pre-release.
+	var parts [3]int
+	core, _, _ := strings.Cut(strings.TrimPrefix(semver.Canonical(version), "v"), "-")
+	for idx, part := range strings.Split(core, ".") {
+		parts[idx], _ = strconv.Atoi(part)
+	}
+	if bump == bumpMajor && parts[0] == 0 {
+		bump = bumpMinor
+	}
+	preRelease := semver.Prerelease(version) != ""
+
+	switch {
+	case bump == bumpMajor && !(preRelease && parts[1] == 0 && parts[2] == 0):
+		parts = [3]int{parts[0] + 1, 0, 0}
+	case bump == bumpMinor && !(preRelease && parts[2] == 0):
+		parts = [3]int{parts[0], parts[1] + 1, 0}
+	case bump == bumpPatch && !preRelease:
+		parts[2]++
+	}
+
+	return fmt.Sprintf("%s%d.%d.%d", prefix, parts[0], parts[1], parts[2]), nil
+}
+
+// NextVersion represents the predicted version of the next release, and the
+// part of the version that its changes bump.
+type NextVersion struct {
+	// TagName is the tag of the next version, i.e - `v1.3.0`.
+	TagName string
+
+	// Bump is the part of the version that is bumped, one of "major",
+	// "minor", or "patch".
+	Bump string
+
+	// PreviousRef is the tag of the latest release, if any.
+	PreviousRef string
+}
+
+// PredictNextVersion predicts the version of the next release from the changes
+// merged since the latest release (release or tag depending on the mode), by
+// the highest bump of their conventional commit titles, or changesets.
+func PredictNextVersion(ctx context.Context, opts ...Option) (NextVersion, error) {
+	next, _, err := predictNextVersion(ctx, newOptions(opts))
+	return next, err
+}
+
+// predictNextVersion predicts the version of the next release, returning the
+// release notes that the release would have.
+func predictNextVersion(ctx context.Context, o options) (NextVersion, releaseNotes, error) {
+	notes, err := collectUntagged(ctx, o)
+	if err != nil {
+		return NextVersion{}, releaseNotes{}, err
+	}
+
+	// The release bumps the version by the most significant bump of its
+	// changes, leaving out the updates of vendored code.
+	bump := bumpPatch
+	for _, pullRequest := range notes.PullRequests {
+		bump = max(bump, pullRequestBump(pullRequest))
+	}
+	tagName, err := nextVersion(notes.PreviousRef.TagName, bump)
+	if err != nil {
+		return NextVersion{}, releaseNotes{}, err
+	}
+	notes.TagName = tagName
+
+	return NextVersion{TagName: tagName, Bump: bump.String(), PreviousRef: notes.PreviousRef.TagName}, notes, nil
+}
+
+// MakeNextVersionNotes outputs the predicted version of the next release, as
+// described by PredictNextVersion, followed by a preview of its markdown
+// release notes, before the release has been tagged.
+func MakeNextVersionNotes(ctx context.Context, opts ...Option) error {
+	o := newOptions(opts)
+
+	next, notes, err := predictNextVersion(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	var preview bytes.Buffer
+	fmt.Fprintf(&preview, "# %s\n\n", next.TagName)
+	if next.PreviousRef != "" {
+		fmt.Fprintf(&preview, "_A %s release, following %s._\n\n", next.Bump, next.PreviousRef)
+	} else {
+		fmt.Fprintf(&preview, "_The first release._\n\n")
+	}
+
+	// The release notes are nested beneath the version.
+	var markdown bytes.Buffer
+	if err := FormatMarkdown.render(ctx, o.cmd, &markdown, notes, o.config.untagged()); err != nil {
+		return err
+	}
+	preview.WriteString(strings.TrimRight(demoteHeadings(markdown.String(), 1), "\n") + "\n")
+
+	_, err = io.Copy(o.writer, &preview)
+	return err
+}
@@ -0,0 +1,163 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NotifyConfig determines where the release notes are posted once they have
+// been generated, or published.
+type NotifyConfig struct {
+	// Slack is the list of Slack incoming webhook URLs to post the release
+	// notes to. Environment variables in the URLs are expanded, i.e -
+	// `${SLACK_WEBHOOK_URL}`.
+	Slack []string `yaml:"slack"`
+
+	// Discord is the list of Discord webhook URLs to post the release notes
+	// to. Environment variables in the URLs are expanded.
+	Discord []string `yaml:"discord"`
+
+	// Teams is the list of Microsoft Teams incoming webhooks to post the
+	// release notes to, as an Adaptive Card.
+	Teams []TeamsWebhookConfig `yaml:"teams"`
+
+	// Webhooks is the list of endpoints to post the structured JSON release
+	// notes to.
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+
+	// Environment is the environment that lorekeeper is running in, i.e -
+	// `production`, selecting which of the environment specific webhooks are
+	// posted to.
+	Environment string `yaml:"environment"`
+}
+
+// notifier posts markdown release notes for a tag to a list of webhook URLs.
+type notifier func(ctx context.Context, cmd commander, webhookURLs []string, tagName string, markdown string) error
+
+// expandWebhookURLs expands any environment variables in the provided webhook
+// URLs, omitting any that are empty once expanded.
+func expandWebhookURLs(webhookURLs []string) []string {
+	var expanded []string
+	for _, webhookURL := range webhookURLs {
+		if webhookURL = os.ExpandEnv(webhookURL); webhookURL != "" {
+			expanded = append(expanded, webhookURL)
+		}
+	}
+	return expanded
+}
+
+// notify posts the rendered markdown release notes to each of the configured
+// destinations.
+func notify(ctx context.Context, cmd commander, notes releaseNotes, config Config) error {
+	destinations := []struct {
+		webhookURLs []string
+		notify      notifier
+	}{
+		{expandWebhookURLs(config.Notify.Slack), notifySlack},
+		{expandWebhookURLs(config.Notify.Discord), notifyDiscord},
+		{teamsWebhookURLs(config.Notify.Teams, config.Notify.Environment), notifyTeams},
+	}
+
+	var markdown *bytes.Buffer
+	for _, destination := range destinations {
+		if len(destination.webhookURLs) == 0 {
+			continue
+		}
+
+		// Only render the release notes once, when they're first needed.
+		if markdown == nil {
+			markdown = &bytes.Buffer{}
+			if err := renderLintedMarkdown(markdown, notes, config); err != nil {
+				return err
+			}
+		}
+
+		if err := destination.notify(ctx, cmd, destination.webhookURLs, notes.TagName, markdown.String()); err != nil {
+			return err
+		}
+	}
+
+	// Post the structured release notes to the generic webhooks.
+	if len(config.Notify.Webhooks) > 0 {
+		var notesJSON bytes.Buffer
+		if err := renderJSON(&notesJSON, notes, config); err != nil {
+			return err
+		}
+		if err := notifyWebhooks(ctx, cmd, config.Notify.Webhooks, notesJSON.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requestTimeout is how long a request may take before it is abandoned.
+const requestTimeout = 30 * time.Second
+
+// postJSON posts the provided payload as JSON to the URL, returning an error
+// if the response status is not successful.
+func postJSON(ctx context.Context, cmd commander, url string, payload any, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return postBody(ctx, cmd, url, body, headers)
+}
+
+// postBody posts the provided JSON body to the URL, returning an error if the
+// response status is not successful. It isn't retried, as a request that
+// appeared to fail may still have been delivered.
+func postBody(ctx context.Context, cmd commander, url string, body []byte, headers map[string]string) error {
+	// Abandon the request if it outlives the context, or its timeout.
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	// Only the host is logged and traced, as webhook URLs embed their
+	// credentials.
+	_, span := tracer.Start(ctx, "POST", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.request.method", request.Method),
+		attribute.String("server.address", request.URL.Host),
+	))
+	start := time.Now()
+	response, err := cmd.client().Do(request)
+	if err != nil {
+		log.Debug("Request failed", "method", request.Method, "host", request.URL.Host, "duration", time.Since(start))
+		endSpan(span, errors.New("request failed"))
+		return err
+	}
+	defer response.Body.Close()
+	log.Debug("Request completed", "method", request.Method, "host", request.URL.Host, "status", response.StatusCode, "duration", time.Since(start))
+
+	// Record the status on the span before ending it, as the attributes of an
+	// ended span are dropped.
+	span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		err := &HTTPStatusError{Status: response.Status, StatusCode: response.StatusCode, Body: string(bytes.TrimSpace(message))}
+		endSpan(span, errors.New(response.Status))
+		return err
+	}
+	endSpan(span, nil)
+
+	return nil
+}
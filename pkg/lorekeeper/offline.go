@@ -0,0 +1,216 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+var (
+	// reMergeCommit matches the subject of a GitHub merge commit, capturing
+	// the number and head branch (prefixed with its owner) of the pull
+	// request.
+	reMergeCommit = regexp.MustCompile(`^Merge pull request #([0-9]+) from (\S+)`)
+
+	// reSquashCommit matches the subject of a GitHub squash merge commit,
+	// capturing its title and the number of the pull request.
+	reSquashCommit = regexp.MustCompile(`^(.*) \(#([0-9]+)\)$`)
+)
+
+var (
+	errOffline       = errors.New("not available offline")
+	errNoMergeCommit = errors.New("no merge commit found")
+)
+
+// offline returns a copy of the configuration with the features that need the
+// network disabled, warning about each that was enabled.
+func (c Config) offline() Config {
+	if c.Security.Advisories {
+		log.Warn("Security advisories are not available offline")
+		c.Security.Advisories = false
+	}
+	if c.GenerateNotes.Enabled {
+		log.Warn("The release notes cannot be generated with GitHub offline")
+		c.GenerateNotes.Enabled = false
+	}
+	if c.Thanks.External {
+		log.Warn("The external contributors cannot be told apart offline, so all are thanked")
+		c.Thanks.External = false
+	}
+	if c.LinkedIssues {
+		log.Warn("The titles of linked issues cannot be resolved offline")
+		c.LinkedIssues = false
+	}
+	if c.Publish.Enabled {
+		log.Warn("The release notes cannot be published offline")
+		c.Publish.Enabled = false
+	}
+	if !reflect.ValueOf(c.Notify).IsZero() {
+		log.Warn("The release notes cannot be posted to the notification destinations offline")
+		c.Notify = NotifyConfig{}
+	}
+	return c
+}
+
+// gitProvider is a Provider that reads the pull requests from the merge and
+// squash merge commits of the local repository, without the network.
+type gitProvider struct {
+	cmd commander
+}
+
+// NewGitProvider returns a Provider that reads the pull requests from the
+// merge and squash merge commits of the local repository, so that no network
+// is needed. The pull requests have no bodies, labels, or author avatars, and
+// there are no releases.
+func NewGitProvider() Provider {
+	return gitProvider{}
+}
+
+func (p gitProvider) bind(cmd commander) Provider {
+	p.cmd = cmd
+	return p
+}
+
+// pullRequestNumber returns the number of the pull request merged by the
+// commit with the provided subject, if any.
+func pullRequestNumber(subject string) (string, bool) {
+	if matches := reMergeCommit.FindStringSubmatch(subject); matches != nil {
+		return matches[1], true
+	}
+	if matches := reSquashCommit.FindStringSubmatch(subject); matches != nil {
+		return matches[2], true
+	}
+	return "", false
+}
+
+func (p gitProvider) CommitPullRequests(ctx context.Context, sha string) (string, error) {
+	subject, err := p.cmd.run(ctx, "git", "log", "-n", "1", "--format=%s", sha)
+	if err != nil {
+		return "", err
+	}
+	number, _ := pullRequestNumber(strings.TrimSpace(subject))
+	return number, nil
+}
+
+func (p gitProvider) MergedPullRequests(ctx context.Context, since string) (string, error) {
+	// `git log` returns commits in reverse chronological order (newest to
+	// oldest), as `gh pr list` does.
+	subjects, err := p.cmd.run(ctx, "git", "log", "--first-parent", "--since="+since, "--format=%s")
+	if err != nil {
+		return "", err
+	}
+
+	var numbers []string
+	for subject := range strings.SplitSeq(strings.TrimSpace(subjects), "\n") {
+		if number, ok := pullRequestNumber(subject); ok {
+			numbers = append(numbers, number)
+		}
+	}
+	return strings.Join(numbers, "\n"), nil
+}
+
+func (p gitProvider) PullRequest(ctx context.Context, number string) (string, error) {
+	// Find the commit that merged the pull request.
+	commit, err := p.cmd.run(ctx, "git",
+		"log", "-n", "1", "--first-parent", "--extended-regexp",
+		fmt.Sprintf(`--grep=^Merge pull request #%s from |\(#%s\)$`, number, number),
+		"--format=%H%x1f%s%x1f%b%x1f%cI",
+	)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.SplitN(strings.TrimSpace(commit), "\x1f", 4)
+	if len(fields) != 4 {
+		return "", errNoMergeCommit
+	}
+	sha, subject, body, committedAt := fields[0], fields[1], fields[2], fields[3]
+
+	pullRequest := gitPullRequest{}
+	pullRequest.Number, _ = strconv.Atoi(number)
+	pullRequest.MergedAt, _ = time.Parse(time.RFC3339, committedAt)
+
+	// Recover the title, and head branch, from the merge commit. The title of
+	// a merge commit is the first line of its body.
+	if matches := reMergeCommit.FindStringSubmatch(subject); matches != nil {
+		pullRequest.Title, _, _ = strings.Cut(strings.TrimSpace(body), "\n")
+		_, pullRequest.HeadRefName, _ = strings.Cut(matches[2], "/")
+	} else if matches := reSquashCommit.FindStringSubmatch(subject); matches != nil {
+		pullRequest.Title = matches[1]
+	}
+
+	// Link to the pull request, if the repository is known.
+	if repository, ok := p.cmd.knownRepository(); ok {
+		pullRequest.URL = fmt.Sprintf("%s/pull/%s", repository.URL(), number)
+	}
+
+	// The first commit of the repository has no parent to compare with, so
+	// everything up to it is in the pull request.
+	revisions, diff := sha+"^1.."+sha, []string{"diff", "--numstat", sha + "^1", sha}
+	if _, err := p.cmd.run(ctx, "git", "rev-parse", "--verify", "--quiet", sha+"^1"); err != nil {
+		revisions, diff = sha, []string{"diff-tree", "--root", "-r", "--no-commit-id", "--numstat", sha}
+	}
+
+	// List the commits of the pull request, attributed to their authors by
+	// name.
+	commits, err := p.cmd.run(ctx, "git", "log", "--no-merges", "--reverse", "--format=%H%x1f%s%x1f%an", revisions)
+	if err != nil {
+		return "", err
+	}
+	for commit := range strings.SplitSeq(strings.TrimSpace(commits), "\n") {
+		fields := strings.SplitN(commit, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		pullRequest.Commits = append(pullRequest.Commits, gitCommit{
+			OID:             fields[0],
+			MessageHeadline: fields[1],
+			Authors:         []gitAuthor{{Login: fields[2]}},
+		})
+	}
+
+	// List the files changed by the pull request.
+	numstat, err := p.cmd.run(ctx, "git", diff...)
+	if err != nil {
+		return "", err
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(numstat), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		additions, _ := strconv.Atoi(fields[0])
+		deletions, _ := strconv.Atoi(fields[1])
+		pullRequest.Files = append(pullRequest.Files, gitFile{Path: fields[2], Additions: additions, Deletions: deletions})
+	}
+
+	pullRequestJSON, err := json.Marshal(pullRequest)
+	return string(pullRequestJSON), err
+}
+
+func (gitProvider) Release(context.Context, string) (string, error) {
+	return "", &OperationError{Op: "get the release", Err: errOffline}
+}
+
+func (gitProvider) Releases(context.Context) (string, error) {
+	return "", &OperationError{Op: "list the releases", Err: errOffline}
+}
+
+func (gitProvider) DefaultBranch(context.Context) (string, error) {
+	return "", &OperationError{Op: "get the default branch", Err: errOffline}
+}
+
+func (p gitProvider) TagCommit(ctx context.Context, tagName string) (string, error) {
+	return p.cmd.run(ctx, "git", "rev-list", "-n", "1", tagName)
+}
+
+func (gitProvider) Tags(context.Context) (string, error) {
+	return "", &OperationError{Op: "list the tags", Err: errOffline}
+}
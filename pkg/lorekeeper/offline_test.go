@@ -0,0 +1,45 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestGitProviderPullRequest(t *testing.T) {
+	dir := newTestRepository(t, []testCommit{
+		{subject: "Add a flag (#1)", at: "2026-01-01T00:00:00Z", files: map[string]string{"flag.go": "package flag\n"}},
+		{subject: "Fix a bug (#2)", at: "2026-01-02T00:00:00Z", files: map[string]string{"bug.go": "package bug\n"}},
+	})
+	provider := bindProvider(NewGitProvider(), commander{runner: NewExecRunner(), repoPath: dir})
+
+	tests := []struct {
+		number    string
+		wantTitle string
+		wantFiles []string
+	}{
+		{number: "1", wantTitle: "Add a flag", wantFiles: []string{"flag.go"}},
+		{number: "2", wantTitle: "Fix a bug", wantFiles: []string{"bug.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			output, err := provider.PullRequest(context.Background(), tt.number)
+			if err != nil {
+				t.Fatalf("PullRequest() error = %v", err)
+			}
+			var pullRequest gitPullRequest
+			if err := json.Unmarshal([]byte(output), &pullRequest); err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, file := range pullRequest.Files {
+				files = append(files, file.Path)
+			}
+			if pullRequest.Title != tt.wantTitle || !slices.Equal(files, tt.wantFiles) || len(pullRequest.Commits) != 1 {
+				t.Errorf("PullRequest() = %q, %v, %d commits, want %q, %v, 1 commit",
+					pullRequest.Title, files, len(pullRequest.Commits), tt.wantTitle, tt.wantFiles)
+			}
+		})
+	}
+}
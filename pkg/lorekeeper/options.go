@@ -0,0 +1,279 @@
+package lorekeeper
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+// DefaultConcurrency is the default maximum number of calls to the forge that
+// are made in parallel.
+const DefaultConcurrency = 4
+
+// Option configures how the release notes are made.
+type Option func(*options)
+
+// options represents the configuration built from the provided Option values.
+type options struct {
+	writer                io.Writer
+	releaseCandidateRegex string
+	currentBranchName     string
+	defaultBranchName     string
+	mode                  mode
+	config                Config
+	provider              Provider
+	cmd                   commander
+	remote                string
+	apiOnly               bool
+	deepen                bool
+	fetchTags             bool
+	offline               bool
+	unreleased            bool
+	concurrency           int
+	template              string
+	templateFuncs         template.FuncMap
+	titleRewrites         []titleRewrite
+	titleRewritesErr      error
+	issueTitles           *issueTitles
+}
+
+// newOptions returns the options built from the provided Option values, on top
+// of the defaults.
+func newOptions(opts []Option) options {
+	o := options{
+		writer:      os.Stdout,
+		mode:        ModeRelease,
+		cmd:         commander{runner: NewExecRunner(), httpClient: http.DefaultClient},
+		remote:      defaultRemote,
+		concurrency: DefaultConcurrency,
+		issueTitles: &issueTitles{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Use the local repository offline, or the provider plugin, if configured
+	// and no Provider was provided.
+	if o.provider == nil {
+		switch {
+		case o.offline:
+			o.provider = NewGitProvider()
+		case len(o.config.Plugins.Provider) > 0:
+			o.provider = NewExecProvider(o.config.Plugins.Provider...)
+		default:
+			o.provider = NewGitHubCLIProvider()
+		}
+	}
+
+	// Offline, the features that need the network are disabled, regardless of
+	// the configuration.
+	if o.offline {
+		o.config = o.config.offline()
+	}
+
+	// Run the commands with the configured retries, against the repository
+	// detected from the remote once, if it wasn't provided, and read the
+	// header and footer from the root of the working tree. Without a local
+	// clone, there is nothing to detect them from.
+	o.cmd.retry = o.config.Retry
+	if !o.apiOnly {
+		o.cmd.repository = resolveRepository(o.cmd, o.remote)
+		o.config.Header = o.config.Header.inWorktree(o.cmd)
+		o.config.Footer = o.config.Footer.inWorktree(o.cmd)
+	}
+
+	// Cache the pull requests retrieved from the forge, if configured, and
+	// run the commands of the Provider as set by the options.
+	if o.config.Cache.Enabled && !o.offline {
+		o.provider = newCachingProvider(o.provider, o.config.Cache)
+	}
+	o.provider = bindProvider(o.provider, o.cmd)
+
+	// The template overrides the configuration, regardless of the order the
+	// options were provided in.
+	if o.template != "" {
+		o.config.Template = o.template
+	}
+	o.config.templateFuncs = o.templateFuncs
+
+	// Compile the title rewrite rules once, for every entry. Invalid rules are
+	// reported before any entry is fetched.
+	o.titleRewrites, o.titleRewritesErr = compileTitleRewrites(o.config.TitleRewrites)
+
+	return o
+}
+
+// WithWriter sets the writer that the release notes are output to. Defaults to
+// stdout.
+func WithWriter(w io.Writer) Option {
+	return func(o *options) {
+		o.writer = w
+	}
+}
+
+// WithReleaseCandidateRegex sets the regex pattern used to identify tags that
+// are release candidates.
+func WithReleaseCandidateRegex(releaseCandidateRegex string) Option {
+	return func(o *options) {
+		o.releaseCandidateRegex = releaseCandidateRegex
+	}
+}
+
+// WithCurrentBranch sets the name of the current branch. A `refs/heads/` prefix,
+// i.e - as provided by CI, is removed.
+func WithCurrentBranch(currentBranchName string) Option {
+	return func(o *options) {
+		o.currentBranchName = currentBranchName
+	}
+}
+
+// WithDefaultBranch sets the name of the default branch of the repository (i.e
+// - main, master, etc). Defaults to the default branch reported by the forge,
+// or the HEAD of the remote.
+func WithDefaultBranch(defaultBranchName string) Option {
+	return func(o *options) {
+		o.defaultBranchName = defaultBranchName
+	}
+}
+
+// WithMode sets whether GitHub Releases (ModeRelease) or Git Tags (ModeTag)
+// are used to identify releases. Defaults to ModeRelease.
+func WithMode(mode mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithConfig sets the lorekeeper configuration, i.e - as returned by
+// LoadConfig.
+func WithConfig(config Config) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithProvider sets the forge that the pull requests and releases are
+// retrieved from. Defaults to the configured provider plugin, if any, or
+// NewGitHubCLIProvider.
+func WithProvider(provider Provider) Option {
+	return func(o *options) {
+		o.provider = provider
+	}
+}
+
+// WithRunner sets how the external commands, i.e - `git` and `gh`, are run.
+// Defaults to NewExecRunner.
+func WithRunner(runner Runner) Option {
+	return func(o *options) {
+		o.cmd.runner = runner
+	}
+}
+
+// WithHTTPClient sets the client that the HTTP requests, i.e - to the
+// notification webhooks, are made with, allowing proxies, custom CAs, or
+// recording transports to be used. Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.cmd.httpClient = client
+	}
+}
+
+// WithRepository sets the repository that the release notes are made for.
+// Defaults to the repository that the remote points to.
+func WithRepository(repository Repository) Option {
+	return func(o *options) {
+		o.cmd.repository = repository
+	}
+}
+
+// WithRepoPath sets the path of the local repository that the `git` commands
+// are run in, which may be a bare repository, i.e - a mirror. Defaults to the
+// working directory.
+func WithRepoPath(path string) Option {
+	return func(o *options) {
+		o.cmd.repoPath = path
+	}
+}
+
+// WithAPIOnly sets whether the tags, commits, and pull requests are resolved
+// purely through the forge, so that no local clone is needed. The repository
+// must be set with WithRepository.
+func WithAPIOnly(apiOnly bool) Option {
+	return func(o *options) {
+		o.apiOnly = apiOnly
+	}
+}
+
+// WithDeepen sets whether a shallow clone, i.e - a CI checkout, is deepened by
+// fetching its full history and tags from the remote. Otherwise a
+// ShallowCloneError is returned, as the pull requests of the release can't be
+// found from an incomplete history.
+func WithDeepen(deepen bool) Option {
+	return func(o *options) {
+		o.deepen = deepen
+	}
+}
+
+// WithFetchTags sets whether the tags of the remote are fetched before the
+// release notes are made, so that the tag is compared with the tags of the
+// canonical repository, i.e - the `upstream` remote of a fork, rather than the
+// stale local tags.
+func WithFetchTags(fetchTags bool) Option {
+	return func(o *options) {
+		o.fetchTags = fetchTags
+	}
+}
+
+// WithOffline sets whether the release notes are made from the local
+// repository alone, without the network. The pull requests are read from the
+// merge commits with NewGitProvider, and the features that need the network,
+// i.e - publishing, are disabled. Only ModeTag can be used offline.
+func WithOffline(offline bool) Option {
+	return func(o *options) {
+		o.offline = offline
+	}
+}
+
+// WithRemote sets the name of the remote pointing to the canonical
+// repository, i.e - `upstream` in fork-based workflows. The repository and
+// default branch are detected from it, its tags are fetched if WithFetchTags is
+// set, and tags are pushed to it. Defaults to `origin`.
+func WithRemote(remote string) Option {
+	return func(o *options) {
+		if remote != "" {
+			o.remote = remote
+		}
+	}
+}
+
+// WithConcurrency sets the maximum number of calls to the forge, i.e - to
+// fetch the pull requests, that are made in parallel. Lower it on strict rate
+// limits or small runners, and raise it to make the release notes of large
+// releases faster. It must be at least 1. Defaults to DefaultConcurrency.
+func WithConcurrency(concurrency int) Option {
+	return func(o *options) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithTemplateFunc provides a custom function, available to the templates
+// under the provided name, replacing any built-in function, or previously
+// provided function, of the same name. The function must be valid as described
+// by text/template.FuncMap.
+func WithTemplateFunc(name string, fn any) Option {
+	return func(o *options) {
+		if o.templateFuncs == nil {
+			o.templateFuncs = template.FuncMap{}
+		}
+		o.templateFuncs[name] = fn
+	}
+}
+
+// WithTemplate sets the path to a Go text/template file that the markdown
+// release notes are rendered with, overriding the configuration.
+func WithTemplate(path string) Option {
+	return func(o *options) {
+		o.template = path
+	}
+}
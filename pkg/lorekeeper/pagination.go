@@ -0,0 +1,129 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// PaginationMode determines how the release note entries are split up.
+type PaginationMode string
+
+const (
+	// PaginationParts splits the release notes into multiple numbered
+	// documents, written to files.
+	PaginationParts PaginationMode = "parts"
+
+	// PaginationDetails wraps each group of entries in a collapsible
+	// `<details>` element.
+	PaginationDetails PaginationMode = "details"
+)
+
+// defaultEntriesPerPage is the number of entries in each part, or each
+// `<details>` group when there are no categories, when it is not configured.
+const defaultEntriesPerPage = 50
+
+// PaginationConfig determines how very large sets of release note entries are
+// split up, so they remain readable and within forge limits.
+type PaginationConfig struct {
+	// Mode is how the entries are split up. Either "parts" or "details". If
+	// empty, the entries are not split up.
+	Mode PaginationMode `yaml:"mode"`
+
+	// EntriesPerPage is the number of entries in each part, or each
+	// `<details>` group when there are no categories. Defaults to 50.
+	EntriesPerPage int `yaml:"entriesPerPage"`
+
+	// Dir is the directory that the parts are written to. Defaults to the
+	// working directory.
+	Dir string `yaml:"dir"`
+
+	// Name is the file name, without a part number or extension, of the parts.
+	// Defaults to "release-notes".
+	Name string `yaml:"name"`
+}
+
+// validate checks the pagination mode is known.
+func (c PaginationConfig) validate() error {
+	switch c.Mode {
+	case "", PaginationParts, PaginationDetails:
+		return nil
+	default:
+		return &PaginationModeInvalidError{Mode: c.Mode}
+	}
+}
+
+// entriesPerPage returns the configured number of entries per page, or the
+// default if it has not been configured.
+func (c PaginationConfig) entriesPerPage() int {
+	if c.EntriesPerPage <= 0 {
+		return defaultEntriesPerPage
+	}
+	return c.EntriesPerPage
+}
+
+// paginate splits the provided pull requests into pages of the configured
+// size. There is always at least one page.
+func (c PaginationConfig) paginate(pullRequests []gitPullRequest) [][]gitPullRequest {
+	var (
+		pages = [][]gitPullRequest{}
+		size  = c.entriesPerPage()
+	)
+
+	for start := 0; start < len(pullRequests); start += size {
+		pages = append(pages, pullRequests[start:min(start+size, len(pullRequests))])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+
+	return pages
+}
+
+// writeReleaseNotesParts renders the release notes as multiple numbered parts
+// in the configured format, writing each to a file, and outputting the path of
+// each file to the writer. Sections other than the entries are only included
+// in the first part.
+func writeReleaseNotesParts(ctx context.Context, cmd commander, w io.Writer, notes releaseNotes, config Config) error {
+	var (
+		pagination = config.Pagination
+		pages      = pagination.paginate(notes.PullRequests)
+		name       = pagination.Name
+	)
+	if name == "" {
+		name = defaultUploadName
+	}
+
+	for idx, page := range pages {
+		// Only the first part includes the other sections.
+		part := releaseNotes{TagName: notes.TagName, PreviousRef: notes.PreviousRef}
+		if idx == 0 {
+			part = notes
+		}
+		part.PullRequests = page
+		part.Part, part.Parts = idx+1, len(pages)
+
+		// Render the part to its file.
+		path := filepath.Join(pagination.Dir, fmt.Sprintf("%s-%d%s", name, idx+1, config.formatExtension(config.Format)))
+		file, err := os.Create(path)
+		if err != nil {
+			return &PaginationError{Path: path, Err: err}
+		}
+		if err := config.Format.render(ctx, cmd, file, part, config); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return &PaginationError{Path: path, Err: err}
+		}
+
+		// Output the path of the part.
+		if _, err := fmt.Fprintln(w, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// pluginProtocolVersion is the version of the protocol spoken with plugins.
+const pluginProtocolVersion = 1
+
+// PluginsConfig determines the external binaries that add custom output
+// formats, or data sources, to lorekeeper.
+type PluginsConfig struct {
+	// Formats is the list of plugins that render the release notes in custom
+	// formats, selected by using their name as the format.
+	Formats []FormatPluginConfig `yaml:"formats"`
+
+	// Provider is the command, and its arguments, of a plugin that is used as
+	// the Provider in place of the `gh` CLI app.
+	Provider []string `yaml:"provider"`
+}
+
+// FormatPluginConfig represents a plugin that renders the release notes in a
+// custom format.
+type FormatPluginConfig struct {
+	// Name is the name of the format, i.e - `rss`.
+	Name Format `yaml:"name"`
+
+	// Command is the command, and its arguments, of the plugin.
+	Command []string `yaml:"command"`
+
+	// WASM is the path to a WebAssembly (WASI) module that is run, sandboxed,
+	// as the plugin in place of a command.
+	WASM string `yaml:"wasm"`
+
+	// Extension is the file extension of the format, i.e - `.xml`. Defaults to
+	// `.txt`.
+	Extension string `yaml:"extension"`
+}
+
+// validate checks that the format plugin has a name, and either a command or
+// WASM module.
+func (c FormatPluginConfig) validate() error {
+	if c.Name == "" || (len(c.Command) == 0) == (c.WASM == "") {
+		return &PluginError{Name: string(c.Name), Err: errors.New("a name, and either a command or WASM module, are required")}
+	}
+	if c.Name.builtin() {
+		return &PluginError{Name: string(c.Name), Err: errors.New("the name is used by a built-in format")}
+	}
+	if c.WASM == "" {
+		if err := validatePluginCommand(c.Command); err != nil {
+			return &PluginError{Name: string(c.Name), Err: err}
+		}
+	}
+	return nil
+}
+
+// validatePluginCommand checks that the command of a plugin names the binary
+// that is run.
+func validatePluginCommand(command []string) error {
+	if len(command) == 0 || strings.TrimSpace(command[0]) == "" {
+		return errors.New("the command is empty")
+	}
+	return nil
+}
+
+// formatPlugin returns the plugin that renders the provided format, if any.
+func (c PluginsConfig) formatPlugin(format Format) (FormatPluginConfig, bool) {
+	for _, plugin := range c.Formats {
+		if plugin.Name == format {
+			return plugin, true
+		}
+	}
+	return FormatPluginConfig{}, false
+}
+
+// pluginRequest is written to the stdin of a plugin, as JSON.
+type pluginRequest struct {
+	Version int            `json:"version"`
+	Method  string         `json:"method"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// pluginResponse is read from the stdout of a plugin, as JSON.
+type pluginResponse struct {
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// callPlugin runs the plugin command, writing the request for the method to
+// its stdin, and returning the result read from its stdout.
+func callPlugin(ctx context.Context, cmd commander, command []string, method string, params map[string]any) (string, error) {
+	name := strings.Join(command, " ")
+
+	if err := validatePluginCommand(command); err != nil {
+		return "", &PluginError{Name: name, Err: err}
+	}
+	request, err := newPluginRequest(method, params)
+	if err != nil {
+		return "", &PluginError{Name: name, Err: err}
+	}
+
+	// Run the plugin with the Runner, which must be able to write the request
+	// to its stdin.
+	runner, ok := cmd.commandRunner().(InputRunner)
+	if !ok {
+		return "", &PluginError{Name: name, Err: errors.New("the runner can't write to the stdin of commands")}
+	}
+	start := time.Now()
+	output, err := runner.RunInput(ctx, request, command[0], command[1:]...)
+	if err != nil {
+		log.Debug("Plugin failed", "plugin", name, "method", method, "duration", time.Since(start), "err", err)
+		return "", &PluginError{Name: name, Err: err}
+	}
+	log.Debug("Plugin succeeded", "plugin", name, "method", method, "duration", time.Since(start))
+
+	// Read the response.
+	result, err := readPluginResponse([]byte(output))
+	if err != nil {
+		return "", &PluginError{Name: name, Err: err}
+	}
+
+	return result, nil
+}
+
+// newPluginRequest returns the JSON request for the method, written to the
+// stdin of a plugin.
+func newPluginRequest(method string, params map[string]any) ([]byte, error) {
+	return json.Marshal(pluginRequest{Version: pluginProtocolVersion, Method: method, Params: params})
+}
+
+// readPluginResponse returns the result of the JSON response, read from the
+// stdout of a plugin, or the error it describes.
+func readPluginResponse(output []byte) (string, error) {
+	var response pluginResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return "", err
+	}
+	if response.Error != "" {
+		return "", errors.New(response.Error)
+	}
+	return response.Result, nil
+}
+
+// renderPlugin renders the provided release notes with the format plugin,
+// passing them in the structure that is rendered as JSON. WASM modules are
+// run in place of a command, if configured.
+func renderPlugin(ctx context.Context, cmd commander, plugin FormatPluginConfig, notes releaseNotes, config Config) (string, error) {
+	releaseNotes, err := newRenderedJSONReleaseNotes(notes, config)
+	if err != nil {
+		return "", err
+	}
+	params := map[string]any{
+		"format":       plugin.Name,
+		"releaseNotes": releaseNotes,
+	}
+	if plugin.WASM != "" {
+		return callWASMPlugin(ctx, plugin.WASM, "render", params)
+	}
+	return callPlugin(ctx, cmd, plugin.Command, "render", params)
+}
+
+// execProvider is a Provider that calls a plugin.
+type execProvider struct {
+	command []string
+	cmd     commander
+}
+
+// NewExecProvider returns a Provider that calls the plugin run by the
+// provided command and arguments. The plugin is passed the name of the
+// Provider method, and its parameters, and returns its output in the same
+// shape as the `gh` CLI app.
+func NewExecProvider(command ...string) Provider {
+	return execProvider{command: command}
+}
+
+func (p execProvider) bind(cmd commander) Provider {
+	p.cmd = cmd
+	return p
+}
+
+func (p execProvider) CommitPullRequests(ctx context.Context, sha string) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "commitPullRequests", map[string]any{"sha": sha})
+}
+
+func (p execProvider) MergedPullRequests(ctx context.Context, since string) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "mergedPullRequests", map[string]any{"since": since})
+}
+
+func (p execProvider) PullRequest(ctx context.Context, number string) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "pullRequest", map[string]any{"number": number})
+}
+
+func (p execProvider) Release(ctx context.Context, tagName string) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "release", map[string]any{"tagName": tagName})
+}
+
+func (p execProvider) Releases(ctx context.Context) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "releases", nil)
+}
+
+func (p execProvider) DefaultBranch(ctx context.Context) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "defaultBranch", nil)
+}
+
+func (p execProvider) TagCommit(ctx context.Context, tagName string) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "tagCommit", map[string]any{"tagName": tagName})
+}
+
+func (p execProvider) Tags(ctx context.Context) (string, error) {
+	return callPlugin(ctx, p.cmd, p.command, "tags", nil)
+}
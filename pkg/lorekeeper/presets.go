@@ -0,0 +1,66 @@
+package lorekeeper
+
+import "strings"
+
+// ReleaseCandidatePreset is a built-in regex pattern to identify tags that are
+// release candidates, so that one doesn't need to be written by hand.
+type ReleaseCandidatePreset struct {
+	Name        string
+	Regex       string
+	Description string
+}
+
+var (
+	// PresetSemverPrerelease identifies any semantic version with a
+	// pre-release, i.e - `v1.2.0-rc.1` or `v1.2.0-alpha`.
+	PresetSemverPrerelease = ReleaseCandidatePreset{
+		Name:        "semver-prerelease",
+		Regex:       `^v?[0-9]+\.[0-9]+\.[0-9]+-[0-9A-Za-z.-]+(\+[0-9A-Za-z.-]+)?$`,
+		Description: "Semantic versions with a pre-release, i.e - v1.2.0-rc.1 or v1.2.0-alpha.",
+	}
+
+	// PresetRCSuffix identifies tags ending with an `-rc` suffix, i.e -
+	// `v1.2.0-rc.1` or `v1.2.0-rc2`.
+	PresetRCSuffix = ReleaseCandidatePreset{
+		Name:        "rc-suffix",
+		Regex:       `-rc\.?[0-9]*$`,
+		Description: "Tags ending with an -rc suffix, i.e - v1.2.0-rc.1 or v1.2.0-rc2.",
+	}
+
+	// PresetCalverBeta identifies calendar versions with a beta suffix, i.e -
+	// `2024.05-beta.1` or `v2024.5.1-beta`.
+	PresetCalverBeta = ReleaseCandidatePreset{
+		Name:        "calver-beta",
+		Regex:       `^v?[0-9]{4}\.[0-9]{1,2}(\.[0-9]+)?-beta(\.?[0-9]+)?$`,
+		Description: "Calendar versions with a beta suffix, i.e - 2024.05-beta.1 or v2024.5.1-beta.",
+	}
+)
+
+// GetReleaseCandidatePresets returns all the built-in release candidate
+// presets.
+func GetReleaseCandidatePresets() []ReleaseCandidatePreset {
+	return []ReleaseCandidatePreset{
+		PresetSemverPrerelease,
+		PresetRCSuffix,
+		PresetCalverBeta,
+	}
+}
+
+// GetReleaseCandidatePresetByName returns the built-in release candidate
+// preset with the provided name.
+func GetReleaseCandidatePresetByName(name string) (ReleaseCandidatePreset, error) {
+	for _, preset := range GetReleaseCandidatePresets() {
+		if preset.Name == name {
+			return preset, nil
+		}
+	}
+	return ReleaseCandidatePreset{}, &ReleaseCandidatePresetGetByNameError{Name: name}
+}
+
+func getReleaseCandidatePresetNamesString() string {
+	var presetNames []string
+	for _, preset := range GetReleaseCandidatePresets() {
+		presetNames = append(presetNames, preset.Name)
+	}
+	return strings.Join(presetNames, ", ")
+}
@@ -0,0 +1,43 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"os"
+)
+
+// progress reports the progress of long running operations on stderr, on a
+// single line that is rewritten as it progresses.
+type progress struct {
+	enabled bool
+}
+
+// newProgress returns a progress reporter, which is suppressed unless it has
+// been enabled and stderr is a terminal.
+func newProgress(enabled bool) *progress {
+	return &progress{enabled: enabled && isTerminal(os.Stderr)}
+}
+
+// report replaces the current progress line with the formatted message.
+func (p *progress) report(format string, args ...any) {
+	if !p.enabled {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K"+format, args...)
+}
+
+// done clears the progress line.
+func (p *progress) done() {
+	if !p.enabled {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+}
+
+// isTerminal reports whether the provided file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
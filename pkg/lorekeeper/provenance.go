@@ -0,0 +1,68 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// provenance represents where, and from what, the release notes were
+// generated, following the SLSA provenance build definition.
+type provenance struct {
+	Builder     string
+	WorkflowRef string
+	CommitSHA   string
+	RunURL      string
+}
+
+// getProvenance returns the provenance of the current CI run, and whether it is
+// running in a supported CI provider (GitHub Actions or GitLab CI).
+func getProvenance() (provenance, bool) {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		runnerEnvironment := os.Getenv("RUNNER_ENVIRONMENT")
+		if runnerEnvironment == "" {
+			runnerEnvironment = "github-hosted"
+		}
+		return provenance{
+			Builder:     "https://github.com/actions/runner/" + runnerEnvironment,
+			WorkflowRef: os.Getenv("GITHUB_WORKFLOW_REF"),
+			CommitSHA:   os.Getenv("GITHUB_SHA"),
+			RunURL: fmt.Sprintf("%s/%s/actions/runs/%s/attempts/%s",
+				os.Getenv("GITHUB_SERVER_URL"),
+				os.Getenv("GITHUB_REPOSITORY"),
+				os.Getenv("GITHUB_RUN_ID"),
+				os.Getenv("GITHUB_RUN_ATTEMPT"),
+			),
+		}, true
+	case os.Getenv("GITLAB_CI") == "true":
+		return provenance{
+			Builder: fmt.Sprintf("%s/-/runners/%s", os.Getenv("CI_SERVER_URL"), os.Getenv("CI_RUNNER_ID")),
+			WorkflowRef: fmt.Sprintf("%s/%s@%s",
+				os.Getenv("CI_PROJECT_PATH"),
+				os.Getenv("CI_CONFIG_PATH"),
+				os.Getenv("CI_COMMIT_REF_NAME"),
+			),
+			CommitSHA: os.Getenv("CI_COMMIT_SHA"),
+			RunURL:    os.Getenv("CI_JOB_URL"),
+		}, true
+	default:
+		return provenance{}, false
+	}
+}
+
+// renderMarkdownProvenance writes the provenance section of the release notes
+// to the writer as markdown, if the provenance is known.
+func renderMarkdownProvenance(w io.Writer, provenance *provenance) {
+	if provenance == nil {
+		return
+	}
+
+	// Output the provenance header and details.
+	fmt.Fprint(w, "# Provenance\n\n")
+	fmt.Fprintf(w, "- **Builder**: <%s>\n", provenance.Builder)
+	fmt.Fprintf(w, "- **Workflow**: `%s`\n", provenance.WorkflowRef)
+	fmt.Fprintf(w, "- **Commit**: `%s`\n", provenance.CommitSHA)
+	fmt.Fprintf(w, "- **Run**: <%s>\n", provenance.RunURL)
+	fmt.Fprint(w, "\n")
+}
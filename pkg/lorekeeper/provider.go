@@ -0,0 +1,269 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// pullRequestFields are the fields of the pull requests retrieved from the
+// forge. The cached pull requests are keyed by them, so that those retrieved
+// before a field was added aren't used.
+const pullRequestFields = "number,title,url,author,body,commits,labels,mergedAt,headRefName,baseRefName,files,isDraft"
+
+// Provider is the forge hosting the repository, that the pull requests and
+// releases are retrieved from.
+//
+// The methods return the output of the forge as-is, in the shapes produced by
+// the `gh` CLI app. Implement it to retrieve them from another forge, and
+// provide it with WithProvider.
+//
+// TODO: The features beyond the pull requests and releases, i.e - publishing,
+// security advisories, and gists, use the `gh` CLI app directly, so are locked
+// to GitHub. Move them behind the Provider.
+type Provider interface {
+	// CommitPullRequests returns the numbers of the pull requests associated
+	// with the provided commit, one per line.
+	CommitPullRequests(ctx context.Context, sha string) (string, error)
+
+	// MergedPullRequests returns the numbers of the pull requests merged after
+	// the provided time, one per line.
+	MergedPullRequests(ctx context.Context, since string) (string, error)
+
+	// PullRequest returns the JSON details of the provided pull request.
+	PullRequest(ctx context.Context, number string) (string, error)
+
+	// Release returns the JSON publishedAt and tagName of the release for the
+	// provided tag.
+	Release(ctx context.Context, tagName string) (string, error)
+
+	// Releases returns the publishedAt and tagName of every release, as a
+	// JSON array, in reverse chronological order (newest to oldest).
+	Releases(ctx context.Context) (string, error)
+
+	// DefaultBranch returns the name of the default branch of the repository.
+	DefaultBranch(ctx context.Context) (string, error)
+
+	// TagCommit returns the SHA of the commit of the provided tag.
+	TagCommit(ctx context.Context, tagName string) (string, error)
+
+	// Tags returns the JSON publishedAt and tagName of every tag, one per
+	// line, in reverse chronological order (newest to oldest).
+	Tags(ctx context.Context) (string, error)
+}
+
+// githubCLIProvider is a Provider that uses the `gh` CLI app.
+type githubCLIProvider struct {
+	cmd commander
+}
+
+// NewGitHubCLIProvider returns a Provider for GitHub, that uses the `gh` CLI
+// app. It is the default Provider.
+func NewGitHubCLIProvider() Provider {
+	return githubCLIProvider{}
+}
+
+// commitPullRequestsFilter is the jq filter of the pull requests associated
+// with a commit, %[1]s, that keeps those that merged it if there are any, i.e
+// - its merge, or squash merge, commit, otherwise those that were merged, and
+// otherwise all of them, i.e - the open pull request of a release candidate.
+const commitPullRequestsFilter = `. as $all
+| [$all[] | select(.merge_commit_sha == "%[1]s")] as $merging
+| [$all[] | select(.merged_at != null)] as $merged
+| if ($merging | length) > 0 then $merging elif ($merged | length) > 0 then $merged else $all end
+| .[].number`
+
+func (p githubCLIProvider) CommitPullRequests(ctx context.Context, sha string) (string, error) {
+	// The pull requests are associated with the commit by GitHub, rather than
+	// searched for by the SHAs of their heads, which differ from those of the
+	// commits landed by a merge queue, or a rebase merge. A commit may belong
+	// to several pull requests, i.e - those grouped by a merge queue, or
+	// stacked on each other, so only the best matches are kept.
+	return p.cmd.runForge(ctx, "gh",
+		"api", "repos/{owner}/{repo}/commits/"+sha+"/pulls",
+		"--jq", fmt.Sprintf(commitPullRequestsFilter, sha),
+	)
+}
+
+func (p githubCLIProvider) MergedPullRequests(ctx context.Context, since string) (string, error) {
+	// `gh pr list` returns pull requests in reverse chronological order
+	// (newest to oldest) sorted by createdAt, and doesn't let you change it.
+	return p.cmd.runForge(ctx, "gh",
+		"pr", "list",
+		"--state", "merged",
+		"--search", "merged:>"+since,
+		"--json", "number",
+		"--jq", ".[].number",
+	)
+}
+
+func (p githubCLIProvider) PullRequest(ctx context.Context, number string) (string, error) {
+	return p.cmd.runForge(ctx, "gh",
+		"pr", "view", number,
+		"--json", pullRequestFields,
+	)
+}
+
+func (p githubCLIProvider) Release(ctx context.Context, tagName string) (string, error) {
+	return p.cmd.runForge(ctx, "gh",
+		"release", "view", tagName,
+		"--json", "publishedAt,tagName",
+	)
+}
+
+func (p githubCLIProvider) Releases(ctx context.Context) (string, error) {
+	// `gh release list` returns releases in reverse chronological order
+	// (newest to oldest) sorted by createdAt.
+	return p.cmd.runForge(ctx, "gh",
+		"release", "list",
+		"--json", "publishedAt,tagName",
+	)
+}
+
+func (p githubCLIProvider) DefaultBranch(ctx context.Context) (string, error) {
+	return p.cmd.runForge(ctx, "gh",
+		"repo", "view",
+		"--json", "defaultBranchRef",
+		"--jq", ".defaultBranchRef.name",
+	)
+}
+
+func (p githubCLIProvider) TagCommit(ctx context.Context, tagName string) (string, error) {
+	return p.cmd.runForge(ctx, "gh",
+		"api", "repos/{owner}/{repo}/commits/"+tagName,
+		"--jq", ".sha",
+	)
+}
+
+// tagsQuery is the GraphQL query for the tags of the repository, newest first,
+// dated by the tagger of annotated tags, or the commit of lightweight tags. It
+// is paginated by `gh api --paginate`, through the $endCursor and pageInfo.
+const tagsQuery = `query($owner: String!, $name: String!, $endCursor: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/tags/", first: 100, after: $endCursor, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      nodes {
+        name
+        target {
+          ... on Commit { committedDate }
+          ... on Tag { tagger { date } }
+        }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+func (p githubCLIProvider) Tags(ctx context.Context) (string, error) {
+	return p.cmd.runForge(ctx, "gh",
+		"api", "graphql", "--paginate",
+		"-F", "owner={owner}",
+		"-F", "name={repo}",
+		"-f", "query="+tagsQuery,
+		"--jq", `.data.repository.refs.nodes[] | {publishedAt: (.target.tagger.date // .target.committedDate), tagName: .name} | @json`,
+	)
+}
+
+// bindableProvider is a Provider that runs commands, which are run as set by
+// the options once it is bound to their commander.
+type bindableProvider interface {
+	Provider
+	bind(cmd commander) Provider
+}
+
+// bindProvider returns the provided Provider bound to the commander, if it
+// runs commands.
+func bindProvider(provider Provider, cmd commander) Provider {
+	if bindable, ok := provider.(bindableProvider); ok {
+		return bindable.bind(cmd)
+	}
+	return provider
+}
+
+func (p githubCLIProvider) bind(cmd commander) Provider {
+	p.cmd = cmd
+	return p
+}
+
+// runForge runs a command of the forge's CLI app that only reads from the
+// forge, as runForgeOnce, retrying its transient failures.
+func (c commander) runForge(ctx context.Context, name string, args ...string) (string, error) {
+	var output string
+	err := retry(ctx, c.retry, name+" "+args[0], func() (err error) {
+		output, err = c.runForgeOnce(ctx, name, args...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// runForgeOnce runs the forge's CLI app, as run, against the Repository, if it
+// is known. It classifies its failure as an AuthError or RateLimitError where
+// the output of the command shows it to be one. The commands that write to the
+// forge, i.e - creating a gist, are run once, as one that appeared to fail may
+// still have taken effect.
+func (c commander) runForgeOnce(ctx context.Context, name string, args ...string) (string, error) {
+	if repository, ok := c.knownRepository(); ok {
+		args = forgeArgsForRepository(name, args, repository)
+	}
+	output, err := c.run(ctx, name, args...)
+	if err != nil {
+		return "", classifyForgeError(err)
+	}
+	return output, nil
+}
+
+// classifyForgeError returns the provided error of a forge command as an
+// AuthError or RateLimitError, where the output of the command shows it to be
+// one.
+func classifyForgeError(err error) error {
+	var commandErr *CommandError
+	if !errors.As(err, &commandErr) {
+		return err
+	}
+	stderr := strings.ToLower(commandErr.Stderr)
+	switch {
+	case strings.Contains(stderr, "rate limit"), strings.Contains(stderr, "http 429"):
+		return &RateLimitError{Secondary: strings.Contains(stderr, "secondary rate limit"), Err: err}
+	case strings.Contains(stderr, "http 401"), strings.Contains(stderr, "bad credentials"), strings.Contains(stderr, "gh auth login"):
+		return &AuthError{Err: err}
+	default:
+		return err
+	}
+}
+
+// forgeArgsForRepository returns the provided arguments of the `gh` CLI app,
+// targeting the provided repository rather than the one `gh` detects.
+func forgeArgsForRepository(name string, args []string, repository Repository) []string {
+	if len(args) < 2 || name != "gh" {
+		return args
+	}
+
+	switch args[0] {
+	case "api":
+		// The `{owner}` and `{repo}` placeholders are expanded with the
+		// repository, on its host.
+		expanded := make([]string, 0, len(args)+2)
+		for _, arg := range args {
+			arg = strings.ReplaceAll(arg, "{owner}", repository.Owner)
+			expanded = append(expanded, strings.ReplaceAll(arg, "{repo}", repository.Name))
+		}
+		if repository.Host != defaultForgeHost {
+			expanded = slices.Insert(expanded, 1, "--hostname", repository.Host)
+		}
+		return expanded
+	case "repo":
+		// The repository is a positional argument.
+		return slices.Insert(slices.Clone(args), 2, repository.String())
+	case "pr", "release":
+		return slices.Insert(slices.Clone(args), 2, "--repo", repository.String())
+	default:
+		return args
+	}
+}
@@ -0,0 +1,306 @@
+package lorekeeper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PublishConfig determines whether, and how, the release notes are published
+// to the release.
+type PublishConfig struct {
+	// Enabled publishes the release notes as the body of the release.
+	Enabled bool `yaml:"enabled"`
+
+	// Assets determines the content of the assets section of the published
+	// release notes.
+	Assets AssetsConfig `yaml:"assets"`
+
+	// Upload is the list of formats that the release notes are also uploaded
+	// to the release in, as assets named `<UploadName><extension>`.
+	Upload []Format `yaml:"upload"`
+
+	// UploadName is the file name, without an extension, of the uploaded
+	// release notes assets. Defaults to "release-notes".
+	UploadName string `yaml:"uploadName"`
+
+	// Funding determines the content of the sponsorship section of the
+	// published release notes.
+	Funding FundingConfig `yaml:"funding"`
+
+	// Truncate determines how the release notes are truncated when they exceed
+	// the maximum length of a release body.
+	Truncate TruncateConfig `yaml:"truncate"`
+}
+
+// AssetsConfig determines the content of the assets section of the published
+// release notes.
+type AssetsConfig struct {
+	// Enabled lists the assets uploaded to the release, with their SHA-256
+	// checksums.
+	Enabled bool `yaml:"enabled"`
+
+	// ChecksumsFile is the path to a `sha256sum` formatted file to read the
+	// checksums from. If empty, the assets are downloaded and the checksums
+	// computed.
+	ChecksumsFile string `yaml:"checksumsFile"`
+}
+
+// defaultUploadName is the file name, without an extension, of the uploaded
+// release notes assets when no name has been configured.
+const defaultUploadName = "release-notes"
+
+// releaseAsset represents an asset uploaded to a release.
+type releaseAsset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	SHA256 string `json:"-"`
+}
+
+// getReleaseAssets returns the assets uploaded to the release for the provided
+// tag, ordered by name, along with their SHA-256 checksums.
+func getReleaseAssets(ctx context.Context, cmd commander, tagName string, config AssetsConfig) ([]releaseAsset, error) {
+	// Get the assets uploaded to the release.
+	//
+	releaseJSON, err := cmd.runForge(ctx, "gh",
+		"release", "view", tagName,
+		"--json", "assets",
+	)
+	if err != nil {
+		return nil, &ReleaseAssetsError{TagName: tagName, Err: err}
+	}
+
+	var release struct {
+		Assets []releaseAsset `json:"assets"`
+	}
+	if err := json.Unmarshal([]byte(releaseJSON), &release); err != nil {
+		return nil, &ReleaseAssetsError{TagName: tagName, Err: err}
+	}
+
+	// Get the checksums of the assets.
+	var checksums map[string]string
+	if config.ChecksumsFile != "" {
+		checksums, err = readChecksumsFile(config.ChecksumsFile)
+	} else {
+		checksums, err = computeReleaseChecksums(ctx, cmd, tagName)
+	}
+	if err != nil {
+		return nil, &ReleaseAssetsError{TagName: tagName, Err: err}
+	}
+
+	// Attach the checksums to the assets, omitting the checksums file itself
+	// if it has been uploaded.
+	var assets []releaseAsset
+	for _, asset := range release.Assets {
+		if config.ChecksumsFile != "" && asset.Name == filepath.Base(config.ChecksumsFile) {
+			continue
+		}
+		asset.SHA256 = checksums[asset.Name]
+		assets = append(assets, asset)
+	}
+
+	slices.SortFunc(assets, func(a, b releaseAsset) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return assets, nil
+}
+
+// readChecksumsFile reads a `sha256sum` formatted file, returning the checksums
+// keyed by file name.
+func readChecksumsFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	checksums := map[string]string{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Each line is the checksum, followed by the file name, optionally
+		// prefixed with `*` to indicate binary mode.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		name := filepath.Base(strings.TrimPrefix(fields[1], "*"))
+		checksums[name] = strings.ToLower(fields[0])
+	}
+
+	return checksums, scanner.Err()
+}
+
+// computeReleaseChecksums downloads the assets of the release for the provided
+// tag, returning their SHA-256 checksums keyed by file name.
+func computeReleaseChecksums(ctx context.Context, cmd commander, tagName string) (map[string]string, error) {
+	dir, err := os.MkdirTemp("", packageName+"-assets-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// Download the assets of the release.
+	//
+	if _, err := cmd.runForge(ctx, "gh", "release", "download", tagName, "--dir", dir); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		checksum, err := sha256File(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		checksums[entry.Name()] = checksum
+	}
+
+	return checksums, nil
+}
+
+// sha256File returns the hex encoded SHA-256 checksum of the file at the
+// provided path.
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// publish publishes the release notes as the body of the release, and uploads
+// them as assets of the release.
+func publish(ctx context.Context, cmd commander, tagName string, notes releaseNotes, config Config) error {
+	rendered, err := renderPublishedMarkdown(ctx, cmd, notes, config)
+	if err != nil {
+		return err
+	}
+	if err := publishReleaseNotes(ctx, cmd, tagName, rendered); err != nil {
+		return err
+	}
+
+	// Upload the rendered release notes as assets of the release.
+	return uploadReleaseNotes(ctx, cmd, tagName, notes, config)
+}
+
+// publishReleaseNotes sets the body of the release for the provided tag to the
+// rendered release notes.
+func publishReleaseNotes(ctx context.Context, cmd commander, tagName string, rendered []byte) error {
+	// Write the release notes to a temporary file, to avoid quoting issues.
+	file, err := os.CreateTemp("", packageName+"-notes-*.md")
+	if err != nil {
+		return &PublishError{TagName: tagName, Err: err}
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(rendered); err != nil {
+		file.Close()
+		return &PublishError{TagName: tagName, Err: err}
+	}
+	if err := file.Close(); err != nil {
+		return &PublishError{TagName: tagName, Err: err}
+	}
+
+	// Update the body of the release.
+	//
+	if _, err := cmd.runForgeOnce(ctx, "gh", "release", "edit", tagName, "--notes-file", file.Name()); err != nil {
+		return &PublishError{TagName: tagName, Err: err}
+	}
+
+	return nil
+}
+
+// uploadReleaseNotes renders the release notes in each of the configured upload
+// formats, and uploads them as assets of the release for the provided tag,
+// replacing any existing assets with the same name.
+func uploadReleaseNotes(ctx context.Context, cmd commander, tagName string, notes releaseNotes, config Config) error {
+	if len(config.Publish.Upload) == 0 {
+		return nil
+	}
+
+	uploadName := config.Publish.UploadName
+	if uploadName == "" {
+		uploadName = defaultUploadName
+	}
+
+	dir, err := os.MkdirTemp("", packageName+"-upload-")
+	if err != nil {
+		return &PublishError{TagName: tagName, Err: err}
+	}
+	defer os.RemoveAll(dir)
+
+	for _, format := range config.Publish.Upload {
+		// Render the release notes to a file with the asset name.
+		var rendered bytes.Buffer
+		if err := format.render(ctx, cmd, &rendered, notes, config); err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, uploadName+config.formatExtension(format))
+		if err := os.WriteFile(path, rendered.Bytes(), 0o644); err != nil {
+			return &PublishError{TagName: tagName, Err: err}
+		}
+
+		// Upload the file, replacing any existing asset with the same name.
+		if err := uploadReleaseAsset(ctx, cmd, tagName, path); err != nil {
+			return err
+		}
+
+		// Sign the file, and upload its signature alongside it.
+		if config.Sign.Method != "" {
+			signaturePath, err := signFile(ctx, cmd, path, config.Sign)
+			if err != nil {
+				return err
+			}
+			if err := uploadReleaseAsset(ctx, cmd, tagName, signaturePath); err != nil {
+				return err
+			}
+		}
+
+		// Attest the file, and upload its Sigstore bundle alongside it.
+		if config.Sigstore.Enabled {
+			bundlePath, err := attestFile(ctx, cmd, path, notes, config)
+			if err != nil {
+				return err
+			}
+			if err := uploadReleaseAsset(ctx, cmd, tagName, bundlePath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// uploadReleaseAsset uploads the file at the provided path as an asset of the
+// release, replacing any existing asset with the same name.
+func uploadReleaseAsset(ctx context.Context, cmd commander, tagName string, path string) error {
+	if _, err := cmd.runForgeOnce(ctx, "gh", "release", "upload", tagName, path, "--clobber"); err != nil {
+		return &PublishError{TagName: tagName, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// tagCommit returns the SHA of the commit of the provided tag, from the local
+// repository, or the forge in the API-only mode.
+func tagCommit(ctx context.Context, o options, tagName string) (string, error) {
+	if o.apiOnly {
+		sha, err := o.provider.TagCommit(ctx, tagName)
+		return strings.TrimSpace(sha), err
+	}
+
+	// `git ref-list` returns commits in reverse chronological order (newest to
+	// oldest)
+	sha, err := o.cmd.run(ctx, "git", "rev-list", "-n", "1", tagName)
+	return strings.TrimSpace(sha), err
+}
+
+// tagExists reports whether the provided tag exists, in the local repository,
+// or on the forge in the API-only mode.
+func tagExists(ctx context.Context, o options, tagName string) bool {
+	if o.apiOnly {
+		_, err := o.provider.TagCommit(ctx, tagName)
+		return err == nil
+	}
+
+	_, err := o.cmd.run(ctx, "git", "rev-parse", "--quiet", "--verify", "refs/tags/"+tagName)
+	return err == nil
+}
+
+// latestTag returns the JSON publishedAt and tagName of the most recently
+// created tag, from the local repository, or the forge in the API-only mode.
+// If reReleaseCandidate is provided, release candidates are skipped.
+func latestTag(ctx context.Context, o options, reReleaseCandidate *regexp.Regexp) (string, error) {
+	var (
+		tags string
+		err  error
+	)
+	if o.apiOnly {
+		tags, err = o.provider.Tags(ctx)
+	} else {
+		tags, err = o.cmd.run(ctx, "git",
+			"for-each-ref", "refs/tags",
+			"--sort=-creatordate",
+			tagFormat,
+		)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return newestTag(tags, func(tagName string) bool {
+		return reReleaseCandidate != nil && reReleaseCandidate.MatchString(tagName)
+	})
+}
+
+// previousTag returns the JSON publishedAt and tagName of the most recently
+// created tag reachable from the provided tag, other than the tag itself, i.e
+// - the previous tag of the same release branch. If reReleaseCandidate is
+// provided, release candidates are skipped.
+func previousTag(ctx context.Context, cmd commander, tagName string, reReleaseCandidate *regexp.Regexp) (string, error) {
+	tags, err := cmd.run(ctx, "git",
+		"for-each-ref", "refs/tags",
+		"--merged="+tagName,
+		"--sort=-creatordate",
+		tagFormat,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return newestTag(tags, func(name string) bool {
+		return name == tagName || (reReleaseCandidate != nil && reReleaseCandidate.MatchString(name))
+	})
+}
+
+// tagBefore returns the JSON publishedAt and tagName of the tag released before
+// the provided tag, i.e - the previous tag reachable from it in the local
+// repository, or the most recently created tag before it on the forge in the
+// API-only mode. If reReleaseCandidate is provided, release candidates are
+// skipped.
+func tagBefore(ctx context.Context, o options, tagName string, reReleaseCandidate *regexp.Regexp) (string, error) {
+	if !o.apiOnly {
+		return previousTag(ctx, o.cmd, tagName, reReleaseCandidate)
+	}
+
+	tags, err := o.provider.Tags(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The tags are newest first, so skip those up to, and including, the tag.
+	seen := false
+	return newestTag(tags, func(name string) bool {
+		if !seen {
+			seen = name == tagName
+			return true
+		}
+		return reReleaseCandidate != nil && reReleaseCandidate.MatchString(name)
+	})
+}
+
+// listReleases returns the publishedAt and tagName of every release, newest
+// first, from the JSON array of the forge.
+func listReleases(ctx context.Context, o options) ([]gitReference, error) {
+	output, err := o.provider.Releases(ctx)
+	if err != nil || strings.TrimSpace(output) == "" {
+		return nil, err
+	}
+
+	var releases []gitReference
+	if err := json.Unmarshal([]byte(output), &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// tagFormat is the `git for-each-ref` format of the JSON publishedAt and
+// tagName of a tag.
+const tagFormat = `--format={"publishedAt":"%(creatordate:iso-strict)","tagName":"%(refname:short)"}`
+
+// newestTag returns the first of the provided JSON tags, one per line and
+// newest first, that isn't skipped, or an empty string if they all are.
+func newestTag(tags string, skip func(tagName string) bool) (string, error) {
+	if strings.TrimSpace(tags) == "" {
+		return "", nil
+	}
+	for tagJSON := range strings.SplitSeq(strings.TrimSpace(tags), "\n") {
+		var tag gitReference
+		if err := json.Unmarshal([]byte(tagJSON), &tag); err != nil {
+			return "", err
+		}
+		if !skip(tag.TagName) {
+			return tagJSON, nil
+		}
+	}
+	return "", nil
+}
+
+// worktreePath returns the absolute path of the provided path, which is
+// relative to the root of the working tree (i.e - of a submodule), so that it
+// is found when run in a subdirectory or linked worktree. Absolute paths are
+// returned as they are.
+func worktreePath(ctx context.Context, cmd commander, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	root, err := cmd.run(ctx, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return path
+	}
+	return filepath.Join(strings.TrimSpace(root), path)
+}
+
+// isShallow reports whether the local repository is a shallow clone.
+func isShallow(ctx context.Context, cmd commander) bool {
+	shallow, err := cmd.run(ctx, "git", "rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(shallow) == "true"
+}
+
+// ensureFullHistory checks the local repository isn't a shallow clone, whose
+// history and tags are incomplete, deepening it from the remote if enabled.
+func ensureFullHistory(ctx context.Context, o options) error {
+	if !isShallow(ctx, o.cmd) {
+		return nil
+	}
+	if !o.deepen || o.offline {
+		return &ShallowCloneError{Remote: o.remote}
+	}
+
+	log.Info("Deepening the shallow clone", "remote", o.remote)
+	err := retry(ctx, o.cmd.retry, "git fetch", func() error {
+		_, err := o.cmd.run(ctx, "git", "fetch", "--quiet", "--unshallow", "--tags", o.remote)
+		return err
+	})
+	if err != nil {
+		return &OperationError{Op: "deepen the shallow clone", Ref: o.remote, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package lorekeeper
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCommit is a commit of a test repository, adding the provided files, made
+// at the provided time, and tagged with an annotated tag an hour later, if it
+// has one.
+type testCommit struct {
+	subject string
+	at      string
+	files   map[string]string
+	tag     string
+}
+
+// newTestRepository returns the path of a git repository, on the `main`
+// branch, with the provided commits.
+func newTestRepository(t *testing.T, commits []testCommit) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(at string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE="+at,
+			"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com", "GIT_COMMITTER_DATE="+at,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	git("", "init", "--quiet", "--initial-branch=main")
+	for _, commit := range commits {
+		for name, content := range commit.files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			git("", "add", name)
+		}
+		git(commit.at, "commit", "--quiet", "--allow-empty", "--message", commit.subject)
+		if commit.tag != "" {
+			at, err := time.Parse(time.RFC3339, commit.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			git(at.Add(time.Hour).Format(time.RFC3339), "tag", "--annotate", "--message", commit.tag, commit.tag)
+		}
+	}
+	return dir
+}
+
+func TestListPullRequestsTagMode(t *testing.T) {
+	dir := newTestRepository(t, []testCommit{
+		{subject: "Initial commit", at: "2026-01-01T00:00:00Z"},
+		{subject: "Add a flag (#1)", at: "2026-01-02T00:00:00Z", tag: "v1.0.0"},
+		{subject: "Fix a bug (#2)", at: "2026-01-03T00:00:00Z", tag: "v1.1.0-rc.1"},
+		{subject: "Fix another bug (#3)", at: "2026-01-04T00:00:00Z", tag: "v1.1.0"},
+	})
+
+	tests := []struct {
+		tagName string
+		want    string
+	}{
+		{tagName: "v1.0.0", want: "1"},
+		{tagName: "v1.1.0-rc.1", want: "2"},
+		{tagName: "v1.1.0", want: "3\n2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tagName, func(t *testing.T) {
+			o := newOptions([]Option{
+				WithRepoPath(dir),
+				WithOffline(true),
+				WithMode(ModeTag),
+				WithCurrentBranch("main"),
+				WithDefaultBranch("main"),
+				WithReleaseCandidateRegex(`-rc\.[0-9]+$`),
+			})
+			listing, err := listPullRequests(context.Background(), tt.tagName, o, time.UTC)
+			if err != nil {
+				t.Fatalf("listPullRequests() error = %v", err)
+			}
+			if got := strings.TrimSpace(listing.numbers); got != tt.want {
+				t.Errorf("listPullRequests() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package lorekeeper
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// reInlineLink matches an inline markdown link or image, capturing the `!`
+	// of an image, its text, and its destination. The text may hold one level
+	// of brackets, i.e - the image of a linked badge.
+	reInlineLink = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\]]*\])*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+	// reLinkDefinition matches a link reference definition, capturing its
+	// destination.
+	reLinkDefinition = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*<?([^\s>]+)>?`)
+
+	// reHTMLLink matches the `src` or `href` attribute of an HTML tag,
+	// capturing the attribute, and its destination.
+	reHTMLLink = regexp.MustCompile(`\b(src|href)\s*=\s*"([^"]+)"`)
+
+	// reURLScheme matches the scheme of an absolute URL, i.e - `https:` or
+	// `mailto:`.
+	reURLScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+)
+
+// absoluteLink returns the absolute URL of the provided relative link in the
+// body of the pull request, pinned to the provided ref, i.e - the release tag,
+// as a blob URL, or a raw URL for images. Links relative to the root of the
+// repository, or the current directory, are resolved from the root. Anchors
+// alone link to the pull request itself. It reports false for links that are
+// already absolute.
+func absoluteLink(link string, image bool, repository Repository, ref string, pullRequest gitPullRequest) (string, bool) {
+	switch {
+	case link == "", reURLScheme.MatchString(link), strings.HasPrefix(link, "//"):
+		return "", false
+	case strings.HasPrefix(link, "#"):
+		if pullRequest.URL == "" {
+			return "", false
+		}
+		return pullRequest.URL + link, true
+	}
+
+	// Resolve the path from the root of the repository, keeping any query and
+	// anchor.
+	file, suffix := link, ""
+	if idx := strings.IndexAny(link, "?#"); idx != -1 {
+		file, suffix = link[:idx], link[idx:]
+	}
+	file = strings.TrimLeft(path.Clean("/"+file), "/")
+
+	kind := "blob"
+	if image {
+		kind = "raw"
+	}
+	if strings.Contains(repository.Host, "gitlab") {
+		kind = "-/" + kind
+	}
+	return fmt.Sprintf("%s/%s/%s/%s%s", repository.URL(), kind, ref, file, suffix), true
+}
+
+// linkDestination is the position of the destination of a link within a line,
+// and whether the link is an image.
+type linkDestination struct {
+	start, end int
+	image      bool
+}
+
+// inlineLinks returns the destinations of the inline links and images in the
+// provided text, offset by the provided position, including those of the
+// images within the text of a link, i.e - `[![badge](./badge.svg)](./a.md)`.
+func inlineLinks(text string, offset int) []linkDestination {
+	var destinations []linkDestination
+	for _, link := range reInlineLink.FindAllStringSubmatchIndex(text, -1) {
+		destinations = append(destinations, linkDestination{start: offset + link[6], end: offset + link[7], image: link[3] > link[2]})
+		destinations = append(destinations, inlineLinks(text[link[4]:link[5]], offset+link[4])...)
+	}
+	return destinations
+}
+
+// absoluteLinks rewrites the relative links in the provided markdown body of
+// the pull request, i.e - `./docs/foo.md`, into absolute URLs pinned to the
+// provided ref, as described by absoluteLink. Links within code are left as
+// they are.
+func absoluteLinks(markdown string, repository Repository, ref string, pullRequest gitPullRequest) string {
+	var (
+		lines   = strings.Split(markdown, "\n")
+		inFence bool
+	)
+	for idx, line := range lines {
+		// Skip the contents of fenced code blocks.
+		if reFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		// Mask inline code, so the links within it are left as they are.
+		masked := reInlineCode.ReplaceAllStringFunc(line, func(code string) string {
+			return strings.Repeat(" ", len(code))
+		})
+
+		// Find the destination of each link, and whether it is an image.
+		destinations := inlineLinks(masked, 0)
+		if link := reLinkDefinition.FindStringSubmatchIndex(masked); link != nil {
+			destinations = append(destinations, linkDestination{start: link[2], end: link[3]})
+		}
+		for _, link := range reHTMLLink.FindAllStringSubmatchIndex(masked, -1) {
+			destinations = append(destinations, linkDestination{start: link[4], end: link[5], image: masked[link[2]:link[3]] == "src"})
+		}
+
+		// Rewrite each destination, working backwards so the rewritten links
+		// don't move the positions of earlier ones.
+		slices.SortFunc(destinations, func(a, b linkDestination) int {
+			return cmp.Compare(a.start, b.start)
+		})
+		for i := len(destinations) - 1; i >= 0; i-- {
+			link := destinations[i]
+			if absolute, ok := absoluteLink(line[link.start:link.end], link.image, repository, ref, pullRequest); ok {
+				line = line[:link.start] + absolute + line[link.end:]
+			}
+		}
+
+		lines[idx] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// absoluteBody returns the body of the provided pull request with its relative
+// links rewritten into absolute URLs pinned to the provided ref, as described
+// by absoluteLinks. The body is returned as it is if the repository isn't
+// known.
+func absoluteBody(ctx context.Context, cmd commander, pullRequest gitPullRequest, ref string) string {
+	repository, ok := bodyRepository(ctx, cmd, pullRequest)
+	if !ok {
+		return pullRequest.Body
+	}
+	return absoluteLinks(pullRequest.Body, repository, ref, pullRequest)
+}
+
+// linksRef returns the ref that the relative links are pinned to: the tag of
+// the release, or the default branch for the changes that haven't been tagged,
+// i.e - the unreleased changes, or a release train whose name isn't a tag yet.
+func linksRef(ctx context.Context, o options, tagName string) string {
+	if !o.unreleased && o.mode != ModeTrain {
+		return tagName
+	}
+	if o.mode == ModeTrain {
+		if _, err := o.provider.TagCommit(ctx, tagName); err == nil {
+			return tagName
+		}
+	}
+	resolveBranches(ctx, &o)
+	if o.defaultBranchName == "" {
+		return tagName
+	}
+	return o.defaultBranchName
+}
@@ -0,0 +1,66 @@
+package lorekeeper
+
+import "testing"
+
+func TestAbsoluteLinks(t *testing.T) {
+	repository := Repository{Host: "github.com", Owner: "riftspire", Name: "lorekeeper"}
+	pullRequest := gitPullRequest{URL: "https://github.com/riftspire/lorekeeper/pull/1"}
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "link",
+			markdown: "See [the docs](./docs/foo.md).",
+			want:     "See [the docs](https://github.com/riftspire/lorekeeper/blob/v1.0.0/docs/foo.md).",
+		},
+		{
+			name:     "image",
+			markdown: "![screenshot](images/a.png)",
+			want:     "![screenshot](https://github.com/riftspire/lorekeeper/raw/v1.0.0/images/a.png)",
+		},
+		{
+			name:     "root",
+			markdown: "[config](/config/../lorekeeper.yaml#L3)",
+			want:     "[config](https://github.com/riftspire/lorekeeper/blob/v1.0.0/lorekeeper.yaml#L3)",
+		},
+		{
+			name:     "anchor",
+			markdown: "[above](#usage)",
+			want:     "[above](https://github.com/riftspire/lorekeeper/pull/1#usage)",
+		},
+		{
+			name:     "title",
+			markdown: `[the docs](docs/foo.md "Foo")`,
+			want:     `[the docs](https://github.com/riftspire/lorekeeper/blob/v1.0.0/docs/foo.md "Foo")`,
+		},
+		{
+			name:     "definition",
+			markdown: "[docs]: ./docs/foo.md",
+			want:     "[docs]: https://github.com/riftspire/lorekeeper/blob/v1.0.0/docs/foo.md",
+		},
+		{
+			name:     "html",
+			markdown: `<img src="./a.png"> <a href="b.md">b</a>`,
+			want:     `<img src="https://github.com/riftspire/lorekeeper/raw/v1.0.0/a.png"> <a href="https://github.com/riftspire/lorekeeper/blob/v1.0.0/b.md">b</a>`,
+		},
+		{
+			name:     "linked badge",
+			markdown: "[![badge](./a.png)](./b.md)",
+			want:     "[![badge](https://github.com/riftspire/lorekeeper/raw/v1.0.0/a.png)](https://github.com/riftspire/lorekeeper/blob/v1.0.0/b.md)",
+		},
+		{name: "absolute", markdown: "[site](https://example.com/a.md)", want: "[site](https://example.com/a.md)"},
+		{name: "protocol-relative", markdown: "[site](//example.com/a.md)", want: "[site](//example.com/a.md)"},
+		{name: "mailto", markdown: "[mail](mailto:a@example.com)", want: "[mail](mailto:a@example.com)"},
+		{name: "inline code", markdown: "`[a](./a.md)`", want: "`[a](./a.md)`"},
+		{name: "fenced code", markdown: "~~~\n[a](./a.md)\n~~~", want: "~~~\n[a](./a.md)\n~~~"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := absoluteLinks(tt.markdown, repository, "v1.0.0", pullRequest); got != tt.want {
+				t.Errorf("absoluteLinks(%q) =\n%s\nwant\n%s", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
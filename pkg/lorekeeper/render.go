@@ -0,0 +1,393 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/riftspire/lorekeeper/pkg/conventional"
+)
+
+// reAvatarVersion matches the version query parameter of a GitHub avatar URL.
+var reAvatarVersion = regexp.MustCompile(`(v=[0-9]+)`)
+
+// entryGroup represents a titled group of pull requests, i.e - a category or a
+// scope within a category.
+type entryGroup struct {
+	Title        string
+	PullRequests []gitPullRequest
+}
+
+// renderMarkdown writes the provided release notes to the writer as markdown.
+//
+// If categories have been configured, the pull requests are rendered in a
+// section per category, optionally sub-grouped by their conventional commit
+// scope.
+func renderMarkdown(w io.Writer, notes releaseNotes, config Config) {
+	// Output the part number, if the release notes have been split into parts.
+	if notes.Parts > 1 {
+		fmt.Fprintf(w, "_Part %d of %d._\n\n", notes.Part, notes.Parts)
+	}
+
+	// Output the release candidates collapsed into the release.
+	renderMarkdownCandidates(w, notes.Candidates)
+
+	// Output the upgrading and security sections first, so they can't be
+	// missed.
+	renderMarkdownUpgrades(w, notes.Upgrades)
+	renderMarkdownSecurity(w, notes)
+
+	// Output the entries, followed by the sections following them.
+	renderMarkdownPullRequests(w, notes.PullRequests, config)
+	renderMarkdownFooterSections(w, notes, config)
+}
+
+// renderMarkdownFooterSections writes the sections following the entries to
+// the writer as markdown, in order: the vendored changes, submodule changes,
+// dependency changes, installation, assets, provenance, sponsorship, and
+// thanks. Each is left out if there is nothing to report.
+func renderMarkdownFooterSections(w io.Writer, notes releaseNotes, config Config) {
+	renderMarkdownVendored(w, notes.Vendored)
+	renderMarkdownSubmodules(w, notes.Submodules)
+	renderMarkdownSBOMDiff(w, notes.SBOMDiff)
+	renderMarkdownInstall(w, notes, config.Install)
+	renderMarkdownAssets(w, notes.Assets)
+	renderMarkdownProvenance(w, notes.Provenance)
+	renderMarkdownFunding(w, notes.Funding)
+	renderMarkdownThanks(w, notes.Thanks)
+}
+
+// renderMarkdownPullRequests writes the provided pull requests to the writer
+// as markdown, in a section per category, if categories have been configured,
+// and in collapsible pages, if configured.
+func renderMarkdownPullRequests(w io.Writer, pullRequests []gitPullRequest, config Config) {
+	// Without categories, render each pull request at the top level,
+	// optionally in collapsible pages.
+	if len(config.Categories) == 0 {
+		if config.Pagination.Mode != PaginationDetails {
+			renderMarkdownEntries(w, pullRequests, 1, config)
+			return
+		}
+
+		var start int
+		for _, page := range config.Pagination.paginate(pullRequests) {
+			openMarkdownDetails(w, fmt.Sprintf("Entries %d to %d", start+1, start+len(page)))
+			renderMarkdownEntries(w, page, 1, config)
+			closeMarkdownDetails(w)
+			start += len(page)
+		}
+		return
+	}
+
+	for _, category := range groupByCategory(pullRequests) {
+		// Output the category header, or collapsible summary.
+		if config.Pagination.Mode == PaginationDetails {
+			openMarkdownDetails(w, fmt.Sprintf("%s (%d)", category.Title, len(category.PullRequests)))
+		} else {
+			fmt.Fprintf(w, "# %s\n\n", category.Title)
+		}
+
+		if !config.GroupByScope {
+			renderMarkdownEntries(w, category.PullRequests, 2, config)
+		} else {
+			// Output the pull requests without a scope first, followed by a
+			// sub-section per scope.
+			unscoped, scopes := groupByScope(category.PullRequests)
+			renderMarkdownEntries(w, unscoped, 2, config)
+			for _, scope := range scopes {
+				fmt.Fprintf(w, "## %s\n\n", scope.Title)
+				renderMarkdownEntries(w, scope.PullRequests, 3, config)
+			}
+		}
+
+		if config.Pagination.Mode == PaginationDetails {
+			closeMarkdownDetails(w)
+		}
+	}
+}
+
+// renderMarkdownEntries writes each of the provided pull requests to the
+// writer as markdown, with their headers at the provided heading level.
+func renderMarkdownEntries(w io.Writer, pullRequests []gitPullRequest, level int, config Config) {
+	for _, pullRequest := range pullRequests {
+		renderMarkdownEntry(w, pullRequest, level, config)
+	}
+}
+
+// openMarkdownDetails writes the opening of a collapsible `<details>` element
+// with the provided summary to the writer.
+func openMarkdownDetails(w io.Writer, summary string) {
+	fmt.Fprintf(w, "<details>\n<summary>%s</summary>\n\n", html.EscapeString(summary))
+}
+
+// pullRequestReference returns the reference to the provided pull request, as
+// it follows a note attributed to it, i.e - ` (#123)`, or nothing for a note
+// read from a fragment file without a pull request.
+func pullRequestReference(pullRequest gitPullRequest) string {
+	if pullRequest.Number == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (#%d)", pullRequest.Number)
+}
+
+// closeMarkdownDetails writes the closing of a collapsible `<details>` element
+// to the writer.
+func closeMarkdownDetails(w io.Writer) {
+	fmt.Fprint(w, "</details>\n\n")
+}
+
+// renderMarkdownSecurity writes the security section of the release notes to
+// the writer as markdown, if there is anything to report.
+func renderMarkdownSecurity(w io.Writer, notes releaseNotes) {
+	if len(notes.Advisories) == 0 && len(notes.SecurityNotes) == 0 && len(notes.DependencyCVEs) == 0 {
+		return
+	}
+
+	// Output the security header.
+	fmt.Fprint(w, "# Security\n\n")
+
+	// Output a line per advisory, with its severity, summary, and links.
+	for _, advisory := range notes.Advisories {
+		links := []string{fmt.Sprintf("[%s](%s)", advisory.GHSAID, advisory.HTMLURL)}
+		if advisory.CVEID != "" {
+			links = append(links, fmt.Sprintf("[%s](%s)", advisory.CVEID, cveURL(advisory.CVEID)))
+		}
+
+		fmt.Fprintf(w, "- **%s**: %s (%s)\n",
+			strings.ToUpper(advisory.Severity), advisory.Summary, strings.Join(links, ", "),
+		)
+	}
+
+	// Output a line per security note read from the fragment files.
+	for _, note := range notes.SecurityNotes {
+		fmt.Fprintf(w, "- %s%s\n", note.Notes, pullRequestReference(note.PullRequest))
+	}
+
+	// Output a line per CVE fixed by a dependency update.
+	for _, cve := range notes.DependencyCVEs {
+		fmt.Fprintf(w, "- [%s](%s): fixed by %s (#%d)\n",
+			cve.CVEID, cveURL(cve.CVEID), cve.PullRequest.Title, cve.PullRequest.Number,
+		)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// renderMarkdownSBOMDiff writes the dependency changes section of the release
+// notes to the writer as markdown, if there is anything to report.
+func renderMarkdownSBOMDiff(w io.Writer, diff sbomDiff) {
+	if diff.empty() {
+		return
+	}
+
+	// Output the dependency changes header and table.
+	fmt.Fprint(w, "# Dependency Changes\n\n")
+	fmt.Fprint(w, "| Component | Change | Previous | Current |\n")
+	fmt.Fprint(w, "| --- | --- | --- | --- |\n")
+
+	for _, change := range diff.Added {
+		fmt.Fprintf(w, "| %s | Added | | %s |\n", change.Name, change.CurrentVersion)
+	}
+	for _, change := range diff.Updated {
+		fmt.Fprintf(w, "| %s | Updated | %s | %s |\n", change.Name, change.PreviousVersion, change.CurrentVersion)
+	}
+	for _, change := range diff.Removed {
+		fmt.Fprintf(w, "| %s | Removed | %s | |\n", change.Name, change.PreviousVersion)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// renderMarkdownVendored writes the vendored section of the release notes to
+// the writer as markdown, if there is anything to report.
+func renderMarkdownVendored(w io.Writer, changes []vendoredChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	// Output the vendored header.
+	fmt.Fprint(w, "# Vendored\n\n")
+
+	// Output a line per vendored directory, with its upstream commit and pull
+	// requests.
+	for _, change := range changes {
+		var numbers []string
+		for _, pullRequest := range change.PullRequests {
+			numbers = append(numbers, fmt.Sprintf("#%d", pullRequest.Number))
+		}
+
+		update := "updated"
+		if change.UpstreamCommit != "" {
+			upstream := fmt.Sprintf("`%s`", shortSHA(change.UpstreamCommit))
+			if commitURL, ok := change.upstreamCommitURL(); ok {
+				upstream = fmt.Sprintf("[%s](%s)", upstream, commitURL)
+			}
+			update = "updated to upstream " + upstream
+		}
+
+		fmt.Fprintf(w, "- Vendored %s %s (%s)\n", change.Vendored.Name, update, strings.Join(numbers, ", "))
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// renderMarkdownSubmodules writes the submodules section of the release notes
+// to the writer as markdown, if there is anything to report.
+func renderMarkdownSubmodules(w io.Writer, changes []submoduleChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	// Output the submodules header.
+	fmt.Fprint(w, "# Submodules\n\n")
+
+	// Output a line per submodule, with any commits nested beneath it.
+	for _, change := range changes {
+		switch {
+		case change.PreviousCommit == "":
+			fmt.Fprintf(w, "- `%s`: added at `%s`\n", change.Path, shortSHA(change.CurrentCommit))
+		case change.CurrentCommit == "":
+			fmt.Fprintf(w, "- `%s`: removed\n", change.Path)
+		default:
+			update := fmt.Sprintf("`%s` → `%s`", shortSHA(change.PreviousCommit), shortSHA(change.CurrentCommit))
+			if compareURL, ok := change.compareURL(); ok {
+				update = fmt.Sprintf("[%s](%s)", update, compareURL)
+			}
+			fmt.Fprintf(w, "- `%s`: %s\n", change.Path, update)
+		}
+
+		for _, commit := range change.Commits {
+			fmt.Fprintf(w, "  - `%s` %s\n", commit.SHA, commit.Subject)
+		}
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// renderMarkdownAssets writes the assets section of the release notes to the
+// writer as markdown, if there is anything to report.
+func renderMarkdownAssets(w io.Writer, assets []releaseAsset) {
+	if len(assets) == 0 {
+		return
+	}
+
+	// Output the assets header and table.
+	fmt.Fprint(w, "# Assets\n\n")
+	fmt.Fprint(w, "| Asset | SHA-256 |\n")
+	fmt.Fprint(w, "| --- | --- |\n")
+
+	for _, asset := range assets {
+		fmt.Fprintf(w, "| [%s](%s) | `%s` |\n", asset.Name, asset.URL, asset.SHA256)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// renderMarkdownEntry writes a single pull request to the writer as markdown,
+// with its header at the provided heading level.
+func renderMarkdownEntry(w io.Writer, pullRequest gitPullRequest, level int, config Config) {
+	heading := strings.Repeat("#", level)
+
+	// Output the pull request header, referencing its follow-ups alongside
+	// it. Entries read from fragment files may have no pull request.
+	reference := fmt.Sprintf(" (#%d%s)", pullRequest.Number, fixupsSuffix(pullRequest))
+	if pullRequest.Number == 0 {
+		reference = ""
+	}
+	fmt.Fprintf(w, "%s %s%s%s%s\n\n", heading, pullRequest.displayTitle(config), reference, linkedIssuesSuffix(pullRequest.issues), badgesSuffix(pullRequest, config.Badges))
+
+	// Output the weight of the change, if configured.
+	if annotation := config.Size.annotation(pullRequest); annotation != "" {
+		fmt.Fprintf(w, "_%s_\n\n", annotation)
+	}
+
+	// Output the earlier release candidate the pull request was published in.
+	if pullRequest.publishedIn != "" {
+		fmt.Fprintf(w, "_Previously published in %s._\n\n", pullRequest.publishedIn)
+	}
+
+	// Output the pull request authors header.
+	fmt.Fprintf(w, "%s# Authors\n\n", heading)
+
+	// Output the pull request authors.
+	var authors []string
+	for _, author := range pullRequestAuthors(pullRequest, config.Authors) {
+		// Anonymised authors have no avatar to render.
+		if author.AvatarURL == "" {
+			authors = append(authors, author.Login)
+			continue
+		}
+
+		avatarUrl := reAvatarVersion.ReplaceAllString(author.AvatarURL, "s=64&amp;$1")
+		authors = append(authors, fmt.Sprintf("![@%s](%s)", author.Login, avatarUrl))
+	}
+	fmt.Fprintf(w, "%s\n\n", strings.Join(authors, " "))
+
+	// Output the pull request body.
+	fmt.Fprintf(w, "%s\n\n", pullRequest.Body)
+
+	// Output the pull requests stacked on top of it, if any.
+	if len(pullRequest.stacked) > 0 {
+		fmt.Fprintf(w, "%s# Stacked pull requests\n\n", heading)
+		for _, layer := range pullRequest.stacked {
+			fmt.Fprintf(w, "- %s (#%d)\n", layer.displayTitle(config), layer.Number)
+		}
+		fmt.Fprint(w, "\n")
+	}
+
+	// Output the pull request commits, if configured.
+	if config.Commits && len(pullRequest.Commits) > 0 {
+		fmt.Fprintf(w, "%s# Commits\n\n", heading)
+		for _, commit := range pullRequest.Commits {
+			sha := fmt.Sprintf("`%s`", shortSHA(commit.OID))
+			if commitURL, ok := pullRequest.commitURL(commit.OID); ok {
+				sha = fmt.Sprintf("[%s](%s)", sha, commitURL)
+			}
+			fmt.Fprintf(w, "- %s %s\n", sha, commit.MessageHeadline)
+		}
+		fmt.Fprint(w, "\n")
+	}
+}
+
+// groupByCategory groups the provided pull requests by their category, ordered
+// by the category weight. The order of the pull requests within each group is
+// preserved.
+func groupByCategory(pullRequests []gitPullRequest) []entryGroup {
+	var groups []entryGroup
+
+	for _, pullRequest := range sortedByCategory(pullRequests) {
+		title := pullRequest.category.Title
+		if len(groups) == 0 || groups[len(groups)-1].Title != title {
+			groups = append(groups, entryGroup{Title: title})
+		}
+		groups[len(groups)-1].PullRequests = append(groups[len(groups)-1].PullRequests, pullRequest)
+	}
+
+	return groups
+}
+
+// groupByScope splits the provided pull requests into those without a
+// conventional commit scope, and groups of those with one, ordered by the
+// first appearance of each scope.
+func groupByScope(pullRequests []gitPullRequest) ([]gitPullRequest, []entryGroup) {
+	var (
+		unscoped []gitPullRequest
+		groups   []entryGroup
+		index    = map[string]int{}
+	)
+
+	for _, pullRequest := range pullRequests {
+		title, ok := conventional.Parse(pullRequest.Title, "")
+		if !ok || title.Scope == "" {
+			unscoped = append(unscoped, pullRequest)
+			continue
+		}
+
+		idx, exists := index[title.Scope]
+		if !exists {
+			idx = len(groups)
+			index[title.Scope] = idx
+			groups = append(groups, entryGroup{Title: title.Scope})
+		}
+		groups[idx].PullRequests = append(groups[idx].PullRequests, pullRequest)
+	}
+
+	return unscoped, groups
+}
@@ -0,0 +1,291 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/riftspire/lorekeeper/pkg/conventional"
+)
+
+// reHTMLComment matches an HTML comment, i.e - the instructions of a pull
+// request template.
+var reHTMLComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+const (
+	reportCheckBodies = "bodies"
+	reportCheckLabels = "labels"
+	reportCheckTitles = "titles"
+)
+
+// ReportConfig determines the thresholds of the notes quality report, below
+// which the report fails, i.e - as a CI check. Each is a percentage of the pull
+// requests, from 0 (never fails) to 100.
+type ReportConfig struct {
+	// MinScore is the minimum overall score, the percentage of the checks
+	// passed across the pull requests.
+	MinScore int `yaml:"minScore"`
+
+	// MinBodies is the minimum percentage of the pull requests with a body,
+	// besides the comments of the pull request template.
+	MinBodies int `yaml:"minBodies"`
+
+	// MinLabels is the minimum percentage of the pull requests with a label.
+	MinLabels int `yaml:"minLabels"`
+
+	// MinTitles is the minimum percentage of the pull requests with a
+	// conventional commit title.
+	MinTitles int `yaml:"minTitles"`
+}
+
+// validate checks each of the thresholds is a percentage.
+func (c ReportConfig) validate() error {
+	var errs []error
+	for _, threshold := range []struct {
+		name  string
+		value int
+	}{
+		{"minScore", c.MinScore},
+		{"minBodies", c.MinBodies},
+		{"minLabels", c.MinLabels},
+		{"minTitles", c.MinTitles},
+	} {
+		if threshold.value < 0 || threshold.value > 100 {
+			errs = append(errs, &ReportThresholdInvalidError{Name: threshold.name, Value: threshold.value})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotesReport represents the hygiene of the pull requests that make up the
+// release notes of a release.
+type NotesReport struct {
+	// TagName is the tag of the release, or empty for the unreleased changes.
+	TagName string
+
+	// PullRequests is the number of pull requests checked.
+	PullRequests int
+
+	// Checks are the results of each of the checks.
+	Checks []NotesReportCheck
+
+	// Score is the percentage of the checks passed across the pull requests.
+	Score int
+
+	// MinScore is the minimum overall score, if any.
+	MinScore int
+}
+
+// NotesReportCheck represents the result of a single check of the notes
+// quality report.
+type NotesReportCheck struct {
+	// Name is the name of the check, i.e - "bodies".
+	Name string
+
+	// Description describes what the check requires of a pull request.
+	Description string
+
+	// Failing are the numbers of the pull requests failing the check.
+	Failing []int
+
+	// Score is the percentage of the pull requests passing the check.
+	Score int
+
+	// MinScore is the minimum score of the check, if any.
+	MinScore int
+}
+
+// Passed returns whether the check meets its threshold.
+func (c NotesReportCheck) Passed() bool {
+	return c.Score >= c.MinScore
+}
+
+// Passed returns whether the report meets all of its thresholds.
+func (r NotesReport) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed() {
+			return false
+		}
+	}
+	return r.Score >= r.MinScore
+}
+
+// ReportNotesQuality scores the hygiene of the pull requests in the release
+// notes of the provided tag, or of the changes merged since the latest release
+// if no tag is provided: those with an empty body, without a label, or whose
+// title isn't a conventional commit. Pull requests folded into another's entry,
+// i.e - follow-ups, are left out.
+func ReportNotesQuality(ctx context.Context, tagName string, opts ...Option) (NotesReport, error) {
+	o := newOptions(opts)
+	config := o.config
+
+	// Check the thresholds before anything is scored against them.
+	if err := config.Report.validate(); err != nil {
+		return NotesReport{}, err
+	}
+
+	// Collect the release notes of the release, or the pending release.
+	var (
+		notes releaseNotes
+		err   error
+	)
+	if tagName == "" {
+		notes, err = collectUntagged(ctx, o)
+	} else {
+		notes, err = collectReleaseNotes(ctx, tagName, o)
+	}
+	if err != nil && !errors.Is(err, ErrNoPullRequests) {
+		return NotesReport{}, err
+	}
+
+	// Gather the entries, with the layers of their stacks, leaving out those
+	// read from fragment files without a pull request.
+	var pullRequests []gitPullRequest
+	for _, pullRequest := range notes.PullRequests {
+		for _, entry := range append([]gitPullRequest{pullRequest}, pullRequest.stacked...) {
+			if entry.Number != 0 {
+				pullRequests = append(pullRequests, entry)
+			}
+		}
+	}
+
+	report := NotesReport{
+		TagName:      tagName,
+		PullRequests: len(pullRequests),
+		MinScore:     config.Report.MinScore,
+		Checks: []NotesReportCheck{
+			{Name: reportCheckBodies, Description: "Has a body", MinScore: config.Report.MinBodies},
+			{Name: reportCheckLabels, Description: "Has a label", MinScore: config.Report.MinLabels},
+			{Name: reportCheckTitles, Description: "Has a conventional commit title", MinScore: config.Report.MinTitles},
+		},
+	}
+
+	// Run each of the checks against each of the pull requests.
+	var passed int
+	for idx := range report.Checks {
+		check := &report.Checks[idx]
+		for _, pullRequest := range pullRequests {
+			if reportCheckPasses(check.Name, pullRequest) {
+				passed++
+			} else {
+				check.Failing = append(check.Failing, pullRequest.Number)
+			}
+		}
+		check.Score = percentage(len(pullRequests)-len(check.Failing), len(pullRequests))
+	}
+	report.Score = percentage(passed, len(pullRequests)*len(report.Checks))
+
+	return report, nil
+}
+
+// reportCheckPasses returns whether the provided pull request passes the check
+// of the provided name.
+func reportCheckPasses(name string, pullRequest gitPullRequest) bool {
+	switch name {
+	case reportCheckBodies:
+		return strings.TrimSpace(reHTMLComment.ReplaceAllString(pullRequest.Body, "")) != ""
+	case reportCheckLabels:
+		return len(pullRequest.Labels) > 0
+	case reportCheckTitles:
+		_, ok := conventional.Parse(pullRequest.Title, "")
+		return ok
+	default:
+		return false
+	}
+}
+
+// percentage returns the provided part of the total as a whole percentage,
+// rounded down. Nothing to check scores 100.
+func percentage(part, total int) int {
+	if total == 0 {
+		return 100
+	}
+	return part * 100 / total
+}
+
+// MakeNotesReport outputs the notes quality report of the provided tag, or of
+// the changes merged since the latest release, as described by
+// ReportNotesQuality, as a markdown table, i.e - for the summary of a CI job.
+// It returns a NotesQualityError if any of the thresholds aren't met.
+func MakeNotesReport(ctx context.Context, tagName string, opts ...Option) error {
+	o := newOptions(opts)
+
+	report, err := ReportNotesQuality(ctx, tagName, opts...)
+	if err != nil {
+		return err
+	}
+	if err := renderNotesReport(o.writer, report); err != nil {
+		return err
+	}
+
+	// Fail on the checks below their thresholds.
+	if report.Passed() {
+		return nil
+	}
+	var failures []string
+	for _, check := range report.Checks {
+		if !check.Passed() {
+			failures = append(failures, fmt.Sprintf("%s %d%% < %d%%", check.Name, check.Score, check.MinScore))
+		}
+	}
+	if report.Score < report.MinScore {
+		failures = append(failures, fmt.Sprintf("score %d%% < %d%%", report.Score, report.MinScore))
+	}
+	return &NotesQualityError{Failures: failures}
+}
+
+// renderNotesReport writes the provided notes quality report to the writer as
+// a markdown table of the checks, followed by the overall score.
+func renderNotesReport(w io.Writer, report NotesReport) error {
+	var output strings.Builder
+
+	// Output the report header.
+	release := report.TagName
+	if release == "" {
+		release = "the unreleased changes"
+	}
+	fmt.Fprintf(&output, "# Notes quality of %s\n\n", release)
+
+	// Output the table of the checks.
+	fmt.Fprint(&output, "| Check | Score | Threshold | Failing |\n")
+	fmt.Fprint(&output, "| --- | --- | --- | --- |\n")
+	for _, check := range report.Checks {
+		var failing []string
+		for _, number := range check.Failing {
+			failing = append(failing, fmt.Sprintf("#%d", number))
+		}
+		fmt.Fprintf(&output, "| %s %s | %d%% | %s | %s |\n",
+			reportStatus(check.Passed()), check.Description, check.Score, reportThreshold(check.MinScore), strings.Join(failing, ", "),
+		)
+	}
+
+	// Output the overall score.
+	fmt.Fprintf(&output, "\n%s **Score**: %d%% across %d pull requests", reportStatus(report.Passed()), report.Score, report.PullRequests)
+	if report.MinScore > 0 {
+		fmt.Fprintf(&output, " (threshold %d%%)", report.MinScore)
+	}
+	fmt.Fprint(&output, "\n")
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
+
+// reportStatus returns the mark of whether a check passed.
+func reportStatus(passed bool) string {
+	if passed {
+		return "✓"
+	}
+	return "✗"
+}
+
+// reportThreshold returns the threshold of a check as it is rendered, or a
+// dash if it has none.
+func reportThreshold(minScore int) string {
+	if minScore == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", minScore)
+}
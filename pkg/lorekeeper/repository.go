@@ -0,0 +1,145 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// reRemoteURL matches the HTTPS and SSH forms of a remote URL, capturing the
+// host, owner (including any subgroups), and repository, i.e -
+// `git@github.com:owner/repo.git` or `https://gitlab.com/group/sub/repo`.
+var reRemoteURL = regexp.MustCompile(`^(?:(?:https?|ssh|git)://(?:[^@/]+@)?|[^@/:]+@)([^/:]+)(?::[0-9]+)?[/:](.+)/([^/]+?)(?:\.git)?/?$`)
+
+// reRepositoryName matches the `owner/name` shorthand of a GitHub repository.
+var reRepositoryName = regexp.MustCompile(`^([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)$`)
+
+// defaultRemote is the remote pointing to the canonical repository when no
+// remote has been provided.
+const defaultRemote = "origin"
+
+// defaultForgeHost is the host of the forge that repositories are assumed to
+// be on when it isn't known.
+const defaultForgeHost = "github.com"
+
+// Repository identifies a repository hosted on a forge.
+type Repository struct {
+	// Host is the host of the forge, i.e - `github.com`.
+	Host string
+
+	// Owner is the user or organisation owning the repository, including any
+	// subgroups, i.e - `group/subgroup` on GitLab.
+	Owner string
+
+	// Name is the name of the repository.
+	Name string
+}
+
+// String returns the repository as `owner/name`, prefixed with the host if it
+// isn't GitHub, as accepted by the `--repo` flag of the `gh` CLI app.
+func (r Repository) String() string {
+	if r.Host == defaultForgeHost {
+		return r.Owner + "/" + r.Name
+	}
+	return r.Host + "/" + r.Owner + "/" + r.Name
+}
+
+// URL returns the HTTPS URL of the repository.
+func (r Repository) URL() string {
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Name)
+}
+
+// isZero reports whether the repository is unknown.
+func (r Repository) isZero() bool {
+	return r == Repository{}
+}
+
+// ParseRepository parses the provided remote URL, in its HTTPS or SSH form, or
+// `owner/name` shorthand of a GitHub repository, as a Repository.
+func ParseRepository(s string) (Repository, error) {
+	s = strings.TrimSpace(s)
+	if repository, ok := parseRemoteURL(s); ok {
+		return repository, nil
+	}
+	if matches := reRepositoryName.FindStringSubmatch(s); matches != nil {
+		return Repository{Host: defaultForgeHost, Owner: matches[1], Name: matches[2]}, nil
+	}
+	return Repository{}, &RepositoryInvalidError{Repository: s}
+}
+
+// parseRemoteURL parses the provided remote URL, in its HTTPS or SSH form, as
+// a Repository.
+func parseRemoteURL(remote string) (Repository, bool) {
+	matches := reRemoteURL.FindStringSubmatch(strings.TrimSpace(remote))
+	if matches == nil {
+		return Repository{}, false
+	}
+	return Repository{Host: strings.ToLower(matches[1]), Owner: matches[2], Name: matches[3]}, true
+}
+
+// DetectRepository returns the repository that the remote (see WithRemote) of
+// the local repository points to, falling back to the `GITHUB_REPOSITORY`
+// environment variable when there is no such remote.
+func DetectRepository(ctx context.Context, opts ...Option) (Repository, error) {
+	o := newOptions(opts)
+	return detectRepository(ctx, o.cmd, o.remote)
+}
+
+// detectRepository detects the repository as described by DetectRepository.
+func detectRepository(ctx context.Context, cmd commander, remote string) (Repository, error) {
+	// Parse the URL of the remote.
+	remoteURL, err := cmd.run(ctx, "git", "remote", "get-url", remote)
+	if err == nil {
+		if repository, ok := parseRemoteURL(remoteURL); ok {
+			return repository, nil
+		}
+		return Repository{}, &RepositoryInvalidError{Repository: strings.TrimSpace(remoteURL)}
+	}
+
+	// Fall back to the repository that GitHub Actions is running for.
+	if name := os.Getenv("GITHUB_REPOSITORY"); name != "" {
+		repository, err := ParseRepository(name)
+		if err != nil {
+			return Repository{}, err
+		}
+		if serverURL := os.Getenv("GITHUB_SERVER_URL"); serverURL != "" {
+			repository.Host = strings.TrimPrefix(strings.TrimPrefix(serverURL, "https://"), "http://")
+		}
+		return repository, nil
+	}
+
+	return Repository{}, &OperationError{Op: "detect the repository", Err: err}
+}
+
+// resolveRepository returns the provided repository, or the one detected from
+// the remote if it is unknown. The forge commands fall back to the repository
+// detected by the `gh` CLI app if neither is known.
+func resolveRepository(cmd commander, remote string) Repository {
+	if !cmd.repository.isZero() {
+		return cmd.repository
+	}
+	detected, err := detectRepository(context.Background(), cmd, remote)
+	if err != nil {
+		log.Debug("Failed to detect the repository", "err", err)
+		return Repository{}
+	}
+	return detected
+}
+
+// fetchRemoteTags fetches the tags of the remote, so that the tag is compared
+// with the tags of the canonical repository rather than those of a fork.
+func fetchRemoteTags(ctx context.Context, cmd commander, remote string) error {
+	err := retry(ctx, cmd.retry, "git fetch", func() error {
+		_, err := cmd.run(ctx, "git", "fetch", "--quiet", "--tags", remote)
+		return err
+	})
+	if err != nil {
+		return &OperationError{Op: "fetch the tags of the remote", Ref: remote, Err: err}
+	}
+	log.Debug("Fetched the tags of the remote", "remote", remote)
+	return nil
+}
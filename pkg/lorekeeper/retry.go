@@ -0,0 +1,194 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// defaultRetryAttempts is the number of attempts made when none have been
+	// configured.
+	defaultRetryAttempts = 3
+
+	// defaultRetryDelay is the delay before the first retry when none has been
+	// configured.
+	defaultRetryDelay = time.Second
+
+	// defaultRetryMaxDelay is the longest delay between attempts when none has
+	// been configured.
+	defaultRetryMaxDelay = 30 * time.Second
+
+	// defaultRetryJitter is the fraction of each delay that is randomised when
+	// none has been configured.
+	defaultRetryJitter = 0.2
+)
+
+// RetryConfig determines how the transient failures of reading from the forge
+// and the remote, i.e - timeouts, 502s, and secondary rate limits, are
+// retried. Writes, i.e - publishing and notifications, aren't retried, as one
+// that appeared to fail may still have taken effect.
+type RetryConfig struct {
+	// Attempts is the maximum number of attempts, including the first.
+	// Defaults to 3, and 1 disables retrying.
+	Attempts int `yaml:"attempts"`
+
+	// Delay is the delay before the first retry, which doubles for each retry
+	// after it. Defaults to 1s.
+	Delay time.Duration `yaml:"delay"`
+
+	// MaxDelay is the longest delay between attempts. Defaults to 30s.
+	MaxDelay time.Duration `yaml:"maxDelay"`
+
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomised, so that concurrent runs don't retry in lockstep. Defaults to
+	// 0.2, and 0 disables it.
+	Jitter *float64 `yaml:"jitter"`
+}
+
+// attempts returns the configured attempts, or the default if they have not
+// been configured.
+func (c RetryConfig) attempts() int {
+	if c.Attempts == 0 {
+		return defaultRetryAttempts
+	}
+	return c.Attempts
+}
+
+// jitter returns the configured jitter, or the default if it has not been
+// configured.
+func (c RetryConfig) jitter() float64 {
+	if c.Jitter == nil {
+		return defaultRetryJitter
+	}
+	return *c.Jitter
+}
+
+// delay returns the delay before the provided retry, counting from 1, with
+// the jitter applied.
+func (c RetryConfig) delay(retry int) time.Duration {
+	delay, maxDelay, jitter := c.Delay, c.MaxDelay, c.jitter()
+	if delay == 0 {
+		delay = defaultRetryDelay
+	}
+	if maxDelay == 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	// Double the delay for each retry, up to the maximum.
+	for range retry - 1 {
+		delay = min(delay*2, maxDelay)
+	}
+	delay = min(delay, maxDelay)
+
+	// Randomise the delay by up to the jitter fraction either way.
+	return time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// validate checks the retry configuration.
+func (c RetryConfig) validate() error {
+	switch {
+	case c.Attempts < 0:
+		return &RetryConfigInvalidError{Reason: "the attempts cannot be negative"}
+	case c.Delay < 0, c.MaxDelay < 0:
+		return &RetryConfigInvalidError{Reason: "the delays cannot be negative"}
+	case c.jitter() < 0, c.jitter() > 1:
+		return &RetryConfigInvalidError{Reason: "the jitter must be between 0 and 1"}
+	}
+	return nil
+}
+
+// retry calls fn until it succeeds, fails with an error that isn't transient,
+// or the attempts of the provided RetryConfig are exhausted, backing off
+// exponentially between the attempts. It stops once the context is done,
+// returning its error, wrapped with that of the last attempt, if any.
+func retry(ctx context.Context, config RetryConfig, op string, fn func() error) error {
+	// Don't make the first attempt once the context is done.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		// The attempt may have failed because the context is done, i.e - the
+		// command was killed, which is the failure to return.
+		if ctx.Err() != nil {
+			return &RetryCanceledError{Op: op, Err: ctx.Err(), LastErr: err}
+		}
+		if attempt >= config.attempts() || !retryable(err) {
+			return err
+		}
+
+		delay := config.delay(attempt)
+		log.Warn("Retrying after a transient failure", "op", op, "attempt", attempt, "delay", delay, "err", err)
+		select {
+		case <-ctx.Done():
+			return &RetryCanceledError{Op: op, Err: ctx.Err(), LastErr: err}
+		case <-time.After(delay):
+		}
+	}
+}
+
+// transientOutputs are the substrings of the stderr of a command showing its
+// failure to be transient. They are specific to the errors of the network, so
+// that i.e - a pull request titled "Fix the upload timeout", in the output of
+// a command that failed for another reason, isn't mistaken for one.
+var transientOutputs = []string{
+	"http 502", "http 503", "http 504",
+	"i/o timeout", "tls handshake timeout", "client.timeout exceeded", "operation timed out", "connection timed out",
+	"connection reset", "early eof", "rpc failed", "could not read from remote",
+	"secondary rate limit",
+}
+
+// retryable reports whether the provided error is transient, so that the
+// failed call may succeed if retried.
+func retryable(err error) bool {
+	// The rate limit only resets after a while, unless it is a secondary one.
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.Secondary
+	}
+
+	// Rejected credentials won't be accepted the next time either.
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		stderr := strings.ToLower(commandErr.Stderr)
+		for _, output := range transientOutputs {
+			if strings.Contains(stderr, output) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return errors.Is(err, context.DeadlineExceeded)
+}
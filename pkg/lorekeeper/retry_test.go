@@ -0,0 +1,90 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "secondary rate limit", err: &RateLimitError{Secondary: true}, want: true},
+		{name: "primary rate limit", err: &RateLimitError{}},
+		{name: "auth", err: &AuthError{Err: errors.New("HTTP 401")}},
+		{name: "bad gateway", err: &HTTPStatusError{StatusCode: http.StatusBadGateway}, want: true},
+		{name: "not found", err: &HTTPStatusError{StatusCode: http.StatusNotFound}},
+		{name: "http 502", err: &CommandError{Stderr: "HTTP 502: Bad Gateway (https://api.github.com/graphql)"}, want: true},
+		{name: "i/o timeout", err: &CommandError{Stderr: "dial tcp 140.82.112.6:443: i/o timeout"}, want: true},
+		{name: "client timeout", err: &CommandError{Stderr: "net/http: request canceled (Client.Timeout exceeded while awaiting headers)"}, want: true},
+		{name: "git timed out", err: &CommandError{Stderr: "fatal: unable to access 'https://github.com/riftspire/lorekeeper/': Failed to connect to github.com port 443: Connection timed out"}, want: true},
+		{name: "early eof", err: &CommandError{Stderr: "fatal: early EOF"}, want: true},
+		{name: "timeout in a title", err: &CommandError{Stderr: `GraphQL: Could not resolve to a PullRequest titled "Fix the upload timeout"`}},
+		{name: "timed out in a title", err: &CommandError{Stderr: `no pull requests match "Retry the requests that timed out"`}},
+		{name: "unrelated", err: &CommandError{Stderr: "fatal: bad revision 'v1.0.0'"}},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: true},
+		{name: "canceled", err: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryContextDone(t *testing.T) {
+	config := RetryConfig{Attempts: 3, Delay: time.Hour}
+	transientErr := &CommandError{Stderr: "HTTP 502"}
+
+	t.Run("before the first attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var attempts int
+		err := retry(ctx, config, "gh api", func() error {
+			attempts++
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) || attempts != 0 {
+			t.Errorf("retry() = %v after %d attempts, want %v after none", err, attempts, context.Canceled)
+		}
+	})
+
+	t.Run("during an attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// Fail as a command killed by the context does.
+		var attempts int
+		err := retry(ctx, config, "gh api", func() error {
+			attempts++
+			cancel()
+			return &CommandError{Err: &exec.ExitError{}}
+		})
+		var commandErr *CommandError
+		if !errors.Is(err, context.Canceled) || !errors.As(err, &commandErr) || attempts != 1 {
+			t.Errorf("retry() = %v after %d attempts, want %v wrapping the failure after one", err, attempts, context.Canceled)
+		}
+	})
+
+	t.Run("between attempts", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var attempts int
+		err := retry(ctx, config, "gh api", func() error {
+			attempts++
+			return transientErr
+		})
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, transientErr) || attempts != 1 {
+			t.Errorf("retry() = %v after %d attempts, want %v wrapping the failure after one", err, attempts, context.DeadlineExceeded)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// commandTimeout is how long an external command may run before it is killed.
+const commandTimeout = 5 * time.Minute
+
+// Runner runs the external commands that lorekeeper depends on, i.e - `git`
+// and `gh`.
+type Runner interface {
+	// Run runs the named command with the provided arguments, returning its
+	// output.
+	Run(ctx context.Context, name string, args ...string) (string, error)
+}
+
+// InputRunner is a Runner that can also write input to the stdin of the
+// commands it runs, i.e - the requests of plugins. Plugins can only be called
+// with an InputRunner.
+type InputRunner interface {
+	Runner
+
+	// RunInput runs the named command with the provided arguments, writing
+	// the input to its stdin, and returning its output.
+	RunInput(ctx context.Context, input []byte, name string, args ...string) (string, error)
+}
+
+// execRunner is a Runner that runs the commands as processes.
+type execRunner struct{}
+
+// NewExecRunner returns a Runner that runs the commands as processes. It is
+// the default Runner.
+func NewExecRunner() Runner {
+	return execRunner{}
+}
+
+func (r execRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	return r.RunInput(ctx, nil, name, args...)
+}
+
+func (execRunner) RunInput(ctx context.Context, input []byte, name string, args ...string) (string, error) {
+	// Kill the command if it outlives the context, or its timeout.
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	// Run the command, capturing its stderr so that it never reaches stdout.
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	// Get the output.
+	start := time.Now()
+	output, err := cmd.Output()
+	if err != nil {
+		log.Debug("Command failed", "command", cmd.String(), "duration", time.Since(start), "err", err, "stderr", strings.TrimSpace(stderr.String()))
+		return "", &CommandError{Command: cmd.String(), Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+
+	log.Debug("Command succeeded", "command", cmd.String(), "duration", time.Since(start), "output", string(output))
+
+	// Return the output from running the command.
+	return string(output), nil
+}
+
+// commander runs the external commands, i.e - `git` and `gh`, and makes the
+// HTTP requests of lorekeeper, as set by the options: with the Runner and HTTP
+// client, in the local repository, and against the Repository of the forge,
+// retrying their transient failures. The zero value runs them with the
+// defaults.
+type commander struct {
+	runner     Runner
+	httpClient *http.Client
+	retry      RetryConfig
+	repoPath   string
+	repository Repository
+}
+
+// run runs the named command with the provided arguments, with the Runner.
+// The arguments are passed as-is, without a shell, so need no quoting. The
+// `git` commands are run in the local repository, if one was provided.
+func (c commander) run(ctx context.Context, name string, args ...string) (string, error) {
+	if c.repoPath != "" && name == "git" {
+		args = slices.Insert(slices.Clone(args), 0, "-C", c.repoPath)
+	}
+	return c.commandRunner().Run(ctx, name, args...)
+}
+
+// commandRunner returns the Runner, or the default Runner if there isn't one.
+func (c commander) commandRunner() Runner {
+	if c.runner == nil {
+		return NewExecRunner()
+	}
+	return c.runner
+}
+
+// client returns the HTTP client, or the default client if there isn't one.
+func (c commander) client() *http.Client {
+	if c.httpClient == nil {
+		return http.DefaultClient
+	}
+	return c.httpClient
+}
+
+// knownRepository returns the Repository of the forge, if it is known.
+func (c commander) knownRepository() (Repository, bool) {
+	return c.repository, !c.repository.isZero()
+}
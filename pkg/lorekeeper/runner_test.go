@@ -0,0 +1,105 @@
+package lorekeeper
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordingRunner is a Runner that records the commands that it runs.
+type recordingRunner struct {
+	commands *[][]string
+}
+
+func (r recordingRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	*r.commands = append(*r.commands, append([]string{name}, args...))
+	return "", nil
+}
+
+func TestCommanderRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoPath string
+		command  []string
+		want     []string
+	}{
+		{
+			name:    "git",
+			command: []string{"git", "log", "--format=%H"},
+			want:    []string{"git", "log", "--format=%H"},
+		},
+		{
+			name:     "git in a repository",
+			repoPath: "/path/with spaces",
+			command:  []string{"git", "log", "--format=%H"},
+			want:     []string{"git", "-C", "/path/with spaces", "log", "--format=%H"},
+		},
+		{
+			name:     "arguments with spaces",
+			repoPath: "/repo",
+			command:  []string{"git", "tag", "-m", "Release v1.0.0", "v1.0.0"},
+			want:     []string{"git", "-C", "/repo", "tag", "-m", "Release v1.0.0", "v1.0.0"},
+		},
+		{
+			name:     "git in a windows repository",
+			repoPath: `C:\Users\Jane Doe\repo`,
+			command:  []string{"git", "show", `v1.0.0:docs\notes.md`},
+			want:     []string{"git", "-C", `C:\Users\Jane Doe\repo`, "show", `v1.0.0:docs\notes.md`},
+		},
+		{
+			name:     "arguments with quotes",
+			repoPath: "/repo",
+			command:  []string{"git", "log", `--format=%H "%s"`, `--grep='fix'`},
+			want:     []string{"git", "-C", "/repo", "log", `--format=%H "%s"`, `--grep='fix'`},
+		},
+		{
+			name:     "forge",
+			repoPath: "/repo",
+			command:  []string{"gh", "pr", "view", "1"},
+			want:     []string{"gh", "pr", "view", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands [][]string
+			cmd := commander{runner: recordingRunner{commands: &commands}, repoPath: tt.repoPath}
+			args := slices.Clone(tt.command[1:])
+			if _, err := cmd.run(context.Background(), tt.command[0], args...); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			if len(commands) != 1 || !slices.Equal(commands[0], tt.want) {
+				t.Fatalf("run() ran %q, want %q", commands, tt.want)
+			}
+			if !slices.Equal(args, tt.command[1:]) {
+				t.Errorf("run() modified the arguments to %q", args)
+			}
+		})
+	}
+}
+
+func TestExecRunnerArguments(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Check the arguments reach the command as they are, without a shell to
+	// split, unquote, or expand them.
+	for _, value := range []string{
+		"Release v1.0.0",
+		`Release "v1.0.0"`,
+		"It's $HOME and %PATH%",
+		`C:\Users\Jane Doe\repo`,
+	} {
+		t.Run(value, func(t *testing.T) {
+			output, err := NewExecRunner().Run(context.Background(), "git", "-c", "lorekeeper.value="+value, "config", "--get", "lorekeeper.value")
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got := strings.TrimRight(output, "\r\n"); got != value {
+				t.Errorf("Run() = %q, want %q", got, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package lorekeeper
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// SBOMConfig determines the content of the dependency changes section of the
+// release notes.
+type SBOMConfig struct {
+	// Previous is the path to the CycloneDX or SPDX JSON SBOM of the previous
+	// release.
+	Previous string `yaml:"previous"`
+
+	// Current is the path to the CycloneDX or SPDX JSON SBOM of the current
+	// release.
+	Current string `yaml:"current"`
+
+	// Go generates the SBOMs from the `go.mod` file at the previous and current
+	// release, instead of reading them from files.
+	Go bool `yaml:"go"`
+}
+
+// enabled reports whether the dependency changes section has been configured.
+func (c SBOMConfig) enabled() bool {
+	return c.Go || c.Previous != "" || c.Current != ""
+}
+
+// sbomComponent represents a single component of an SBOM.
+type sbomComponent struct {
+	Name    string
+	Version string
+
+	// PURL is the package URL of the component, if any, i.e -
+	// `pkg:npm/left-pad@1.3.0`, which tells apart the components of the same
+	// name from different ecosystems.
+	PURL string
+}
+
+// key returns what identifies the component across versions: its package URL
+// without the version, or its name if it has none.
+func (c sbomComponent) key() string {
+	if c.PURL == "" {
+		return c.Name
+	}
+	purl, _, _ := strings.Cut(c.PURL, "?")
+	purl, _, _ = strings.Cut(purl, "#")
+	if idx := strings.LastIndex(purl, "@"); idx > strings.LastIndex(purl, "/") {
+		purl = purl[:idx]
+	}
+	return purl
+}
+
+// sbomChange represents the change to a single component between two SBOMs.
+type sbomChange struct {
+	Name            string
+	PreviousVersion string
+	CurrentVersion  string
+}
+
+// sbomDiff represents the component-level differences between two SBOMs.
+type sbomDiff struct {
+	Added   []sbomChange
+	Removed []sbomChange
+	Updated []sbomChange
+}
+
+// empty reports whether there are no differences between the SBOMs.
+func (d sbomDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+type cycloneDXDocument struct {
+	BOMFormat  string `json:"bomFormat"`
+	Components []struct {
+		Group   string `json:"group"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		PURL    string `json:"purl"`
+	} `json:"components"`
+}
+
+type spdxDocument struct {
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// getSBOMDiff returns the differences between the SBOMs of the previous and
+// current release, either read from the configured files or generated from the
+// `go.mod` file at the provided refs.
+func getSBOMDiff(ctx context.Context, cmd commander, config SBOMConfig, previousRef, currentRef string) (sbomDiff, error) {
+	var (
+		previous, current []sbomComponent
+		err               error
+	)
+
+	if config.Go {
+		if previous, err = goModuleComponents(ctx, cmd, previousRef); err != nil {
+			return sbomDiff{}, err
+		}
+		if current, err = goModuleComponents(ctx, cmd, currentRef); err != nil {
+			return sbomDiff{}, err
+		}
+	} else {
+		if previous, err = readSBOM(config.Previous); err != nil {
+			return sbomDiff{}, err
+		}
+		if current, err = readSBOM(config.Current); err != nil {
+			return sbomDiff{}, err
+		}
+	}
+
+	return diffSBOMComponents(previous, current), nil
+}
+
+// readSBOM reads the components of the CycloneDX or SPDX JSON SBOM at the
+// provided path.
+func readSBOM(path string) ([]sbomComponent, error) {
+	if path == "" {
+		return nil, &SBOMError{Err: fmt.Errorf("both the previous and current SBOM must be provided")}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &SBOMError{Path: path, Err: err}
+	}
+
+	var components []sbomComponent
+
+	// Try to parse the SBOM as CycloneDX.
+	var cycloneDX cycloneDXDocument
+	if err := json.Unmarshal(data, &cycloneDX); err == nil && cycloneDX.BOMFormat == "CycloneDX" {
+		for _, component := range cycloneDX.Components {
+			name := component.Name
+			if component.Group != "" {
+				name = component.Group + "/" + component.Name
+			}
+			components = append(components, sbomComponent{Name: name, Version: component.Version, PURL: component.PURL})
+		}
+		return components, nil
+	}
+
+	// Try to parse the SBOM as SPDX.
+	var spdx spdxDocument
+	if err := json.Unmarshal(data, &spdx); err == nil && spdx.SPDXVersion != "" {
+		for _, pkg := range spdx.Packages {
+			component := sbomComponent{Name: pkg.Name, Version: pkg.VersionInfo}
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					component.PURL = ref.ReferenceLocator
+				}
+			}
+			components = append(components, component)
+		}
+		return components, nil
+	}
+
+	return nil, &SBOMError{Path: path, Err: fmt.Errorf("not a CycloneDX or SPDX JSON document")}
+}
+
+// goModuleComponents returns the modules required by the `go.mod` file at the
+// provided ref as SBOM components.
+func goModuleComponents(ctx context.Context, cmd commander, ref string) ([]sbomComponent, error) {
+	if ref == "" {
+		return nil, &SBOMError{Err: fmt.Errorf("no previous release to compare go.mod against")}
+	}
+
+	// Read the `go.mod` file at the ref.
+	goMod, err := cmd.run(ctx, "git", "show", ref+":go.mod")
+	if err != nil {
+		return nil, &SBOMError{Path: ref + ":go.mod", Err: err}
+	}
+
+	file, err := modfile.ParseLax("go.mod", []byte(goMod), nil)
+	if err != nil {
+		return nil, &SBOMError{Path: ref + ":go.mod", Err: err}
+	}
+
+	var components []sbomComponent
+	for _, require := range file.Require {
+		components = append(components, sbomComponent{
+			Name:    require.Mod.Path,
+			Version: require.Mod.Version,
+		})
+	}
+
+	return components, nil
+}
+
+// diffSBOMComponents returns the differences between the previous and current
+// components, each ordered by component name. The components are keyed by
+// their package URL, or name, so that those of the same name from different
+// ecosystems, or several versions of the same component, are kept apart. A
+// component with a single version before and after is updated, otherwise the
+// versions that differ are added or removed.
+func diffSBOMComponents(previous, current []sbomComponent) sbomDiff {
+	type versions struct {
+		name     string
+		versions []string
+	}
+	group := func(components []sbomComponent) map[string]*versions {
+		grouped := map[string]*versions{}
+		for _, component := range components {
+			key := component.key()
+			if grouped[key] == nil {
+				grouped[key] = &versions{name: component.Name}
+			}
+			if !slices.Contains(grouped[key].versions, component.Version) {
+				grouped[key].versions = append(grouped[key].versions, component.Version)
+			}
+		}
+		return grouped
+	}
+
+	var (
+		diff            sbomDiff
+		previousVersion = group(previous)
+		currentVersion  = group(current)
+	)
+
+	for key, current := range currentVersion {
+		previous, existed := previousVersion[key]
+		switch {
+		case !existed:
+			for _, version := range current.versions {
+				diff.Added = append(diff.Added, sbomChange{Name: current.name, CurrentVersion: version})
+			}
+		case len(previous.versions) == 1 && len(current.versions) == 1:
+			if previous.versions[0] != current.versions[0] {
+				diff.Updated = append(diff.Updated, sbomChange{
+					Name:            current.name,
+					PreviousVersion: previous.versions[0],
+					CurrentVersion:  current.versions[0],
+				})
+			}
+		default:
+			for _, version := range current.versions {
+				if !slices.Contains(previous.versions, version) {
+					diff.Added = append(diff.Added, sbomChange{Name: current.name, CurrentVersion: version})
+				}
+			}
+			for _, version := range previous.versions {
+				if !slices.Contains(current.versions, version) {
+					diff.Removed = append(diff.Removed, sbomChange{Name: previous.name, PreviousVersion: version})
+				}
+			}
+		}
+	}
+	for key, previous := range previousVersion {
+		if _, exists := currentVersion[key]; !exists {
+			for _, version := range previous.versions {
+				diff.Removed = append(diff.Removed, sbomChange{Name: previous.name, PreviousVersion: version})
+			}
+		}
+	}
+
+	// Order the changes by name, then version, as map iteration order is
+	// random.
+	compareName := func(a, b sbomChange) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)),
+			cmp.Compare(a.PreviousVersion, b.PreviousVersion),
+			cmp.Compare(a.CurrentVersion, b.CurrentVersion),
+		)
+	}
+	slices.SortFunc(diff.Added, compareName)
+	slices.SortFunc(diff.Removed, compareName)
+	slices.SortFunc(diff.Updated, compareName)
+
+	return diff
+}
@@ -0,0 +1,175 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+var (
+	// reCVE matches a CVE identifier, i.e - `CVE-2024-12345`.
+	reCVE = regexp.MustCompile(`CVE-\d{4}-\d{4,}`)
+
+	// reDependencyTitle matches the titles of pull requests created by common
+	// dependency update tools, i.e - `chore(deps): bump x from 1.0 to 1.1`.
+	reDependencyTitle = regexp.MustCompile(`(?i)^((build|chore|fix)\(deps(-dev)?\)!?:|bump |update .* to )`)
+)
+
+// defaultDependencyLabels are the labels that identify dependency update pull
+// requests when none have been configured.
+var defaultDependencyLabels = []string{"dependencies"}
+
+// SecurityConfig determines the content of the security section of the release
+// notes.
+type SecurityConfig struct {
+	// Advisories includes the repository security advisories published since
+	// the latest reference in the release notes.
+	Advisories bool `yaml:"advisories"`
+
+	// DependencyCVEs includes the CVE identifiers mentioned in the bodies of
+	// dependency update pull requests.
+	DependencyCVEs bool `yaml:"dependencyCVEs"`
+
+	// DependencyLabels is the list of labels that identify dependency update
+	// pull requests, in addition to the titles used by common dependency update
+	// tools. Defaults to "dependencies".
+	DependencyLabels []string `yaml:"dependencyLabels"`
+}
+
+// securityNote represents a note of a security fix, read from a fragment file,
+// i.e - the `security` section of a reno note.
+type securityNote struct {
+	PullRequest gitPullRequest
+	Notes       string
+}
+
+// dependencyCVE represents a CVE identifier mentioned by a dependency update
+// pull request.
+type dependencyCVE struct {
+	CVEID       string
+	PullRequest gitPullRequest
+}
+
+type securityAdvisory struct {
+	GHSAID      string    `json:"ghsa_id"`
+	CVEID       string    `json:"cve_id"`
+	Summary     string    `json:"summary"`
+	Severity    string    `json:"severity"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// getSecurityAdvisories returns the repository security advisories published
+// after the provided time, and up to the provided time, unless it is zero,
+// ordered from oldest to newest.
+func getSecurityAdvisories(ctx context.Context, cmd commander, since, until time.Time) ([]securityAdvisory, error) {
+	// Get all published security advisories for the repository.
+	advisoriesJSON, err := cmd.runForge(ctx, "gh",
+		"api", "repos/{owner}/{repo}/security-advisories?state=published&sort=published&direction=asc",
+		"--paginate",
+		"--jq", ".[]",
+	)
+	if err != nil {
+		return nil, &SecurityAdvisoriesError{Err: err}
+	}
+
+	// Keep the advisories published between the provided times.
+	var advisories []securityAdvisory
+	for advisoryJSON := range strings.SplitSeq(strings.TrimSpace(advisoriesJSON), "\n") {
+		if advisoryJSON == "" {
+			continue
+		}
+
+		var advisory securityAdvisory
+		if err := json.Unmarshal([]byte(advisoryJSON), &advisory); err != nil {
+			return nil, &SecurityAdvisoriesError{Err: err}
+		}
+
+		if advisory.PublishedAt.After(since) && (until.IsZero() || !advisory.PublishedAt.After(until)) {
+			advisories = append(advisories, advisory)
+		}
+	}
+
+	return advisories, nil
+}
+
+// releasePublishedAt returns when the release of the provided tag was
+// published, if it has been, so that the advisories published after it are
+// left out of its release notes.
+func releasePublishedAt(ctx context.Context, o options, tagName string) (time.Time, bool) {
+	if tagName == "" {
+		return time.Time{}, false
+	}
+	releases, err := listReleases(ctx, o)
+	if err != nil {
+		log.Debug("Failed to list the releases", "err", err)
+		return time.Time{}, false
+	}
+	for _, release := range releases {
+		if release.TagName == tagName && !release.PublishedAt.IsZero() {
+			return release.PublishedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// cveURL returns the URL of the National Vulnerability Database entry for the
+// provided CVE identifier.
+func cveURL(cveID string) string {
+	return "https://nvd.nist.gov/vuln/detail/" + cveID
+}
+
+// isDependencyUpdate reports whether the pull request updates dependencies,
+// either by its labels or by its title.
+func (c SecurityConfig) isDependencyUpdate(pullRequest gitPullRequest) bool {
+	labels := c.DependencyLabels
+	if len(labels) == 0 {
+		labels = defaultDependencyLabels
+	}
+
+	return matchesAny(labels, pullRequest.hasLabel) || reDependencyTitle.MatchString(pullRequest.Title)
+}
+
+// getDependencyCVEs returns the CVE identifiers mentioned in the bodies of the
+// dependency update pull requests, excluding those already covered by the
+// provided security advisories.
+func getDependencyCVEs(
+	pullRequests []gitPullRequest,
+	advisories []securityAdvisory,
+	config SecurityConfig,
+) []dependencyCVE {
+	var (
+		cves []dependencyCVE
+		seen = map[string]bool{}
+	)
+
+	// CVEs with an advisory are already in the security section.
+	for _, advisory := range advisories {
+		seen[advisory.CVEID] = true
+	}
+
+	for _, pullRequest := range pullRequests {
+		if !config.isDependencyUpdate(pullRequest) {
+			continue
+		}
+
+		// Sort the CVEs in the body, so they are output in a stable order.
+		cveIDs := reCVE.FindAllString(pullRequest.Body, -1)
+		slices.Sort(cveIDs)
+
+		for _, cveID := range cveIDs {
+			if seen[cveID] {
+				continue
+			}
+			seen[cveID] = true
+			cves = append(cves, dependencyCVE{CVEID: cveID, PullRequest: pullRequest})
+		}
+	}
+
+	return cves
+}
@@ -0,0 +1,30 @@
+package lorekeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReleasePublishedAt(t *testing.T) {
+	o := options{provider: listingProvider{
+		releases: `[{"publishedAt":"2026-01-04T00:00:00Z","tagName":"v1.1.0"},` +
+			`{"publishedAt":"2026-01-02T00:00:00Z","tagName":"v1.0.0"}]` + "\n",
+	}}
+	tests := []struct {
+		tagName string
+		want    time.Time
+		wantOK  bool
+	}{
+		{tagName: "v1.0.0", want: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), wantOK: true},
+		{tagName: "v1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tagName, func(t *testing.T) {
+			got, ok := releasePublishedAt(context.Background(), o, tt.tagName)
+			if !got.Equal(tt.want) || ok != tt.wantOK {
+				t.Errorf("releasePublishedAt() = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SignMethod is the method used to sign the rendered release notes.
+type SignMethod string
+
+const (
+	// SignGPG signs the release notes with an ASCII armored, detached GPG
+	// signature, written alongside them with a `.asc` extension.
+	SignGPG SignMethod = "gpg"
+
+	// SignSSH signs the release notes with a detached SSH signature (in the
+	// "file" namespace), written alongside them with a `.sig` extension.
+	SignSSH SignMethod = "ssh"
+)
+
+// SignConfig determines whether, and how, the rendered release notes are
+// signed, so that consumers can verify that they came from the release
+// pipeline.
+//
+// When a method is set, the release notes uploaded as assets of the release
+// have their signatures uploaded alongside them.
+type SignConfig struct {
+	// Method is the signing method, either "gpg" or "ssh". Signing is disabled
+	// if it is not set.
+	Method SignMethod `yaml:"method"`
+
+	// Key is the GPG key ID to sign with (defaults to the default GPG key), or
+	// the path to the SSH private key to sign with (required).
+	Key string `yaml:"key"`
+
+	// Output is the path that the rendered release notes are written to, with
+	// their signature written alongside them. The release notes are still
+	// output as normal.
+	Output string `yaml:"output"`
+}
+
+// extension returns the file extension of the signatures made by the method.
+func (m SignMethod) extension() string {
+	if m == SignSSH {
+		return ".sig"
+	}
+	return ".asc"
+}
+
+// validate checks that the signing configuration is usable.
+func (c SignConfig) validate() error {
+	switch c.Method {
+	case "", SignGPG:
+	case SignSSH:
+		if c.Key == "" {
+			return &SignError{Err: errors.New("the ssh method requires a key")}
+		}
+	default:
+		return &SignMethodInvalidError{Method: c.Method}
+	}
+	return nil
+}
+
+// signFile writes a detached signature of the file at the provided path
+// alongside it, returning the path of the signature.
+func signFile(ctx context.Context, cmd commander, path string, config SignConfig) (string, error) {
+	signaturePath := path + config.Method.extension()
+
+	// Remove any existing signature, as it would otherwise be refused or
+	// prompted for.
+	if err := os.Remove(signaturePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", &SignError{Path: path, Err: err}
+	}
+
+	var (
+		name string
+		args []string
+	)
+	switch config.Method {
+	case SignGPG:
+		name, args = "gpg", []string{"--batch", "--armor", "--detach-sign"}
+		if config.Key != "" {
+			args = append(args, "--local-user", config.Key)
+		}
+		args = append(args, "--output", signaturePath, path)
+	case SignSSH:
+		name, args = "ssh-keygen", []string{"-Y", "sign", "-n", "file", "-f", expandHome(config.Key), path}
+	default:
+		return "", &SignMethodInvalidError{Method: config.Method}
+	}
+
+	if _, err := cmd.run(ctx, name, args...); err != nil {
+		return "", &SignError{Path: path, Err: err}
+	}
+
+	return signaturePath, nil
+}
+
+// writeSignedReleaseNotes writes the rendered release notes to the configured
+// output path, alongside their detached signature.
+func writeSignedReleaseNotes(ctx context.Context, cmd commander, rendered []byte, config SignConfig) error {
+	if err := os.MkdirAll(filepath.Dir(config.Output), 0o755); err != nil {
+		return &SignError{Path: config.Output, Err: err}
+	}
+	if err := os.WriteFile(config.Output, rendered, 0o644); err != nil {
+		return &SignError{Path: config.Output, Err: err}
+	}
+
+	_, err := signFile(ctx, cmd, config.Output, config)
+	return err
+}
+
+// expandHome replaces a leading `~` in the provided path with the home
+// directory of the user, as a shell would, since the commands are run without
+// one.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && !os.IsPathSeparator(rest[0])) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
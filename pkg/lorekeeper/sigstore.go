@@ -0,0 +1,56 @@
+package lorekeeper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// releaseNotesPredicateType is the in-toto predicate type of release notes
+// attestations, whose predicate is the structured JSON release notes.
+const releaseNotesPredicateType = "https://github.com/riftspire/lorekeeper/release-notes/v1"
+
+// SigstoreConfig determines whether the release notes uploaded as assets of the
+// release are signed and attested with Sigstore.
+type SigstoreConfig struct {
+	// Enabled signs and attests the uploaded release notes keylessly with
+	// `cosign`, using the ambient OIDC identity of the CI provider, and uploads
+	// each Sigstore bundle alongside them as `<asset>.sigstore.json`.
+	Enabled bool `yaml:"enabled"`
+}
+
+// attestFile signs and attests the file at the provided path with cosign, with
+// the structured release notes as the predicate, returning the path of the
+// Sigstore bundle.
+func attestFile(ctx context.Context, cmd commander, path string, notes releaseNotes, config Config) (string, error) {
+	// Write the predicate alongside the file.
+	document, err := newRenderedJSONReleaseNotes(notes, config)
+	if err != nil {
+		return "", err
+	}
+	predicate, err := json.Marshal(document)
+	if err != nil {
+		return "", &SigstoreError{Path: path, Err: err}
+	}
+
+	predicatePath := filepath.Join(filepath.Dir(path), "predicate.json")
+	if err := os.WriteFile(predicatePath, predicate, 0o644); err != nil {
+		return "", &SigstoreError{Path: path, Err: err}
+	}
+	defer os.Remove(predicatePath)
+
+	// Sign and attest the file keylessly.
+	bundlePath := path + ".sigstore.json"
+	if _, err := cmd.run(ctx, "cosign",
+		"attest-blob", "--yes",
+		"--type", releaseNotesPredicateType,
+		"--predicate", predicatePath,
+		"--bundle", bundlePath,
+		path,
+	); err != nil {
+		return "", &SigstoreError{Path: path, Err: err}
+	}
+
+	return bundlePath, nil
+}
@@ -0,0 +1,106 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SiteConfig determines whether, and where, a static-site fragment of the
+// release notes is written.
+type SiteConfig struct {
+	// Dir is the directory that the fragment is written to, as
+	// `<Dir>/<tag>.md`. If empty, no fragment is written.
+	Dir string `yaml:"dir"`
+
+	// Tags is the list of tags added to the front matter of the fragment.
+	// Release candidates are also tagged "release-candidate".
+	Tags []string `yaml:"tags"`
+}
+
+// siteFrontMatter represents the YAML front matter of a static-site fragment,
+// as understood by Hugo and Jekyll.
+type siteFrontMatter struct {
+	Title   string    `yaml:"title"`
+	Date    time.Time `yaml:"date"`
+	Version string    `yaml:"version"`
+	Tags    []string  `yaml:"tags,omitempty"`
+}
+
+// versionFromTag returns the version of the provided tag, without any `v`
+// prefix or `refs/tags/` prefix.
+func versionFromTag(tagName string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(tagName, "refs/tags/"), "v")
+}
+
+// writeSiteFragment writes the release notes as a markdown file with YAML front
+// matter to the configured directory, returning the path of the file.
+func writeSiteFragment(notes releaseNotes, config Config) (string, error) {
+	frontMatter := siteFrontMatter{
+		Title:   fmt.Sprintf("Release %s", notes.TagName),
+		Date:    notes.Date,
+		Version: versionFromTag(notes.TagName),
+		Tags:    config.Site.Tags,
+	}
+	if notes.ReleaseCandidate {
+		frontMatter.Tags = append(frontMatter.Tags, "release-candidate")
+	}
+
+	// Output the front matter, followed by the release notes.
+	var fragment bytes.Buffer
+	fragment.WriteString("---\n")
+	encoder := yaml.NewEncoder(&fragment)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(frontMatter); err != nil {
+		return "", err
+	}
+	fragment.WriteString("---\n\n")
+	if err := renderLintedMarkdown(&fragment, notes, config); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(config.Site.Dir, notes.TagName+".md")
+	if err := os.MkdirAll(config.Site.Dir, 0o755); err != nil {
+		return "", &SiteError{Path: path, Err: err}
+	}
+	if err := os.WriteFile(path, fragment.Bytes(), 0o644); err != nil {
+		return "", &SiteError{Path: path, Err: err}
+	}
+
+	return path, nil
+}
+
+// getTagDate returns the date that the provided tag was created, or the build
+// time if it can't be determined.
+func getTagDate(ctx context.Context, cmd commander, tagName string) time.Time {
+	output, err := cmd.run(ctx, "git",
+		"for-each-ref", "refs/tags/"+strings.TrimPrefix(tagName, "refs/tags/"),
+		"--format=%(creatordate:iso-strict)",
+	)
+	if err != nil {
+		return buildTime()
+	}
+
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(output))
+	if err != nil {
+		return buildTime()
+	}
+
+	return date
+}
+
+// buildTime returns the time set by the `SOURCE_DATE_EPOCH` environment
+// variable, for reproducible builds, or the current time if it isn't set.
+func buildTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0)
+	}
+	return time.Now()
+}
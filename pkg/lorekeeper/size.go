@@ -0,0 +1,116 @@
+package lorekeeper
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// defaultSizeThresholds are the most lines changed by an entry of each size,
+// XS to L, when no thresholds have been configured.
+var defaultSizeThresholds = []int{10, 50, 250, 1000}
+
+// sizes are the sizes of the entries, smallest first.
+var sizes = []string{"XS", "S", "M", "L", "XL"}
+
+// maxAreas is the most changed areas that an entry is annotated with.
+const maxAreas = 3
+
+// rootArea is the area of the files at the root of the repository.
+const rootArea = "root"
+
+// SizeConfig determines how the entries are annotated with the weight of
+// their change.
+type SizeConfig struct {
+	// Enabled annotates each entry with a size: XS, S, M, L, or XL, by the
+	// lines changed.
+	Enabled bool `yaml:"enabled"`
+
+	// Thresholds are the most lines changed by an entry of each size, XS to
+	// L, beyond which entries are XL. Defaults to 10, 50, 250, and 1000.
+	Thresholds []int `yaml:"thresholds"`
+
+	// Areas annotates each entry with the top-level directories it changes
+	// the most.
+	Areas bool `yaml:"areas"`
+}
+
+// validate checks the thresholds, which must be four ascending, positive
+// numbers of lines.
+func (c SizeConfig) validate() error {
+	if c.Thresholds == nil {
+		return nil
+	}
+	if len(c.Thresholds) != len(sizes)-1 || c.Thresholds[0] < 1 || !slices.IsSorted(c.Thresholds) || len(slices.Compact(slices.Clone(c.Thresholds))) != len(c.Thresholds) {
+		return &SizeThresholdsInvalidError{Thresholds: c.Thresholds}
+	}
+	return nil
+}
+
+// linesChanged returns the number of lines added and deleted by the pull
+// request.
+func (pr gitPullRequest) linesChanged() (int, int) {
+	var additions, deletions int
+	for _, file := range pr.Files {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	return additions, deletions
+}
+
+// size returns the size of the pull request, by the lines it changes.
+func (c SizeConfig) size(pullRequest gitPullRequest) string {
+	thresholds := c.Thresholds
+	if thresholds == nil {
+		thresholds = defaultSizeThresholds
+	}
+
+	additions, deletions := pullRequest.linesChanged()
+	for i, threshold := range thresholds {
+		if additions+deletions <= threshold {
+			return sizes[i]
+		}
+	}
+	return sizes[len(sizes)-1]
+}
+
+// areas returns the top-level directories that the pull request changes the
+// most lines of, up to maxAreas, most changed first.
+func areas(pullRequest gitPullRequest) []string {
+	changed := map[string]int{}
+	for _, file := range pullRequest.Files {
+		area, _, ok := strings.Cut(file.Path, "/")
+		if !ok {
+			area = rootArea
+		}
+		changed[area] += file.Additions + file.Deletions
+	}
+
+	names := slices.Collect(maps.Keys(changed))
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(changed[b], changed[a]), strings.Compare(a, b))
+	})
+	if len(names) > maxAreas {
+		names = names[:maxAreas]
+	}
+	return names
+}
+
+// annotation returns the annotation of the pull request with the weight of
+// its change, i.e - "Size: M (+120 −30) · Areas: cmd, pkg", or an empty string
+// if it isn't annotated.
+func (c SizeConfig) annotation(pullRequest gitPullRequest) string {
+	var parts []string
+	if c.Enabled {
+		additions, deletions := pullRequest.linesChanged()
+		parts = append(parts, fmt.Sprintf("Size: %s (+%d −%d)", c.size(pullRequest), additions, deletions))
+	}
+	if c.Areas {
+		if changed := areas(pullRequest); len(changed) > 0 {
+			parts = append(parts, "Areas: "+strings.Join(changed, ", "))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
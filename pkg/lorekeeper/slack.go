@@ -0,0 +1,144 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// slackMaxTextLength is the maximum length of the text of a Slack section
+	// block.
+	slackMaxTextLength = 3000
+
+	// slackMaxBlocks is the maximum number of blocks in a Slack message.
+	slackMaxBlocks = 50
+)
+
+var (
+	// reMarkdownImage matches a markdown image, capturing its alt text.
+	reMarkdownImage = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+
+	// reMarkdownLink matches a markdown link, capturing its text and URL.
+	reMarkdownLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+	// reMarkdownBold matches bold markdown text, capturing the text.
+	reMarkdownBold = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+
+	// reMarkdownHeading matches a markdown heading, capturing its text.
+	reMarkdownHeading = regexp.MustCompile(`(?m)^#{1,6}\s+(.*)$`)
+)
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// NotifySlack posts the provided markdown release notes for the tag to each of
+// the Slack incoming webhook URLs, formatted as Block Kit blocks.
+func NotifySlack(ctx context.Context, webhookURLs []string, tagName string, markdown string, opts ...Option) error {
+	o := newOptions(opts)
+	return notifySlack(ctx, o.cmd, webhookURLs, tagName, markdown)
+}
+
+// notifySlack posts the release notes as described by NotifySlack, with the
+// provided commander.
+func notifySlack(ctx context.Context, cmd commander, webhookURLs []string, tagName string, markdown string) error {
+	message := slackMessage{
+		Text: fmt.Sprintf("Release %s", tagName),
+		Blocks: []slackBlock{{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: fmt.Sprintf("Release %s", tagName), Emoji: true},
+		}},
+	}
+
+	// Add the release notes as section blocks, up to the maximum number of
+	// blocks.
+	chunks := chunkText(toSlackMrkdwn(markdown), slackMaxTextLength)
+	for idx, chunk := range chunks {
+		if len(message.Blocks) == slackMaxBlocks-1 && idx < len(chunks)-1 {
+			message.Blocks = append(message.Blocks, slackBlock{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: "_The release notes are too long to post in full._"},
+			})
+			break
+		}
+		message.Blocks = append(message.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: chunk},
+		})
+	}
+
+	for _, webhookURL := range webhookURLs {
+		if err := postJSON(ctx, cmd, webhookURL, message, nil); err != nil {
+			return &NotifyError{Destination: "slack", Err: err}
+		}
+	}
+
+	return nil
+}
+
+// toSlackMrkdwn converts the provided markdown to Slack's mrkdwn format.
+func toSlackMrkdwn(markdown string) string {
+	mrkdwn := reMarkdownImage.ReplaceAllString(markdown, "$1")
+	mrkdwn = reMarkdownLink.ReplaceAllString(mrkdwn, "<$2|$1>")
+	mrkdwn = reMarkdownBold.ReplaceAllString(mrkdwn, "*$1*")
+	mrkdwn = reMarkdownHeading.ReplaceAllString(mrkdwn, "*$1*")
+	return strings.TrimSpace(mrkdwn)
+}
+
+// chunkText splits the provided text into chunks no longer than the maximum
+// length, splitting at line boundaries where possible.
+func chunkText(text string, maxLength int) []string {
+	var (
+		chunks  []string
+		current strings.Builder
+	)
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+	}
+
+	for line := range strings.SplitSeq(text, "\n") {
+		// Split lines that are too long on their own.
+		for len(line) > maxLength {
+			flush()
+			cut := maxLength
+			for cut > 0 && !utf8RuneStart(line[cut]) {
+				cut--
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+		}
+
+		if current.Len()+len(line)+1 > maxLength {
+			flush()
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	flush()
+
+	return chunks
+}
+
+// utf8RuneStart reports whether the byte is the first byte of a UTF-8 encoded
+// rune, so text is never split mid-rune.
+func utf8RuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
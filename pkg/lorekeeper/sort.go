@@ -0,0 +1,99 @@
+package lorekeeper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SortKey is the field that the release note entries are ordered by.
+type SortKey string
+
+const (
+	// SortByMerged orders entries by the date they were merged.
+	SortByMerged SortKey = "merged"
+
+	// SortByNumber orders entries by their pull request number.
+	SortByNumber SortKey = "number"
+
+	// SortByTitle orders entries alphabetically by their title.
+	SortByTitle SortKey = "title"
+
+	// SortByCategory orders entries by the weight of their category, then by
+	// the date they were merged.
+	SortByCategory SortKey = "category"
+)
+
+// SortConfig determines the order of the release note entries.
+type SortConfig struct {
+	// By is the field to order the entries by. Defaults to "merged".
+	By SortKey `yaml:"by"`
+
+	// Descending reverses the order of the entries.
+	Descending bool `yaml:"descending"`
+}
+
+// sortPullRequests orders the provided pull requests in place, as determined by
+// the provided SortConfig.
+func sortPullRequests(pullRequests []gitPullRequest, config SortConfig) error {
+	var compare func(a, b gitPullRequest) int
+
+	switch config.By {
+	case SortByMerged, "":
+		compare = compareMerged
+	case SortByNumber:
+		compare = func(a, b gitPullRequest) int {
+			return cmp.Compare(a.Number, b.Number)
+		}
+	case SortByTitle:
+		compare = func(a, b gitPullRequest) int {
+			return cmp.Or(
+				strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)),
+				cmp.Compare(a.Number, b.Number),
+			)
+		}
+	case SortByCategory:
+		compare = func(a, b gitPullRequest) int {
+			return cmp.Or(
+				cmp.Compare(a.category.Weight, b.category.Weight),
+				compareMerged(a, b),
+			)
+		}
+	default:
+		return &SortKeyInvalidError{Key: config.By}
+	}
+
+	// Reverse the comparison for a descending order.
+	if config.Descending {
+		ascending := compare
+		compare = func(a, b gitPullRequest) int {
+			return ascending(b, a)
+		}
+	}
+
+	slices.SortStableFunc(pullRequests, compare)
+
+	return nil
+}
+
+// compareMerged compares two pull requests by the date they were merged, then
+// by their number.
+func compareMerged(a, b gitPullRequest) int {
+	return cmp.Or(
+		a.MergedAt.Compare(b.MergedAt),
+		cmp.Compare(a.Number, b.Number),
+	)
+}
+
+// sortedByCategory returns a copy of the provided pull requests, stably ordered
+// by the weight, then the title, of their category.
+func sortedByCategory(pullRequests []gitPullRequest) []gitPullRequest {
+	sorted := slices.Clone(pullRequests)
+	slices.SortStableFunc(sorted, func(a, b gitPullRequest) int {
+		return cmp.Or(
+			cmp.Compare(a.category.Weight, b.category.Weight),
+			strings.Compare(a.category.Title, b.category.Title),
+		)
+	})
+	return sorted
+}
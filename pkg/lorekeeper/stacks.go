@@ -0,0 +1,174 @@
+package lorekeeper
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+var (
+	// reStackMarker matches the word "stack" in the body of a pull request,
+	// i.e - the `Stack from ghstack` or `**Stack**:` markers of stacking tools,
+	// which precede a list of the pull requests of the stack.
+	reStackMarker = regexp.MustCompile(`(?i)\bstack\b`)
+
+	// reStackListItem matches an item of the list of the pull requests of a
+	// stack, capturing its number, i.e - `* __->__ #123` (ghstack), `- #123 ⬅`
+	// (spr), or `* **#123** 👈` (Graphite).
+	reStackListItem = regexp.MustCompile(`(?m)^\s*(?:[-*+]|[0-9]+\.)\s+(?:__->__\s+)?(?:\*\*)?#([0-9]+)\b`)
+)
+
+// StacksConfig determines whether stacked pull requests, each a layer built on
+// top of the one before, are nested beneath the entry of the bottom of their
+// stack, rather than listed as unrelated changes.
+type StacksConfig struct {
+	// Enabled detects the stacks from the markers that stacking tools add to
+	// the bodies of the pull requests, i.e - a `Stack` list of the pull
+	// requests, and from pull requests based on the branch of another.
+	Enabled bool `yaml:"enabled"`
+
+	// Branch is a regular expression matching the head branches of stacked
+	// pull requests, whose first capture group names their stack, i.e -
+	// `^stack/([^/]+)/` for `stack/<name>/<layer>`.
+	Branch string `yaml:"branch"`
+}
+
+// validate checks the branch pattern of the stacks, if any.
+func (c StacksConfig) validate() error {
+	_, err := c.branchPattern()
+	return err
+}
+
+// branchPattern compiles the branch pattern of the stacks, returning nil if
+// there is none, or a StackBranchInvalidError if it is invalid.
+func (c StacksConfig) branchPattern() (*regexp.Regexp, error) {
+	if c.Branch == "" {
+		return nil, nil
+	}
+	reBranch, err := regexp.Compile(c.Branch)
+	if err != nil {
+		return nil, &StackBranchInvalidError{Pattern: c.Branch, Err: err}
+	}
+	if reBranch.NumSubexp() < 1 {
+		return nil, &StackBranchInvalidError{Pattern: c.Branch, Reason: "the pattern has no capture group"}
+	}
+	return reBranch, nil
+}
+
+// stackNumbers returns the numbers of the pull requests in the stack list of
+// the body of the provided pull request, if it has one.
+func stackNumbers(body string) []int {
+	if !reStackMarker.MatchString(body) {
+		return nil
+	}
+
+	var numbers []int
+	for _, matches := range reStackListItem.FindAllStringSubmatch(body, -1) {
+		if number, err := strconv.Atoi(matches[1]); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// nestStacks nests the pull requests of each stack found amongst the provided
+// pull requests beneath the bottom of the stack, the one opened first, in the
+// order the layers were opened. The pull requests that aren't stacked are
+// returned as they are.
+func nestStacks(pullRequests []gitPullRequest, config StacksConfig, defaultBranchName string) ([]gitPullRequest, error) {
+	if !config.Enabled {
+		return pullRequests, nil
+	}
+
+	// Compile the branch pattern, if any.
+	reBranch, err := config.branchPattern()
+	if err != nil {
+		return nil, err
+	}
+
+	// Join the pull requests of the same stack into sets.
+	var (
+		parents = make([]int, len(pullRequests))
+		index   = map[int]int{}
+		heads   = map[string]int{}
+	)
+	for idx, pullRequest := range pullRequests {
+		parents[idx] = idx
+		if pullRequest.Number != 0 {
+			index[pullRequest.Number] = idx
+		}
+		if pullRequest.HeadRefName != "" {
+			heads[pullRequest.HeadRefName] = idx
+		}
+	}
+	var find func(int) int
+	find = func(idx int) int {
+		if parents[idx] != idx {
+			parents[idx] = find(parents[idx])
+		}
+		return parents[idx]
+	}
+	join := func(a, b int) {
+		parents[find(a)] = find(b)
+	}
+
+	stacks := map[string]int{}
+
+	for idx, pullRequest := range pullRequests {
+		// Join the pull requests listed in the stack of the body.
+		if numbers := stackNumbers(pullRequest.Body); slices.Contains(numbers, pullRequest.Number) {
+			for _, number := range numbers {
+				if other, ok := index[number]; ok {
+					join(idx, other)
+				}
+			}
+		}
+
+		// Join the pull request based on the branch of another, unless the
+		// branch is the default branch of a fork.
+		other, ok := heads[pullRequest.BaseRefName]
+		if ok && pullRequest.BaseRefName != defaultBranchName && pullRequests[other].BaseRefName != pullRequests[other].HeadRefName {
+			join(idx, other)
+		}
+
+		// Join the pull requests whose branches name the same stack.
+		if reBranch != nil {
+			if matches := reBranch.FindStringSubmatch(pullRequest.HeadRefName); matches != nil && matches[1] != "" {
+				if other, ok := stacks[matches[1]]; ok {
+					join(idx, other)
+				} else {
+					stacks[matches[1]] = idx
+				}
+			}
+		}
+	}
+
+	// Gather the layers of each stack, in the order they were opened.
+	layers := map[int][]int{}
+	for idx := range pullRequests {
+		layers[find(idx)] = append(layers[find(idx)], idx)
+	}
+	bottom := map[int]int{}
+	for root, members := range layers {
+		slices.SortFunc(members, func(a, b int) int {
+			return cmp.Compare(pullRequests[a].Number, pullRequests[b].Number)
+		})
+		bottom[root] = members[0]
+	}
+
+	// Nest the layers beneath the bottom of their stack, keeping the order of
+	// the pull requests.
+	var nested []gitPullRequest
+	for idx, pullRequest := range pullRequests {
+		root := find(idx)
+		if bottom[root] != idx {
+			continue
+		}
+		for _, layer := range layers[root][1:] {
+			pullRequest.stacked = append(pullRequest.stacked, pullRequests[layer])
+		}
+		nested = append(nested, pullRequest)
+	}
+	return nested, nil
+}
@@ -0,0 +1,158 @@
+package lorekeeper
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Strategy is how the pull requests merged since the previous release are
+// found.
+type Strategy string
+
+const (
+	// StrategyMergeBase finds the pull requests merged by the commits between
+	// the merge-base of the previous and new tags, and the new tag, in the
+	// commit graph of the local repository. It is the default, which falls
+	// back to StrategyTimestamp when the commit graph can't be walked, unless
+	// it is configured explicitly.
+	StrategyMergeBase Strategy = "merge-base"
+
+	// StrategyTimestamp finds the pull requests merged after the previous
+	// release was published, with the forge. It misses pull requests merged
+	// before the previous release was published but not included in it, and
+	// may double count those merged while it was being published.
+	StrategyTimestamp Strategy = "timestamp"
+)
+
+// GetStrategies returns the strategies for finding the pull requests merged
+// since the previous release.
+func GetStrategies() []Strategy {
+	return []Strategy{StrategyMergeBase, StrategyTimestamp}
+}
+
+// validate checks the strategy.
+func (s Strategy) validate() error {
+	if s != "" && !slices.Contains(GetStrategies(), s) {
+		return &StrategyInvalidError{Strategy: s}
+	}
+	return nil
+}
+
+// pullRequestsSince lists the pull requests merged since the provided previous
+// reference, up to the tag, with the configured strategy.
+//
+// The merge-base strategy needs the local repository, so the timestamp
+// strategy is used in the API-only mode, or if the tags aren't in the
+// commit graph, i.e - the previous release has no tag. When the merge-base
+// strategy fails, i.e - with a CommitLookupsExceededError, the timestamp
+// strategy is used with a warning by default, and the error is returned if the
+// merge-base strategy was configured explicitly.
+func pullRequestsSince(ctx context.Context, o options, previousRef gitReference, tagName string, location *time.Location) (string, error) {
+	if err := o.config.Strategy.validate(); err != nil {
+		return "", err
+	}
+
+	if o.config.Strategy != StrategyTimestamp && !o.apiOnly && previousRef.TagName != tagName {
+		numbers, err := mergeBasePullRequests(ctx, o, previousRef.TagName, tagName)
+		if err == nil {
+			return numbers, nil
+		}
+		if o.config.Strategy == StrategyMergeBase {
+			return "", err
+		}
+		log.Warn("Falling back to the merge dates to find the pull requests", "previous", previousRef.TagName, "tag", tagName, "err", err)
+	}
+
+	numbers, err := o.provider.MergedPullRequests(ctx, previousRef.PublishedAt.In(location).Format(time.RFC3339))
+	return listedNumbers(numbers), err
+}
+
+// maxCommitLookups is the most commits without a pull request in their subject,
+// i.e - rebase merges, whose pull requests are asked of the forge by the
+// merge-base strategy, before falling back to the merge dates.
+const maxCommitLookups = 500
+
+// mergeBasePullRequests lists the pull requests merged by the first-parent
+// commits between the merge-base of the previous and new tags, and the new
+// tag, newest first. Every commit reachable from the new tag is included if
+// there is no previous tag.
+//
+// The pull request of a merge, or squash merge, commit is read from its
+// subject, and that of any other commit, i.e - a rebase merge, is asked of
+// the forge, in parallel. It returns a CommitLookupsExceededError if there are
+// more than maxCommitLookups of them.
+func mergeBasePullRequests(ctx context.Context, o options, previousTagName string, tagName string) (string, error) {
+	// Find the point that the new tag diverged from the previous tag.
+	revisions := tagName
+	if previousTagName != "" {
+		base, err := o.cmd.run(ctx, "git", "merge-base", previousTagName, tagName)
+		if err != nil {
+			return "", err
+		}
+		revisions = strings.TrimSpace(base) + ".." + tagName
+	}
+
+	// `git log` returns commits in reverse chronological order (newest to
+	// oldest), as `gh pr list` does.
+	commits, err := o.cmd.run(ctx, "git", "log", "--first-parent", "--format=%H%x1f%s", revisions)
+	if err != nil {
+		return "", err
+	}
+
+	// Read the pull request of each merge, or squash merge, commit from its
+	// subject, and ask the forge for those of the other commits, i.e - rebase
+	// merges, unless there are too many to ask for.
+	type logCommit struct {
+		sha     string
+		numbers string
+	}
+	var (
+		logCommits []logCommit
+		lookups    []int
+	)
+	for commit := range strings.SplitSeq(strings.TrimSpace(commits), "\n") {
+		sha, subject, ok := strings.Cut(commit, "\x1f")
+		if !ok {
+			continue
+		}
+		number, ok := pullRequestNumber(subject)
+		if !ok {
+			lookups = append(lookups, len(logCommits))
+		}
+		logCommits = append(logCommits, logCommit{sha: sha, numbers: number})
+	}
+	if len(lookups) > maxCommitLookups {
+		return "", &CommitLookupsExceededError{Commits: len(lookups), Max: maxCommitLookups}
+	}
+
+	// Ask the forge in parallel, up to the configured concurrency.
+	lookup := func(ctx context.Context, i int) (string, error) {
+		return o.provider.CommitPullRequests(ctx, logCommits[lookups[i]].sha)
+	}
+	for i, result := range fetchOrdered(ctx, o.concurrency, len(lookups), lookup, func(int) {}) {
+		if result.err != nil {
+			return "", result.err
+		}
+		logCommits[lookups[i]].numbers = result.value
+	}
+
+	// List each pull request once, in the order of the commits.
+	var (
+		numbers []string
+		seen    = map[string]bool{}
+	)
+	for _, commit := range logCommits {
+		for number := range strings.SplitSeq(commit.numbers, "\n") {
+			if number = strings.TrimSpace(number); number != "" && !seen[number] {
+				seen[number] = true
+				numbers = append(numbers, number)
+			}
+		}
+	}
+
+	return strings.Join(numbers, "\n"), nil
+}
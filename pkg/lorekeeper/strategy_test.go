@@ -0,0 +1,92 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// gitLogRunner is a Runner that outputs the provided first-parent history, and
+// the merge-base `base`.
+type gitLogRunner struct {
+	log string
+}
+
+func (r gitLogRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	if len(args) > 0 && args[0] == "merge-base" {
+		return "base\n", nil
+	}
+	return r.log, nil
+}
+
+// commitProvider is a Provider that returns the pull requests of the commits
+// from the provided map, counting the lookups.
+type commitProvider struct {
+	Provider
+	pullRequests map[string]string
+	mu           *sync.Mutex
+	lookups      *int
+}
+
+func (p commitProvider) CommitPullRequests(ctx context.Context, sha string) (string, error) {
+	p.mu.Lock()
+	*p.lookups++
+	p.mu.Unlock()
+	return p.pullRequests[sha], nil
+}
+
+func TestMergeBasePullRequests(t *testing.T) {
+	log := strings.Join([]string{
+		"c5\x1fMerge pull request #5 from owner/branch",
+		"c4\x1fRebased change",
+		"c3\x1fAdd a flag (#3)",
+		"c2\x1fAnother rebased change",
+		"c1\x1fThe rebased change again",
+	}, "\n")
+	var lookups int
+	o := options{
+		cmd:         commander{runner: gitLogRunner{log: log}},
+		concurrency: 2,
+		provider: commitProvider{
+			pullRequests: map[string]string{"c4": "4", "c2": "2\n4", "c1": "4"},
+			mu:           &sync.Mutex{},
+			lookups:      &lookups,
+		},
+	}
+
+	got, err := mergeBasePullRequests(context.Background(), o, "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatalf("mergeBasePullRequests() error = %v", err)
+	}
+	if want := "5\n4\n3\n2"; got != want {
+		t.Errorf("mergeBasePullRequests() = %q, want %q", got, want)
+	}
+	if lookups != 3 {
+		t.Errorf("mergeBasePullRequests() asked the forge %d times, want 3", lookups)
+	}
+}
+
+func TestMergeBasePullRequestsExceeded(t *testing.T) {
+	var commits []string
+	for i := range maxCommitLookups + 1 {
+		commits = append(commits, fmt.Sprintf("c%d\x1fRebased change %d", i, i))
+	}
+	var lookups int
+	o := options{
+		cmd:         commander{runner: gitLogRunner{log: strings.Join(commits, "\n")}},
+		concurrency: 2,
+		provider:    commitProvider{mu: &sync.Mutex{}, lookups: &lookups},
+	}
+
+	_, err := mergeBasePullRequests(context.Background(), o, "v1.0.0", "v1.1.0")
+	var exceeded *CommitLookupsExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("mergeBasePullRequests() error = %v, want *CommitLookupsExceededError", err)
+	}
+	if lookups != 0 {
+		t.Errorf("mergeBasePullRequests() asked the forge %d times, want none", lookups)
+	}
+}
@@ -0,0 +1,205 @@
+package lorekeeper
+
+import (
+	"context"
+	"io"
+
+	"github.com/charmbracelet/log"
+)
+
+// flusher is implemented by writers that buffer their output, i.e - a
+// bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// streamIncompatible returns the configured features that can't be combined
+// with streaming, as they need every entry before anything is output, or the
+// whole of the rendered release notes.
+func (c Config) streamIncompatible() []string {
+	var features []string
+	add := func(enabled bool, feature string) {
+		if enabled {
+			features = append(features, feature)
+		}
+	}
+
+	add(c.Format != "" && c.Format != FormatMarkdown, "format")
+	add(c.Template != "", "template")
+	add(len(c.Categories) > 0, "categories")
+	add(c.GenerateNotes.Enabled, "generateNotes")
+	add(c.GroupByScope, "groupByScope")
+	add(c.Stacks.Enabled, "stacks")
+	add(c.Fixups.enabled(), "fixups")
+	add(c.Thanks.Enabled, "thanks")
+	add(c.Header != BlockConfig{}, "header")
+	add(c.Footer != BlockConfig{}, "footer")
+	add(c.Install != InstallConfig{}, "install")
+	add(c.Sort.By != "", "sort")
+	add(c.Pagination.Mode != "", "pagination")
+	add(c.Lint.Enabled, "lint")
+	add(len(c.Vendored) > 0, "vendored")
+	add(c.Security.DependencyCVEs, "security.dependencyCVEs")
+	add(c.Publish.Enabled, "publish")
+	add(c.GoReleaser.Enabled, "goreleaser")
+	add(c.Fragments.Format != "", "fragments")
+	add(c.Sign.Output != "", "sign.output")
+	add(c.Site.Dir != "", "site")
+	add(c.Docs.Dir != "", "docs")
+	add(len(c.Notify.Slack)+len(c.Notify.Discord)+len(c.Notify.Teams)+len(c.Notify.Webhooks) > 0, "notify")
+	add(c.Diff, "diff")
+	add(c.Check, "check")
+	return features
+}
+
+// streamReleaseNotes outputs the release notes described by MakeReleaseNotes
+// as markdown, rendering and flushing each entry as soon as its pull request
+// is fetched, so that only one pull request is held in memory at a time.
+//
+// The entries are output in the order the pull requests are listed, without
+// being sorted or grouped, followed by their upgrade notes.
+func streamReleaseNotes(ctx context.Context, tagName string, o options) error {
+	w, config := o.writer, o.config
+
+	// Check that nothing needs every entry up front.
+	if features := config.streamIncompatible(); len(features) > 0 {
+		return &StreamIncompatibleError{Features: features}
+	}
+
+	// The timezone that dates are displayed and compared in.
+	location, err := config.location()
+	if err != nil {
+		return err
+	}
+
+	// Report the progress of the collection.
+	progress := newProgress(config.Progress)
+	defer progress.done()
+	progress.report("Listing pull requests for %s", tagName)
+
+	// List the pull requests to include.
+	listing, err := listPullRequests(ctx, tagName, o, location)
+	if err != nil {
+		return err
+	}
+	latestRef := listing.latestRef
+
+	// Compile the category rules, and the filter expression.
+	categoriser, err := newCategoriser(config.Categories)
+	if err != nil {
+		return err
+	}
+	filter, err := newEntryFilter(config.Filter)
+	if err != nil {
+		return err
+	}
+
+	notes := releaseNotes{
+		TagName:          tagName,
+		Date:             getTagDate(ctx, o.cmd, tagName),
+		ReleaseCandidate: listing.releaseCandidate,
+		PreviousRef:      latestRef,
+		Candidates:       listing.candidates,
+	}
+
+	// Output the release candidates collapsed into the release.
+	renderMarkdownCandidates(w, notes.Candidates)
+
+	// Output the security section first, as when rendered in full.
+	if config.Security.Advisories {
+		progress.report("Fetching security advisories")
+		ctx, span := tracer.Start(ctx, "security advisories")
+		until, _ := releasePublishedAt(ctx, o, tagName)
+		notes.Advisories, err = getSecurityAdvisories(ctx, o.cmd, latestRef.PublishedAt, until)
+		endSpan(span, err)
+		if err != nil {
+			return err
+		}
+		normaliseReleaseNotes(&notes, location)
+		renderMarkdownSecurity(w, notes)
+		if err := flush(w); err != nil {
+			return err
+		}
+	}
+
+	// Output each entry as it is fetched, without holding on to it, besides
+	// its upgrade notes.
+	var (
+		upgrades []upgradeNote
+		ref      string
+	)
+	if config.AbsoluteLinks {
+		ref = linksRef(ctx, o, tagName)
+	}
+	for pullRequest, err := range fetchPullRequests(ctx, o, listing, categoriser, filter, progress) {
+		if err != nil {
+			return err
+		}
+		pullRequest.MergedAt = pullRequest.MergedAt.In(location)
+		if notes, body := splitUpgradeNotes(pullRequest.Body); notes != "" {
+			pullRequest.Body = body
+			upgrades = append(upgrades, upgradeNote{
+				PullRequest: gitPullRequest{Number: pullRequest.Number, Title: pullRequest.Title, URL: pullRequest.URL},
+				Notes:       notes,
+			})
+		}
+		if config.AbsoluteLinks {
+			pullRequest.Body = absoluteBody(ctx, o.cmd, pullRequest, ref)
+		}
+		if config.Autolinks {
+			pullRequest.Body = autolinkBody(ctx, o.cmd, pullRequest)
+		}
+		renderMarkdownEntry(w, pullRequest, 1, config)
+		if err := flush(w); err != nil {
+			return err
+		}
+	}
+
+	// Output the upgrade notes, which can only follow the entries they were
+	// found in.
+	renderMarkdownUpgrades(w, upgrades)
+
+	// Get the component-level differences between the SBOMs of the releases.
+	if config.SBOM.enabled() {
+		progress.report("Comparing SBOMs")
+		ctx, span := tracer.Start(ctx, "sbom diff")
+		notes.SBOMDiff, err = getSBOMDiff(ctx, o.cmd, config.SBOM, latestRef.TagName, tagName)
+		endSpan(span, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Get the submodules whose commit changed between the releases.
+	if config.Submodules.Enabled {
+		progress.report("Fetching submodule changes")
+		ctx, span := tracer.Start(ctx, "submodule changes")
+		notes.Submodules, err = getSubmoduleChanges(ctx, o.cmd, config.Submodules, latestRef.TagName, tagName)
+		endSpan(span, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Get the provenance of the CI run generating the release notes.
+	if config.Provenance {
+		if provenance, ok := getProvenance(); ok {
+			notes.Provenance = &provenance
+		} else {
+			log.Warn("Provenance is only available when running in GitHub Actions or GitLab CI")
+		}
+	}
+
+	// Output the sections following the entries, in the same order as when
+	// rendered in full.
+	renderMarkdownFooterSections(w, notes, config)
+	return flush(w)
+}
+
+// flush flushes the provided writer, if it buffers its output.
+func flush(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package lorekeeper
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStreamIncompatible(t *testing.T) {
+	tests := []struct {
+		feature string
+		config  Config
+	}{
+		{feature: "format", config: Config{Format: FormatJSON}},
+		{feature: "template", config: Config{Template: "notes.tmpl"}},
+		{feature: "categories", config: Config{Categories: []CategoryConfig{{Title: "Features"}}}},
+		{feature: "generateNotes", config: Config{GenerateNotes: GenerateNotesConfig{Enabled: true}}},
+		{feature: "groupByScope", config: Config{GroupByScope: true}},
+		{feature: "stacks", config: Config{Stacks: StacksConfig{Enabled: true}}},
+		{feature: "fixups", config: Config{Fixups: FixupsConfig{Labels: []string{"follow-up"}}}},
+		{feature: "thanks", config: Config{Thanks: ThanksConfig{Enabled: true}}},
+		{feature: "header", config: Config{Header: BlockConfig{Text: "Hello"}}},
+		{feature: "footer", config: Config{Footer: BlockConfig{Text: "Bye"}}},
+		{feature: "install", config: Config{Install: InstallConfig{Go: "github.com/riftspire/lorekeeper"}}},
+		{feature: "sort", config: Config{Sort: SortConfig{By: SortByTitle}}},
+		{feature: "pagination", config: Config{Pagination: PaginationConfig{Mode: PaginationDetails}}},
+		{feature: "lint", config: Config{Lint: LintConfig{Enabled: true}}},
+		{feature: "vendored", config: Config{Vendored: []VendoredConfig{{Path: "third_party/foo"}}}},
+		{feature: "security.dependencyCVEs", config: Config{Security: SecurityConfig{DependencyCVEs: true}}},
+		{feature: "publish", config: Config{Publish: PublishConfig{Enabled: true}}},
+		{feature: "goreleaser", config: Config{GoReleaser: GoReleaserConfig{Enabled: true}}},
+		{feature: "fragments", config: Config{Fragments: FragmentsConfig{Format: FragmentsChangesets}}},
+		{feature: "sign.output", config: Config{Sign: SignConfig{Output: "notes.md.asc"}}},
+		{feature: "site", config: Config{Site: SiteConfig{Dir: "site"}}},
+		{feature: "docs", config: Config{Docs: DocsConfig{Dir: "docs"}}},
+		{feature: "notify", config: Config{Notify: NotifyConfig{Slack: []string{"https://hooks.slack.com/services/x"}}}},
+		{feature: "diff", config: Config{Diff: true}},
+		{feature: "check", config: Config{Check: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.feature, func(t *testing.T) {
+			if got := tt.config.streamIncompatible(); !slices.Equal(got, []string{tt.feature}) {
+				t.Errorf("streamIncompatible() = %v, want [%s]", got, tt.feature)
+			}
+		})
+	}
+}
+
+func TestStreamCompatible(t *testing.T) {
+	// The features that are streamed, or handled once the entries have been
+	// output.
+	config := Config{
+		Format:        FormatMarkdown,
+		AbsoluteLinks: true,
+		Autolinks:     true,
+		LinkedIssues:  true,
+		Security:      SecurityConfig{Advisories: true},
+		SBOM:          SBOMConfig{Go: true},
+		Submodules:    SubmodulesConfig{Enabled: true},
+		Provenance:    true,
+	}
+	if got := config.streamIncompatible(); len(got) != 0 {
+		t.Errorf("streamIncompatible() = %v, want none", got)
+	}
+}
@@ -0,0 +1,211 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SubmodulesConfig determines the content of the submodules section of the
+// release notes.
+type SubmodulesConfig struct {
+	// Enabled lists the submodules whose commit changed between the releases.
+	Enabled bool `yaml:"enabled"`
+
+	// Recurse lists the commits made to each changed submodule between the
+	// releases. The submodules must be checked out.
+	Recurse bool `yaml:"recurse"`
+}
+
+// submoduleChange represents the change to a submodule's commit between two
+// refs. Added and removed submodules have an empty previous or current commit.
+type submoduleChange struct {
+	Path           string
+	URL            string
+	PreviousCommit string
+	CurrentCommit  string
+	Commits        []submoduleCommit
+}
+
+// submoduleCommit represents a commit made to a submodule.
+type submoduleCommit struct {
+	SHA     string
+	Subject string
+}
+
+// compareURL returns the URL comparing the previous and current commit of the
+// submodule, if it is hosted on GitHub.
+func (c submoduleChange) compareURL() (string, bool) {
+	repoURL, ok := githubRepoURL(c.URL)
+	if !ok || c.PreviousCommit == "" || c.CurrentCommit == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", repoURL, c.PreviousCommit, c.CurrentCommit), true
+}
+
+// githubRepoURL returns the HTTPS URL of the repository for the provided
+// GitHub remote URL.
+func githubRepoURL(remote string) (string, bool) {
+	repository, ok := parseRemoteURL(remote)
+	if !ok || repository.Host != defaultForgeHost {
+		return "", false
+	}
+	return repository.URL(), true
+}
+
+// getSubmoduleChanges returns the submodules whose commit changed between the
+// provided refs, ordered by path.
+func getSubmoduleChanges(ctx context.Context, cmd commander, config SubmodulesConfig, previousRef, currentRef string) ([]submoduleChange, error) {
+	if previousRef == "" {
+		return nil, &SubmodulesError{Err: fmt.Errorf("no previous release to compare submodules against")}
+	}
+
+	previous, err := submoduleCommits(ctx, cmd, previousRef)
+	if err != nil {
+		return nil, err
+	}
+	current, err := submoduleCommits(ctx, cmd, currentRef)
+	if err != nil {
+		return nil, err
+	}
+	urls := submoduleURLs(ctx, cmd, currentRef)
+
+	var changes []submoduleChange
+	for path, commit := range current {
+		if previous[path] != commit {
+			changes = append(changes, submoduleChange{
+				Path:           path,
+				URL:            urls[path],
+				PreviousCommit: previous[path],
+				CurrentCommit:  commit,
+			})
+		}
+	}
+	for path, commit := range previous {
+		if _, exists := current[path]; !exists {
+			changes = append(changes, submoduleChange{
+				Path:           path,
+				URL:            submoduleURLs(ctx, cmd, previousRef)[path],
+				PreviousCommit: commit,
+			})
+		}
+	}
+
+	// Order the changes by path, as map iteration order is random.
+	slices.SortFunc(changes, func(a, b submoduleChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	// List the commits made to each submodule between the releases.
+	if config.Recurse {
+		for idx, change := range changes {
+			if change.PreviousCommit == "" || change.CurrentCommit == "" {
+				continue
+			}
+			changes[idx].Commits, err = submoduleLog(ctx, cmd, change)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// submoduleCommits returns the commit of each submodule at the provided ref,
+// keyed by path.
+func submoduleCommits(ctx context.Context, cmd commander, ref string) (map[string]string, error) {
+	// List the tree at the ref. Submodules have the mode 160000.
+	tree, err := cmd.run(ctx, "git", "ls-tree", "-r", "--full-tree", ref)
+	if err != nil {
+		return nil, &SubmodulesError{Ref: ref, Err: err}
+	}
+
+	commits := map[string]string{}
+	for line := range strings.SplitSeq(tree, "\n") {
+		// Each line is `<mode> <type> <object>\t<path>`.
+		meta, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) == 3 && fields[0] == "160000" {
+			commits[path] = fields[2]
+		}
+	}
+
+	return commits, nil
+}
+
+// submoduleURLs returns the URL of each submodule declared in the
+// `.gitmodules` file at the provided ref, keyed by path.
+func submoduleURLs(ctx context.Context, cmd commander, ref string) map[string]string {
+	urls := map[string]string{}
+
+	// Read the submodule declarations. There may be none, so any error is
+	// treated as no declarations.
+	declarations, err := cmd.run(ctx, "git",
+		"config", "--blob", ref+":.gitmodules",
+		"--get-regexp", `^submodule\..*\.(path|url)$`,
+	)
+	if err != nil {
+		return urls
+	}
+
+	// Each line is `submodule.<name>.<path|url> <value>`.
+	var (
+		paths = map[string]string{}
+		names = map[string]string{}
+	)
+	for line := range strings.SplitSeq(declarations, "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		name, field, ok := strings.Cut(strings.TrimPrefix(key, "submodule."), ".")
+		if !ok {
+			continue
+		}
+		if field == "path" {
+			paths[name] = value
+		} else {
+			names[name] = value
+		}
+	}
+	for name, path := range paths {
+		urls[path] = names[name]
+	}
+
+	return urls
+}
+
+// submoduleLog returns the commits made to the submodule between its previous
+// and current commit, from newest to oldest.
+func submoduleLog(ctx context.Context, cmd commander, change submoduleChange) ([]submoduleCommit, error) {
+	log, err := cmd.run(ctx, "git",
+		"-C", worktreePath(ctx, cmd, change.Path),
+		"log", "--format=%h%x09%s", change.PreviousCommit+".."+change.CurrentCommit,
+	)
+	if err != nil {
+		return nil, &SubmodulesError{Ref: change.Path, Err: err}
+	}
+
+	var commits []submoduleCommit
+	for line := range strings.SplitSeq(strings.TrimSpace(log), "\n") {
+		sha, subject, ok := strings.Cut(line, "\t")
+		if ok {
+			commits = append(commits, submoduleCommit{SHA: sha, Subject: subject})
+		}
+	}
+
+	return commits, nil
+}
+
+// shortSHA returns the abbreviated form of the provided commit SHA.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
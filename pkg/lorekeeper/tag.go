@@ -0,0 +1,101 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// TagConfig determines how tags are created by CreateTag.
+type TagConfig struct {
+	// Sign creates a signed tag, using the signing key configured in git
+	// (`user.signingKey`, and `gpg.format` for SSH keys).
+	Sign bool `yaml:"sign"`
+
+	// Remote is the remote that the tag is pushed to. Defaults to the remote
+	// set with WithRemote, i.e - "origin".
+	Remote string `yaml:"remote"`
+
+	// SkipPush creates the tag without pushing it.
+	SkipPush bool `yaml:"skipPush"`
+}
+
+// remote returns the configured remote, or the fallback if it has not been
+// configured.
+func (c TagConfig) remote(fallback string) string {
+	if c.Remote == "" {
+		return fallback
+	}
+	return c.Remote
+}
+
+var errTagExists = errors.New("the tag already exists")
+
+// CreateTag creates an annotated tag whose message is the generated markdown
+// release notes, then pushes it to the configured remote.
+//
+// The message is kept verbatim, so that markdown headings are not stripped as
+// comments, and the release notes can be read back in the tag mode.
+func CreateTag(ctx context.Context, tagName string, opts ...Option) error {
+	o := newOptions(opts)
+	config := o.config
+
+	// Check the tag doesn't already exist.
+	if _, err := o.cmd.run(ctx, "git", "rev-parse", "--quiet", "--verify", "refs/tags/"+tagName); err == nil {
+		return &TagError{TagName: tagName, Err: errTagExists}
+	}
+
+	// Generate the release notes for the tag.
+	notes, err := collectReleaseNotes(ctx, tagName, o)
+	if err != nil {
+		return err
+	}
+
+	var message strings.Builder
+	if err := renderLintedMarkdown(&message, notes, config); err != nil {
+		return err
+	}
+
+	// Write the message to a temporary file, to pass to git.
+	file, err := os.CreateTemp("", "lorekeeper-tag-*.md")
+	if err != nil {
+		return &TagError{TagName: tagName, Err: err}
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(message.String()); err != nil {
+		file.Close()
+		return &TagError{TagName: tagName, Err: err}
+	}
+	if err := file.Close(); err != nil {
+		return &TagError{TagName: tagName, Err: err}
+	}
+
+	// Create the annotated, or signed, tag.
+	kind := "--annotate"
+	if config.Tag.Sign {
+		kind = "--sign"
+	}
+	if _, err := o.cmd.run(ctx, "git", "tag", kind, "--cleanup=verbatim", "--file", file.Name(), tagName); err != nil {
+		return &TagError{TagName: tagName, Err: err}
+	}
+	log.Info("Created tag", "tag", tagName, "signed", config.Tag.Sign)
+
+	// Push the tag.
+	if config.Tag.SkipPush {
+		return nil
+	}
+	err = retry(ctx, o.cmd.retry, "git push", func() error {
+		_, err := o.cmd.run(ctx, "git", "push", config.Tag.remote(o.remote), "refs/tags/"+tagName)
+		return err
+	})
+	if err != nil {
+		return &TagError{TagName: tagName, Err: err}
+	}
+	log.Info("Pushed tag", "tag", tagName, "remote", config.Tag.remote(o.remote))
+
+	return nil
+}
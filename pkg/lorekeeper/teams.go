@@ -0,0 +1,128 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// teamsMaxTextLength is the maximum combined length of the release notes
+	// text posted to Teams, keeping the card within the 28KB message limit.
+	teamsMaxTextLength = 24000
+
+	// teamsMaxTextBlockLength is the maximum length of each text block of the
+	// card.
+	teamsMaxTextBlockLength = 4000
+)
+
+// TeamsWebhookConfig represents a Microsoft Teams incoming webhook.
+type TeamsWebhookConfig struct {
+	// URL is the incoming webhook URL. Environment variables in the URL are
+	// expanded.
+	URL string `yaml:"url"`
+
+	// Environments is the list of environments that the webhook is posted to
+	// in, as selected by NotifyConfig.Environment. If empty, the webhook is
+	// posted to in every environment.
+	Environments []string `yaml:"environments"`
+}
+
+type teamsTextBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Wrap   bool   `json:"wrap"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+}
+
+type teamsAdaptiveCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []teamsTextBlock `json:"body"`
+	MSTeams map[string]any   `json:"msteams"`
+}
+
+type teamsAttachment struct {
+	ContentType string            `json:"contentType"`
+	Content     teamsAdaptiveCard `json:"content"`
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// teamsWebhookURLs returns the expanded URLs of the provided webhooks that are
+// posted to in the environment.
+func teamsWebhookURLs(webhooks []TeamsWebhookConfig, environment string) []string {
+	var webhookURLs []string
+	for _, webhook := range webhooks {
+		if len(webhook.Environments) == 0 || slices.Contains(webhook.Environments, environment) {
+			webhookURLs = append(webhookURLs, webhook.URL)
+		}
+	}
+	return expandWebhookURLs(webhookURLs)
+}
+
+// NotifyTeams posts the provided markdown release notes for the tag to each of
+// the Microsoft Teams incoming webhook URLs, as an Adaptive Card.
+func NotifyTeams(ctx context.Context, webhookURLs []string, tagName string, markdown string, opts ...Option) error {
+	o := newOptions(opts)
+	return notifyTeams(ctx, o.cmd, webhookURLs, tagName, markdown)
+}
+
+// notifyTeams posts the release notes as described by NotifyTeams, with the
+// provided commander.
+func notifyTeams(ctx context.Context, cmd commander, webhookURLs []string, tagName string, markdown string) error {
+	card := teamsAdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []teamsTextBlock{{
+			Type:   "TextBlock",
+			Text:   fmt.Sprintf("Release %s", tagName),
+			Wrap:   true,
+			Size:   "Large",
+			Weight: "Bolder",
+		}},
+		MSTeams: map[string]any{"width": "Full"},
+	}
+
+	// Text blocks don't support headings or images, so convert them.
+	text := reMarkdownImage.ReplaceAllString(markdown, "$1")
+	text = strings.TrimSpace(reMarkdownHeading.ReplaceAllString(text, "**$1**"))
+
+	// Add the release notes as text blocks, up to the maximum length.
+	var length int
+	for _, chunk := range chunkText(text, teamsMaxTextBlockLength) {
+		if length+len(chunk) > teamsMaxTextLength {
+			card.Body = append(card.Body, teamsTextBlock{
+				Type: "TextBlock",
+				Text: "_The release notes are too long to post in full._",
+				Wrap: true,
+			})
+			break
+		}
+		card.Body = append(card.Body, teamsTextBlock{Type: "TextBlock", Text: chunk, Wrap: true})
+		length += len(chunk)
+	}
+
+	message := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+
+	for _, webhookURL := range webhookURLs {
+		if err := postJSON(ctx, cmd, webhookURL, message, nil); err != nil {
+			return &NotifyError{Destination: "teams", Err: err}
+		}
+	}
+
+	return nil
+}
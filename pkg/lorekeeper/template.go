@@ -0,0 +1,109 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// renderTemplate writes the provided release notes to the writer using the
+// configured markdown template.
+//
+// The template is passed the same structure that is rendered as JSON, i.e -
+// `{{ .TagName }}` and `{{ range .Entries }}`.
+func renderTemplate(w io.Writer, notes releaseNotes, config Config) error {
+	tmpl, err := parseTemplate(config)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(w, newJSONReleaseNotes(notes, config)); err != nil {
+		return &TemplateError{Path: config.Template, Err: err}
+	}
+
+	return nil
+}
+
+// parseTemplate reads and parses the configured markdown template.
+func parseTemplate(config Config) (*template.Template, error) {
+	text, err := os.ReadFile(config.Template)
+	if err != nil {
+		return nil, &TemplateError{Path: config.Template, Err: err}
+	}
+
+	tmpl, err := template.New(filepath.Base(config.Template)).
+		Funcs(templateFuncs(config)).
+		Parse(string(text))
+	if err != nil {
+		return nil, &TemplateError{Path: config.Template, Err: err}
+	}
+
+	return tmpl, nil
+}
+
+// reSlugSeparators matches the runs of characters that are replaced with a
+// hyphen when slugifying.
+var reSlugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// shortSHALength is the length that commit SHAs are abbreviated to.
+const shortSHALength = 7
+
+// templateFuncs returns the functions available to markdown templates, the
+// built-ins followed by those provided with WithTemplateFunc.
+func templateFuncs(config Config) template.FuncMap {
+	funcs := template.FuncMap{
+		// date formats a date with the configured date format.
+		"date": func(date time.Time) string {
+			return date.Format(config.dateLayout())
+		},
+
+		// slugify lowercases the text, replacing each run of non-alphanumeric
+		// characters with a hyphen, i.e - for anchors.
+		"slugify": func(text string) string {
+			return strings.Trim(reSlugSeparators.ReplaceAllString(strings.ToLower(text), "-"), "-")
+		},
+
+		// truncate shortens the text to at most length characters, ending it
+		// with an ellipsis if it was shortened, i.e - `{{ .Title | truncate 50 }}`.
+		"truncate": func(length int, text string) string {
+			runes := []rune(text)
+			if length < 1 || len(runes) <= length {
+				return text
+			}
+			return string(runes[:length-1]) + "…"
+		},
+
+		// prlink returns a markdown link to the pull request of the entry, i.e -
+		// `[#123](https://...)`.
+		"prlink": func(entry Entry) string {
+			if entry.URL == "" {
+				return fmt.Sprintf("#%d", entry.Number)
+			}
+			return fmt.Sprintf("[#%d](%s)", entry.Number, entry.URL)
+		},
+
+		// join joins the items with the separator, i.e -
+		// `{{ .Contributors | join ", " }}`.
+		"join": func(separator string, items []string) string {
+			return strings.Join(items, separator)
+		},
+
+		// shortsha abbreviates the commit SHA.
+		"shortsha": func(sha string) string {
+			if len(sha) <= shortSHALength {
+				return sha
+			}
+			return sha[:shortSHALength]
+		},
+	}
+
+	maps.Copy(funcs, config.templateFuncs)
+
+	return funcs
+}
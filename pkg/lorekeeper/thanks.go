@@ -0,0 +1,132 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/log"
+)
+
+// defaultThanksTemplate is the template of the thank-you paragraph, unless
+// another is configured.
+const defaultThanksTemplate = `Thank you to {{ .Contributors | join ", " }} for contributing to {{ .TagName }}!`
+
+// ThanksConfig determines the thank-you paragraph that closes the release
+// notes.
+type ThanksConfig struct {
+	// Enabled closes the release notes with a paragraph thanking the authors
+	// of the pull requests by handle.
+	Enabled bool `yaml:"enabled"`
+
+	// Template is the text/template of the paragraph, passed the `.TagName`,
+	// and the `.Contributors` handles, i.e - `@jane-doe`, in the order they
+	// first appear. Defaults to "Thank you to {{ .Contributors | join ", " }}
+	// for contributing to {{ .TagName }}!".
+	Template string `yaml:"template"`
+
+	// External only thanks the authors who aren't members of the
+	// organisation that owns the repository.
+	External bool `yaml:"external"`
+}
+
+// thanksData is passed to the template of the thank-you paragraph.
+type thanksData struct {
+	TagName      string
+	Contributors []string
+}
+
+// parse parses the configured, or default, template of the paragraph.
+func (c ThanksConfig) parse(config Config) (*template.Template, error) {
+	text := c.Template
+	if text == "" {
+		text = defaultThanksTemplate
+	}
+	tmpl, err := template.New("thanks").Funcs(templateFuncs(config)).Parse(text)
+	if err != nil {
+		return nil, &TemplateError{Path: "thanks.template", Err: err}
+	}
+	return tmpl, nil
+}
+
+// getThanks returns the thank-you paragraph for the authors of the provided
+// pull requests, or an empty string if there is no one to thank. Logins are
+// only rendered as handles when they are known, i.e - not offline.
+func getThanks(ctx context.Context, cmd commander, tagName string, pullRequests []gitPullRequest, config Config, handles bool) (string, error) {
+	tmpl, err := config.Thanks.parse(config)
+	if err != nil {
+		return "", err
+	}
+
+	// List the members of the organisation once, to leave them out.
+	var members map[string]bool
+	if config.Thanks.External {
+		members = orgMembers(ctx, cmd)
+	}
+
+	// List the authors in the order they first appear, leaving out bots.
+	var (
+		contributors []string
+		seen         = map[string]bool{}
+	)
+	for _, pullRequest := range pullRequests {
+		for _, commit := range pullRequest.Commits {
+			for _, author := range commit.Authors {
+				login := strings.ToLower(author.Login)
+				if login == "" || seen[login] || strings.HasSuffix(login, "[bot]") || matchesLogin(config.Authors.Exclude, login) || matchesLogin(config.Authors.Anonymize, login) {
+					continue
+				}
+				seen[login] = true
+
+				if config.Thanks.External && members[login] {
+					continue
+				}
+				if handles {
+					contributors = append(contributors, "@"+author.Login)
+				} else {
+					contributors = append(contributors, author.Login)
+				}
+			}
+		}
+	}
+	if len(contributors) == 0 {
+		return "", nil
+	}
+
+	var thanks strings.Builder
+	if err := tmpl.Execute(&thanks, thanksData{TagName: tagName, Contributors: contributors}); err != nil {
+		return "", &TemplateError{Path: "thanks.template", Err: err}
+	}
+	return strings.TrimSpace(thanks.String()), nil
+}
+
+// orgMembers returns the lowercased logins of the members of the organisation
+// that owns the repository, and its owner, if known. If the members can't be
+// listed, i.e - the owner is a user, every other login is treated as
+// external.
+func orgMembers(ctx context.Context, cmd commander) map[string]bool {
+	members := map[string]bool{}
+	if repository, ok := cmd.knownRepository(); ok {
+		members[strings.ToLower(repository.Owner)] = true
+	}
+
+	output, err := cmd.runForge(ctx, "gh", "api", "orgs/{owner}/members", "--paginate", "--jq", ".[].login")
+	if err != nil {
+		log.Debug("Treating the authors as external", "err", err)
+		return members
+	}
+	for login := range strings.FieldsSeq(output) {
+		members[strings.ToLower(login)] = true
+	}
+	return members
+}
+
+// renderMarkdownThanks writes the thank-you paragraph to the writer, if any.
+func renderMarkdownThanks(w io.Writer, thanks string) {
+	if thanks == "" {
+		return
+	}
+	fmt.Fprintf(w, "%s\n\n", thanks)
+}
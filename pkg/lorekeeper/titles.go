@@ -0,0 +1,258 @@
+package lorekeeper
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/riftspire/lorekeeper/pkg/conventional"
+)
+
+// TitleRewriteConfig represents a rule rewriting the titles of the entries,
+// i.e - to strip ticket prefixes, or rename internal codenames.
+type TitleRewriteConfig struct {
+	// Pattern is a regular expression matching the part of the title that is
+	// rewritten, i.e - `^[A-Z]+-[0-9]+:?\s*` for a `PROJ-123: ` prefix.
+	Pattern string `yaml:"pattern"`
+
+	// Replace is what each match of the pattern is replaced with, in which
+	// `$1` expands to the first capture group, and so on. Matches are removed
+	// if it is empty.
+	Replace string `yaml:"replace"`
+}
+
+// titleRewrite is a compiled TitleRewriteConfig.
+type titleRewrite struct {
+	rePattern *regexp.Regexp
+	replace   string
+}
+
+// compileTitleRewrites compiles the patterns of the provided rules, in the
+// order they are configured.
+func compileTitleRewrites(rules []TitleRewriteConfig) ([]titleRewrite, error) {
+	var compiled []titleRewrite
+	for _, rule := range rules {
+		rePattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, &TitleRewriteInvalidError{Pattern: rule.Pattern, Err: err}
+		}
+		compiled = append(compiled, titleRewrite{rePattern: rePattern, replace: rule.Replace})
+	}
+	return compiled, nil
+}
+
+// rewriteTitle applies each of the provided rules to the title, in turn. The
+// title is kept as it is if the rules would leave it empty.
+func rewriteTitle(title string, rewrites []titleRewrite) string {
+	rewritten := title
+	for _, rewrite := range rewrites {
+		rewritten = rewrite.rePattern.ReplaceAllString(rewritten, rewrite.replace)
+	}
+	if rewritten = strings.TrimSpace(rewritten); rewritten == "" {
+		return title
+	}
+	return rewritten
+}
+
+// withRewrittenTitle returns the pull request with its title rewritten by the
+// provided rules, as described by rewriteTitle, keeping the original title,
+// i.e - for the GoReleaser filters.
+func (pr gitPullRequest) withRewrittenTitle(rewrites []titleRewrite) gitPullRequest {
+	if title := rewriteTitle(pr.Title, rewrites); title != pr.Title {
+		pr.originalTitle, pr.Title = pr.Title, title
+	}
+	return pr
+}
+
+// StripPrefixesConfig determines whether the conventional commit prefixes of
+// the titles, i.e - `feat(api):`, are stripped from the displayed entries, so
+// that the release notes read naturally when the entries are grouped by their
+// type, i.e - into categories, instead of echoing the commit syntax.
+type StripPrefixesConfig struct {
+	// Enabled strips the type, scope, and breaking change marker of the
+	// conventional commit titles, leaving the description, when the entries
+	// are grouped into categories. The breaking change marker is replaced by
+	// a `**Breaking:**` prefix.
+	Enabled bool `yaml:"enabled"`
+
+	// KeepScope keeps the scope before the description, i.e - `api: add a
+	// new endpoint`, unless the entries are sub-grouped by their scope.
+	KeepScope bool `yaml:"keepScope"`
+
+	// Capitalise capitalises the first letter of the description, i.e - `Add
+	// a new endpoint`.
+	Capitalise bool `yaml:"capitalise"`
+}
+
+// displayTitle returns the title of the pull request as it is displayed in the
+// entries, normalised, and with its conventional commit prefix stripped, if
+// configured, and the entries are grouped by their type, i.e - into
+// categories. Only the description of a conventional commit title is
+// normalised. A stripped breaking change marker is kept as a `**Breaking:**`
+// prefix.
+func (pr gitPullRequest) displayTitle(config Config) string {
+	strip := config.StripPrefixes.Enabled && len(config.Categories) > 0
+	if !strip && !config.NormaliseTitles.Enabled {
+		return pr.Title
+	}
+	title, ok := conventional.Parse(pr.Title, "")
+	if !ok {
+		return config.NormaliseTitles.normalise(pr.Title, false)
+	}
+
+	// Normalise the description, keeping the prefix unless it is stripped.
+	display := config.NormaliseTitles.normalise(title.Description, true)
+	if !strip {
+		return strings.TrimSuffix(strings.TrimSpace(pr.Title), title.Description) + display
+	}
+
+	if config.StripPrefixes.Capitalise {
+		display = capitalise(display)
+	}
+	if config.StripPrefixes.KeepScope && !config.GroupByScope && title.Scope != "" {
+		display = title.Scope + ": " + display
+	}
+	if title.Breaking {
+		display = "**Breaking:** " + display
+	}
+	return display
+}
+
+// TitleMood is the grammatical mood that the leading verbs of the displayed
+// titles are converted to.
+type TitleMood string
+
+const (
+	// TitleMoodImperative converts the leading verbs to the imperative mood,
+	// i.e - `Add`, as in the conventional commits specification.
+	TitleMoodImperative TitleMood = "imperative"
+
+	// TitleMoodPast converts the leading verbs to the past tense, i.e -
+	// `Added`, as in many changelogs.
+	TitleMoodPast TitleMood = "past"
+)
+
+// GetTitleMoods returns the moods that the leading verbs of the displayed
+// titles can be converted to.
+func GetTitleMoods() []TitleMood {
+	return []TitleMood{TitleMoodImperative, TitleMoodPast}
+}
+
+// validate checks the mood.
+func (m TitleMood) validate() error {
+	if m != "" && !slices.Contains(GetTitleMoods(), m) {
+		return &TitleMoodInvalidError{Mood: m}
+	}
+	return nil
+}
+
+// titleVerb represents the forms of a verb that titles commonly start with.
+type titleVerb struct {
+	imperative string
+	third      string
+	past       string
+}
+
+// titleVerbs are the verbs whose forms are converted between the moods. Verbs
+// that aren't listed are left as they are.
+var titleVerbs = []titleVerb{
+	{"add", "adds", "added"},
+	{"allow", "allows", "allowed"},
+	{"avoid", "avoids", "avoided"},
+	{"bump", "bumps", "bumped"},
+	{"change", "changes", "changed"},
+	{"clean", "cleans", "cleaned"},
+	{"correct", "corrects", "corrected"},
+	{"create", "creates", "created"},
+	{"deprecate", "deprecates", "deprecated"},
+	{"disable", "disables", "disabled"},
+	{"document", "documents", "documented"},
+	{"drop", "drops", "dropped"},
+	{"enable", "enables", "enabled"},
+	{"ensure", "ensures", "ensured"},
+	{"expose", "exposes", "exposed"},
+	{"extend", "extends", "extended"},
+	{"fix", "fixes", "fixed"},
+	{"handle", "handles", "handled"},
+	{"implement", "implements", "implemented"},
+	{"improve", "improves", "improved"},
+	{"introduce", "introduces", "introduced"},
+	{"make", "makes", "made"},
+	{"migrate", "migrates", "migrated"},
+	{"move", "moves", "moved"},
+	{"optimise", "optimises", "optimised"},
+	{"optimize", "optimizes", "optimized"},
+	{"prevent", "prevents", "prevented"},
+	{"refactor", "refactors", "refactored"},
+	{"reduce", "reduces", "reduced"},
+	{"remove", "removes", "removed"},
+	{"rename", "renames", "renamed"},
+	{"replace", "replaces", "replaced"},
+	{"restore", "restores", "restored"},
+	{"return", "returns", "returned"},
+	{"revert", "reverts", "reverted"},
+	{"simplify", "simplifies", "simplified"},
+	{"speed", "speeds", "sped"},
+	{"support", "supports", "supported"},
+	{"switch", "switches", "switched"},
+	{"update", "updates", "updated"},
+	{"upgrade", "upgrades", "upgraded"},
+	{"use", "uses", "used"},
+	{"write", "writes", "wrote"},
+}
+
+// NormaliseTitlesConfig determines whether the displayed titles are normalised,
+// so that they read consistently across the entries.
+type NormaliseTitlesConfig struct {
+	// Enabled capitalises the first letter of the titles, and removes their
+	// trailing periods.
+	Enabled bool `yaml:"enabled"`
+
+	// Mood converts the leading verbs of the conventional commit titles to
+	// either "imperative", i.e - `Add`, or "past", i.e - `Added`. The verbs
+	// are left as they are if it is empty.
+	Mood TitleMood `yaml:"mood"`
+}
+
+// normalise returns the provided title normalised as configured. The leading
+// verb is only converted to the configured mood in the description of a
+// conventional commit title, whose phrasing is known to start with a verb.
+func (c NormaliseTitlesConfig) normalise(title string, conventional bool) string {
+	if !c.Enabled {
+		return title
+	}
+
+	// Convert the leading verb to the configured mood.
+	if c.Mood != "" && conventional {
+		word, rest, _ := strings.Cut(title, " ")
+		for _, verb := range titleVerbs {
+			if !slices.Contains([]string{verb.imperative, verb.third, verb.past}, strings.ToLower(word)) {
+				continue
+			}
+			converted := verb.imperative
+			if c.Mood == TitleMoodPast {
+				converted = verb.past
+			}
+			title = strings.TrimSpace(converted + " " + rest)
+			break
+		}
+	}
+
+	// Remove the trailing periods, but not an ellipsis.
+	if strings.HasSuffix(title, ".") && !strings.HasSuffix(title, "..") {
+		title = strings.TrimSpace(strings.TrimSuffix(title, "."))
+	}
+
+	return capitalise(title)
+}
+
+// capitalise returns the provided text with its first letter capitalised.
+func capitalise(text string) string {
+	first, size := utf8.DecodeRuneInString(text)
+	if first == utf8.RuneError {
+		return text
+	}
+	return string(unicode.ToUpper(first)) + text[size:]
+}
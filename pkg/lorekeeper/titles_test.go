@@ -0,0 +1,104 @@
+package lorekeeper
+
+import "testing"
+
+func TestDisplayTitle(t *testing.T) {
+	categories := []CategoryConfig{{Title: "Features"}}
+	tests := []struct {
+		name   string
+		title  string
+		config Config
+		want   string
+	}{
+		{
+			name:   "as it is",
+			title:  "feat(api): add a new endpoint",
+			config: Config{Categories: categories},
+			want:   "feat(api): add a new endpoint",
+		},
+		{
+			name:   "stripped",
+			title:  "feat(api): add a new endpoint",
+			config: Config{Categories: categories, StripPrefixes: StripPrefixesConfig{Enabled: true, Capitalise: true}},
+			want:   "Add a new endpoint",
+		},
+		{
+			name:   "kept scope",
+			title:  "feat(api): add a new endpoint",
+			config: Config{Categories: categories, StripPrefixes: StripPrefixesConfig{Enabled: true, KeepScope: true}},
+			want:   "api: add a new endpoint",
+		},
+		{
+			name:   "breaking",
+			title:  "feat(api)!: remove the old endpoint",
+			config: Config{Categories: categories, StripPrefixes: StripPrefixesConfig{Enabled: true}},
+			want:   "**Breaking:** remove the old endpoint",
+		},
+		{
+			name:   "not grouped by type",
+			title:  "feat(api)!: remove the old endpoint",
+			config: Config{StripPrefixes: StripPrefixesConfig{Enabled: true}},
+			want:   "feat(api)!: remove the old endpoint",
+		},
+		{
+			name:   "normalised",
+			title:  "fix: adds a flag.",
+			config: Config{NormaliseTitles: NormaliseTitlesConfig{Enabled: true, Mood: TitleMoodPast}},
+			want:   "fix: Added a flag",
+		},
+		{
+			name:   "normalised without the mood",
+			title:  "fixes for windows.",
+			config: Config{NormaliseTitles: NormaliseTitlesConfig{Enabled: true, Mood: TitleMoodPast}},
+			want:   "Fixes for windows",
+		},
+		{
+			name:   "not conventional",
+			title:  "Add a new endpoint",
+			config: Config{Categories: categories, StripPrefixes: StripPrefixesConfig{Enabled: true}},
+			want:   "Add a new endpoint",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (gitPullRequest{Title: tt.title}).displayTitle(tt.config); got != tt.want {
+				t.Errorf("displayTitle(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRewrittenTitle(t *testing.T) {
+	rewrites, err := compileTitleRewrites([]TitleRewriteConfig{
+		{Pattern: `^[A-Z]+-[0-9]+:?\s*`},
+		{Pattern: `\bphoenix\b`, Replace: "the new API"},
+	})
+	if err != nil {
+		t.Fatalf("compileTitleRewrites() error = %v", err)
+	}
+	tests := []struct {
+		name         string
+		title        string
+		want         string
+		wantOriginal string
+	}{
+		{name: "rewritten", title: "PROJ-123: expose phoenix", want: "expose the new API", wantOriginal: "PROJ-123: expose phoenix"},
+		{name: "as it is", title: "fix: handle the empty list", want: "fix: handle the empty list"},
+		{name: "left empty", title: "PROJ-123", want: "PROJ-123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (gitPullRequest{Title: tt.title}).withRewrittenTitle(rewrites)
+			if got.Title != tt.want || got.originalTitle != tt.wantOriginal {
+				t.Errorf("withRewrittenTitle(%q) = %q (originally %q), want %q (originally %q)", tt.title, got.Title, got.originalTitle, tt.want, tt.wantOriginal)
+			}
+			if got.unrewrittenTitle() != tt.title {
+				t.Errorf("unrewrittenTitle() = %q, want %q", got.unrewrittenTitle(), tt.title)
+			}
+		})
+	}
+
+	if _, err := compileTitleRewrites([]TitleRewriteConfig{{Pattern: "("}}); err == nil {
+		t.Error("compileTitleRewrites() error = nil, want an invalid pattern")
+	}
+}
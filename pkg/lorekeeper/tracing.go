@@ -0,0 +1,21 @@
+package lorekeeper
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the OpenTelemetry spans of the package, using the global
+// TracerProvider, so that nothing is recorded unless the caller has configured
+// one.
+var tracer = otel.Tracer("github.com/riftspire/lorekeeper/pkg/lorekeeper")
+
+// endSpan ends the provided span, recording the error, if any, on it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,239 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// cronShorthands are the cron expressions of the supported shorthands.
+var cronShorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// maxTrainLookback is the furthest back that the last departure of the train
+// is searched for, long enough to find a departure on the 29th of February.
+const maxTrainLookback = 4 * 366 * 24 * time.Hour
+
+// TrainConfig determines the schedule of the release train, whose release
+// notes are made for everything merged since its last departure, with
+// ModeTrain.
+type TrainConfig struct {
+	// Schedule is the cron expression (minute, hour, day of the month, month,
+	// and day of the week) of the departures of the train, in the configured
+	// timezone, i.e - `0 9 * * 1` for 09:00 every Monday. The `@hourly`,
+	// `@daily`, `@weekly`, `@monthly`, and `@yearly` shorthands can also be
+	// used.
+	Schedule string `yaml:"schedule"`
+}
+
+// cronSchedule is a parsed cron expression, with a bit set per field.
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+
+	// anyDay and anyWeekday record whether the day of the month, and day of
+	// the week, fields are unrestricted, as when both are restricted either
+	// may match.
+	anyDay, anyWeekday bool
+}
+
+// parseCronSchedule parses the provided five field cron expression, or
+// shorthand.
+func parseCronSchedule(expression string) (cronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if shorthand, ok := cronShorthands[expression]; ok {
+		expression = shorthand
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return cronSchedule{}, &TrainScheduleInvalidError{Schedule: expression, Reason: "expected 5 fields"}
+	}
+
+	var schedule cronSchedule
+	for i, field := range []struct {
+		bits     *uint64
+		min, max int
+	}{
+		{&schedule.minutes, 0, 59},
+		{&schedule.hours, 0, 23},
+		{&schedule.days, 1, 31},
+		{&schedule.months, 1, 12},
+		{&schedule.weekdays, 0, 7},
+	} {
+		bits, ok := parseCronField(fields[i], field.min, field.max)
+		if !ok {
+			return cronSchedule{}, &TrainScheduleInvalidError{Schedule: expression, Reason: fmt.Sprintf("invalid field %q", fields[i])}
+		}
+		*field.bits = bits
+	}
+
+	// Sunday is both 0 and 7.
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+	schedule.anyDay = fields[2] == "*"
+	schedule.anyWeekday = fields[4] == "*"
+
+	return schedule, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges, and steps,
+// i.e - `1,15`, `1-5`, or `*/15`, into a bit set of the values between min
+// and max, reporting whether it is valid.
+func parseCronField(field string, min, max int) (uint64, bool) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		// Split off the step.
+		step := 1
+		if value, stepValue, ok := strings.Cut(part, "/"); ok {
+			var err error
+			if step, err = strconv.Atoi(stepValue); err != nil || step < 1 {
+				return 0, false
+			}
+			part = value
+		}
+
+		// Parse the range, or single value.
+		low, high := min, max
+		if part != "*" {
+			lowValue, highValue, isRange := strings.Cut(part, "-")
+			var err error
+			if low, err = strconv.Atoi(lowValue); err != nil {
+				return 0, false
+			}
+			// A single value with a step, i.e - `5/15`, runs to the maximum.
+			high = low
+			if step > 1 {
+				high = max
+			}
+			if isRange {
+				if high, err = strconv.Atoi(highValue); err != nil {
+					return 0, false
+				}
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, false
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, true
+}
+
+// matchesDay reports whether the day of the provided time matches the
+// schedule.
+func (s cronSchedule) matchesDay(t time.Time) bool {
+	if s.months&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	day := s.days&(1<<t.Day()) != 0
+	weekday := s.weekdays&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// previous returns the latest time, at or before the provided time, matching
+// the schedule, or false if there is none within maxTrainLookback.
+func (s cronSchedule) previous(t time.Time) (time.Time, bool) {
+	// The minutes and hours are truncated in the location of the time, as
+	// truncating the absolute time is wrong for the locations whose offset
+	// isn't a whole number of hours, i.e - Asia/Kolkata.
+	earliest := t.Add(-maxTrainLookback)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	for !t.Before(earliest) {
+		// Skip to the end of the previous day, or hour, when they don't
+		// match.
+		switch {
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case s.hours&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+		case s.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// lastDeparture returns the time that the train last departed, at or before
+// the provided time, in the provided timezone.
+func (c TrainConfig) lastDeparture(now time.Time, location *time.Location) (time.Time, error) {
+	if c.Schedule == "" {
+		return time.Time{}, &TrainScheduleInvalidError{Reason: "a schedule is required for the train mode"}
+	}
+	schedule, err := parseCronSchedule(c.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	departure, ok := schedule.previous(now.In(location))
+	if !ok {
+		return time.Time{}, &TrainScheduleInvalidError{Schedule: c.Schedule, Reason: "the train has not departed in the last four years"}
+	}
+	return departure, nil
+}
+
+// validate checks the schedule of the train, if any.
+func (c TrainConfig) validate() error {
+	if c.Schedule == "" {
+		return nil
+	}
+	_, err := parseCronSchedule(c.Schedule)
+	return err
+}
+
+// TrainName returns the default name of the release train whose release notes
+// are made with ModeTrain, from the current date in the configured timezone,
+// i.e - `train-2006-01-02`, for when there is no tag yet.
+func TrainName(config Config) (string, error) {
+	location, err := config.location()
+	if err != nil {
+		return "", err
+	}
+	return "train-" + buildTime().In(location).Format(time.DateOnly), nil
+}
+
+// listTrainPullRequests lists the pull requests merged since the last
+// departure of the release train, regardless of any tags.
+func listTrainPullRequests(ctx context.Context, o options, location *time.Location) (pullRequestListing, error) {
+	departure, err := o.config.Train.lastDeparture(buildTime(), location)
+	if err != nil {
+		return pullRequestListing{}, err
+	}
+	log.Debug("Found the last departure of the train", "departure", departure)
+
+	// The previous reference is the departure, which has no tag.
+	latestRef := gitReference{PublishedAt: departure}
+
+	prList, err := o.provider.MergedPullRequests(ctx, departure.Format(time.RFC3339))
+	if err != nil {
+		return pullRequestListing{}, &OperationError{Op: "list the merged pull requests", Ref: departure.Format(time.RFC3339), Err: err}
+	}
+	prList = listedNumbers(prList)
+	if prList == "" {
+		return pullRequestListing{}, &NoPullRequestsFoundError{Mode: ModeTrain, LatestRef: latestRef}
+	}
+
+	return pullRequestListing{numbers: prList, latestRef: latestRef}, nil
+}
@@ -0,0 +1,132 @@
+package lorekeeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{name: "every minute", expression: "* * * * *"},
+		{name: "weekly", expression: "0 9 * * 1"},
+		{name: "lists, ranges, and steps", expression: "0,30 9-17 */2 1-6/2 1-5"},
+		{name: "single value with a step", expression: "5/15 * * * *"},
+		{name: "sunday as seven", expression: "0 0 * * 7"},
+		{name: "shorthand", expression: "@weekly"},
+		{name: "surrounding whitespace", expression: "  0 9 * * 1  "},
+		{name: "too few fields", expression: "0 9 * *", wantErr: true},
+		{name: "too many fields", expression: "0 9 * * 1 2026", wantErr: true},
+		{name: "minute out of range", expression: "60 * * * *", wantErr: true},
+		{name: "day out of range", expression: "0 0 0 * *", wantErr: true},
+		{name: "weekday out of range", expression: "0 0 * * 8", wantErr: true},
+		{name: "backwards range", expression: "0 17-9 * * *", wantErr: true},
+		{name: "zero step", expression: "*/0 * * * *", wantErr: true},
+		{name: "not a number", expression: "a * * * *", wantErr: true},
+		{name: "unknown shorthand", expression: "@fortnightly", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCronSchedule(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCronSchedule(%q) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+			var invalid *TrainScheduleInvalidError
+			if err != nil && !errors.As(err, &invalid) {
+				t.Errorf("parseCronSchedule(%q) error = %T, want *TrainScheduleInvalidError", tt.expression, err)
+			}
+		})
+	}
+}
+
+func TestCronSchedulePrevious(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("the timezone database is unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		now        time.Time
+		want       time.Time
+	}{
+		{
+			name:       "same minute",
+			expression: "0 9 * * *",
+			now:        time.Date(2026, 10, 17, 9, 0, 30, 0, time.UTC),
+			want:       time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "earlier today",
+			expression: "30 9 * * *",
+			now:        time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "yesterday",
+			expression: "30 9 * * *",
+			now:        time.Date(2026, 10, 17, 9, 29, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekday",
+			expression: "0 9 * * 1",
+			now:        time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "day of the month or weekday",
+			expression: "0 0 15 * 5",
+			now:        time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "leap day",
+			expression: "0 0 29 2 *",
+			now:        time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "half hour offset",
+			expression: "45 9 * * *",
+			now:        time.Date(2026, 10, 17, 10, 0, 0, 0, kolkata),
+			want:       time.Date(2026, 10, 17, 9, 45, 0, 0, kolkata),
+		},
+		{
+			name:       "half hour offset, previous hour",
+			expression: "15 * * * *",
+			now:        time.Date(2026, 10, 17, 10, 0, 0, 0, kolkata),
+			want:       time.Date(2026, 10, 17, 9, 15, 0, 0, kolkata),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCronSchedule(tt.expression)
+			if err != nil {
+				t.Fatalf("parseCronSchedule(%q) error = %v", tt.expression, err)
+			}
+			got, ok := schedule.previous(tt.now)
+			if !ok {
+				t.Fatalf("previous(%v) found no departure", tt.now)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("previous(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedulePreviousNone(t *testing.T) {
+	schedule, err := parseCronSchedule("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("parseCronSchedule error = %v", err)
+	}
+	if got, ok := schedule.previous(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("previous() = %v, want no departure", got)
+	}
+}
@@ -0,0 +1,136 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxBodyLength is the maximum length, in characters, of the body of a
+// GitHub release.
+const defaultMaxBodyLength = 125000
+
+// TruncateConfig determines how the published release notes are truncated
+// when they exceed the maximum length of a release body.
+type TruncateConfig struct {
+	// MaxLength is the maximum length, in characters, of the published release
+	// notes. Defaults to 125000, the maximum length of a GitHub release body.
+	MaxLength int `yaml:"maxLength"`
+
+	// FullNotesURL is the URL of the full release notes, linked to when the
+	// release notes are truncated.
+	FullNotesURL string `yaml:"fullNotesURL"`
+
+	// Gist publishes the full release notes as a gist, linked to when the
+	// release notes are truncated. Ignored if FullNotesURL is set.
+	Gist bool `yaml:"gist"`
+}
+
+// maxLength returns the configured maximum length, or the default if it has
+// not been configured.
+func (c TruncateConfig) maxLength() int {
+	if c.MaxLength <= 0 {
+		return defaultMaxBodyLength
+	}
+	return c.MaxLength
+}
+
+// renderPublishedMarkdown renders the release notes to be published as the
+// body of the release, truncating them at an entry boundary if they exceed the
+// configured maximum length.
+func renderPublishedMarkdown(ctx context.Context, cmd commander, notes releaseNotes, config Config) ([]byte, error) {
+	var full bytes.Buffer
+	if err := renderLintedMarkdown(&full, notes, config); err != nil {
+		return nil, err
+	}
+
+	truncate := config.Publish.Truncate
+	if utf8.RuneCount(full.Bytes()) <= truncate.maxLength() {
+		return full.Bytes(), nil
+	}
+
+	// Link to the full release notes from the truncated release notes.
+	fullNotesURL := truncate.FullNotesURL
+	if fullNotesURL == "" && truncate.Gist {
+		var err error
+		if fullNotesURL, err = createGist(ctx, cmd, notes.TagName, full.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	footer := truncationFooter(len(notes.PullRequests), fullNotesURL)
+
+	// Find the most entries that fit within the maximum length, alongside the
+	// footer.
+	var (
+		truncated     bytes.Buffer
+		renderEntries = func(n int) error {
+			truncated.Reset()
+			partial := notes
+			partial.PullRequests = notes.PullRequests[:n]
+			if err := renderLintedMarkdown(&truncated, partial, config); err != nil {
+				return err
+			}
+			truncated.WriteString(footer)
+			return nil
+		}
+		renderErr error
+	)
+	n := sort.Search(len(notes.PullRequests)+1, func(n int) bool {
+		if err := renderEntries(n); err != nil {
+			renderErr = err
+			return true
+		}
+		return utf8.RuneCount(truncated.Bytes()) > truncate.maxLength()
+	}) - 1
+	if renderErr != nil {
+		return nil, renderErr
+	}
+	if n < 0 {
+		return nil, &BodyTooLongError{MaxLength: truncate.maxLength()}
+	}
+
+	// Render the entries that fit.
+	if err := renderEntries(n); err != nil {
+		return nil, err
+	}
+
+	return truncated.Bytes(), nil
+}
+
+// truncationFooter returns the markdown appended to truncated release notes.
+func truncationFooter(entries int, fullNotesURL string) string {
+	message := fmt.Sprintf("These release notes have been truncated, as the %d entries exceed the maximum length", entries)
+	if fullNotesURL != "" {
+		message += fmt.Sprintf(". See [the full release notes](%s)", fullNotesURL)
+	}
+	return "---\n\n_" + message + "._\n"
+}
+
+// createGist publishes the provided release notes as a secret gist, returning
+// its URL.
+func createGist(ctx context.Context, cmd commander, tagName string, rendered []byte) (string, error) {
+	dir, err := os.MkdirTemp("", packageName+"-gist-")
+	if err != nil {
+		return "", &PublishError{TagName: tagName, Err: err}
+	}
+	defer os.RemoveAll(dir)
+
+	path := fmt.Sprintf("%s/%s-%s.md", dir, packageName, tagName)
+	if err := os.WriteFile(path, rendered, 0o644); err != nil {
+		return "", &PublishError{TagName: tagName, Err: err}
+	}
+
+	// Create the gist. The URL of the gist is output on the last line.
+	//
+	output, err := cmd.runForgeOnce(ctx, "gh", "gist", "create", path)
+	if err != nil {
+		return "", &PublishError{TagName: tagName, Err: err}
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
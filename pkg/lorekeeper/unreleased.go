@@ -0,0 +1,237 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// unreleasedRef is the revision that the unreleased changes are listed up
+	// to, in place of a tag.
+	unreleasedRef = "HEAD"
+
+	// unreleasedHeading is the title of the heading that the unreleased
+	// changes are rendered beneath.
+	unreleasedHeading = "Unreleased"
+
+	// defaultUnreleasedLevel is the level of the unreleased heading, unless
+	// the changelog already has one, i.e - beneath the `# Changelog` title.
+	defaultUnreleasedLevel = 2
+)
+
+// untagged returns a copy of the configuration with the features that need
+// the tag of the release to exist, i.e - the release notes generated by
+// GitHub, thanks, and publishing, disabled.
+func (c Config) untagged() Config {
+	c.GenerateNotes.Enabled = false
+	c.Thanks.Enabled = false
+	c.Publish.Enabled = false
+	return c
+}
+
+// collectUntagged collects the release notes of the changes merged since the
+// latest release, up to the HEAD commit, which hasn't been tagged.
+func collectUntagged(ctx context.Context, o options) (releaseNotes, error) {
+	o.unreleased = true
+	o.config = o.config.untagged()
+	return collectReleaseNotes(ctx, unreleasedRef, o)
+}
+
+// MakeUnreleasedNotes outputs the changes merged since the latest release
+// (release or tag depending on the mode) as markdown, beneath an "Unreleased"
+// heading, i.e - for the unreleased section of a CHANGELOG.md. Only the
+// heading is output if nothing has been merged since.
+func MakeUnreleasedNotes(ctx context.Context, opts ...Option) error {
+	o := newOptions(opts)
+
+	section, err := renderUnreleased(ctx, o, defaultUnreleasedLevel)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(o.writer, section)
+	return err
+}
+
+// WriteUnreleasedNotes replaces the unreleased section of the changelog at the
+// provided path, as output by MakeUnreleasedNotes, at the level of its
+// existing heading. If there is none, the section is added before the first
+// release, or at the end of the changelog, which is created if needed.
+func WriteUnreleasedNotes(ctx context.Context, path string, opts ...Option) error {
+	o := newOptions(opts)
+
+	changelog, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return &ChangelogError{Path: path, Err: err}
+	}
+
+	// Find the existing unreleased section, or where to add it.
+	lines := strings.Split(string(changelog), "\n")
+	start, end, level := findUnreleasedSection(lines)
+
+	section, err := renderUnreleased(ctx, o, level)
+	if err != nil {
+		return err
+	}
+
+	// Replace the section, keeping a blank line either side of it.
+	var updated strings.Builder
+	for _, line := range lines[:start] {
+		updated.WriteString(line + "\n")
+	}
+	if start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		updated.WriteString("\n")
+	}
+	updated.WriteString(section)
+	if rest := strings.Join(lines[end:], "\n"); strings.TrimSpace(rest) != "" {
+		updated.WriteString("\n" + rest)
+	}
+
+	if err := os.WriteFile(path, []byte(updated.String()), 0o644); err != nil {
+		return &ChangelogError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// renderUnreleased renders the changes merged since the latest release as
+// markdown, beneath an unreleased heading of the provided level.
+func renderUnreleased(ctx context.Context, o options, level int) (string, error) {
+	var section bytes.Buffer
+	fmt.Fprintf(&section, "%s %s\n", strings.Repeat("#", level), unreleasedHeading)
+
+	// Collect the changes up to the HEAD commit, of which there may be none.
+	notes, err := collectUntagged(ctx, o)
+	if errors.Is(err, ErrNoPullRequests) {
+		return section.String(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The release notes are nested beneath the unreleased heading, without the
+	// installation commands, header, or footer, as there is no release yet.
+	config := o.config.untagged()
+	config.Install = InstallConfig{}
+	config.Header = BlockConfig{}
+	config.Footer = BlockConfig{}
+
+	var markdown bytes.Buffer
+	if err := FormatMarkdown.render(ctx, o.cmd, &markdown, notes, config); err != nil {
+		return "", err
+	}
+	section.WriteString("\n" + strings.TrimRight(demoteHeadings(markdown.String(), level), "\n") + "\n")
+
+	return section.String(), nil
+}
+
+// findUnreleasedSection returns the lines that the unreleased section of the
+// changelog spans, up to the next heading of the same or a higher level, and
+// the level of its heading. If there is none, the section is empty, before the
+// first heading of the default level, or at the end of the changelog.
+func findUnreleasedSection(lines []string) (int, int, int) {
+	var (
+		start   = -1
+		level   int
+		inFence bool
+	)
+	for idx, line := range lines {
+		if reFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		matches := reHeading.FindStringSubmatch(line)
+		if inFence || matches == nil {
+			continue
+		}
+		title := strings.Trim(strings.TrimSpace(matches[2]), "[]")
+
+		switch {
+		case start == -1 && strings.EqualFold(title, unreleasedHeading):
+			start, level = idx, len(matches[1])
+		case start != -1 && len(matches[1]) <= level:
+			return start, idx, level
+		case start == -1 && len(matches[1]) == defaultUnreleasedLevel:
+			return idx, idx, defaultUnreleasedLevel
+		}
+	}
+
+	if start != -1 {
+		return start, len(lines), level
+	}
+
+	// Add the section at the end, after any trailing blank lines.
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end, end, defaultUnreleasedLevel
+}
+
+// CheckChangelog checks that the unreleased section of the changelog at the
+// provided path already has an entry for each of the changes merged since the
+// latest release, i.e - as a required CI check that the changelog is kept
+// current. An entry is found by the number, i.e - `#123`, or URL of its pull
+// request, or by its title when it has none.
+func CheckChangelog(ctx context.Context, path string, opts ...Option) error {
+	o := newOptions(opts)
+
+	changelog, err := os.ReadFile(path)
+	if err != nil {
+		return &ChangelogError{Path: path, Err: err}
+	}
+
+	// Collect the changes of the pending release, of which there may be none.
+	notes, err := collectUntagged(ctx, o)
+	if errors.Is(err, ErrNoPullRequests) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Look for the entries in the unreleased section only, as those of the
+	// earlier releases may share the numbers, or titles.
+	lines := strings.Split(string(changelog), "\n")
+	start, end, _ := findUnreleasedSection(lines)
+	section := strings.Join(lines[start:end], "\n")
+
+	var missing []string
+	for _, pullRequest := range notes.PullRequests {
+		if !changelogHasEntry(section, pullRequest) {
+			missing = append(missing, entryReference(pullRequest))
+		}
+	}
+	if len(missing) > 0 {
+		return &ChangelogOutdatedError{Path: path, Missing: missing}
+	}
+	return nil
+}
+
+// changelogHasEntry reports whether the changelog has an entry for the pull
+// request, by its number or URL, or by its title when it has no number.
+func changelogHasEntry(changelog string, pullRequest gitPullRequest) bool {
+	if pullRequest.Number == 0 {
+		return strings.Contains(changelog, pullRequest.Title)
+	}
+	if pullRequest.URL != "" && strings.Contains(changelog, pullRequest.URL) {
+		return true
+	}
+	reNumber := regexp.MustCompile(`#` + strconv.Itoa(pullRequest.Number) + `\b`)
+	return reNumber.MatchString(changelog)
+}
+
+// entryReference returns how the entry of the pull request is referred to,
+// i.e - `#123`, or its title when it has no number.
+func entryReference(pullRequest gitPullRequest) string {
+	if pullRequest.Number == 0 {
+		return strconv.Quote(pullRequest.Title)
+	}
+	return "#" + strconv.Itoa(pullRequest.Number)
+}
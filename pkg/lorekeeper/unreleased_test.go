@@ -0,0 +1,107 @@
+package lorekeeper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindUnreleasedSection(t *testing.T) {
+	tests := []struct {
+		name               string
+		changelog          string
+		wantStart, wantEnd int
+		wantLevel          int
+	}{
+		{
+			name:      "before the latest release",
+			changelog: "# Changelog\n\n## Unreleased\n\n- Add a flag\n\n## v1.0.0\n\n- Fix a bug",
+			wantStart: 2, wantEnd: 6, wantLevel: 2,
+		},
+		{
+			name:      "bracketed heading",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n- Add a flag\n\n## [v1.0.0]",
+			wantStart: 2, wantEnd: 6, wantLevel: 2,
+		},
+		{
+			name:      "subsections",
+			changelog: "## Unreleased\n\n### Features\n\n- Add a flag\n\n## v1.0.0",
+			wantStart: 0, wantEnd: 6, wantLevel: 2,
+		},
+		{
+			name:      "last section",
+			changelog: "# Changelog\n\n## Unreleased\n\n- Add a flag",
+			wantStart: 2, wantEnd: 5, wantLevel: 2,
+		},
+		{
+			name:      "missing, before the latest release",
+			changelog: "# Changelog\n\n## v1.0.0\n\n- Fix a bug",
+			wantStart: 2, wantEnd: 2, wantLevel: 2,
+		},
+		{
+			name:      "missing, without releases",
+			changelog: "# Changelog\n\n",
+			wantStart: 1, wantEnd: 1, wantLevel: 2,
+		},
+		{
+			name:      "heading within a code block",
+			changelog: "# Changelog\n\n```md\n## Unreleased\n```\n\n## Unreleased\n\n- Add a flag",
+			wantStart: 6, wantEnd: 9, wantLevel: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, level := findUnreleasedSection(strings.Split(tt.changelog, "\n"))
+			if start != tt.wantStart || end != tt.wantEnd || level != tt.wantLevel {
+				t.Errorf("findUnreleasedSection() = %d, %d, %d, want %d, %d, %d",
+					start, end, level, tt.wantStart, tt.wantEnd, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestChangelogHasEntry(t *testing.T) {
+	tests := []struct {
+		name        string
+		changelog   string
+		pullRequest gitPullRequest
+		want        bool
+	}{
+		{
+			name:        "by number",
+			changelog:   "- Add a flag (#12)",
+			pullRequest: gitPullRequest{Number: 12},
+			want:        true,
+		},
+		{
+			name:        "longer number",
+			changelog:   "- Add a flag (#123)",
+			pullRequest: gitPullRequest{Number: 12},
+			want:        false,
+		},
+		{
+			name:        "by URL",
+			changelog:   "- [Add a flag](https://github.com/riftspire/lorekeeper/pull/12)",
+			pullRequest: gitPullRequest{Number: 12, URL: "https://github.com/riftspire/lorekeeper/pull/12"},
+			want:        true,
+		},
+		{
+			name:        "by title, without a number",
+			changelog:   "- Add a flag",
+			pullRequest: gitPullRequest{Title: "Add a flag"},
+			want:        true,
+		},
+		{
+			name:        "missing",
+			changelog:   "- Fix a bug (#11)",
+			pullRequest: gitPullRequest{Number: 12},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changelogHasEntry(tt.changelog, tt.pullRequest); got != tt.want {
+				t.Errorf("changelogHasEntry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
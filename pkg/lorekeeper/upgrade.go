@@ -0,0 +1,89 @@
+package lorekeeper
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// upgradeNotesHeadings are the titles of the headings, compared
+// case-insensitively, of the upgrade notes in the bodies of pull requests.
+var upgradeNotesHeadings = []string{"upgrade notes", "upgrade note", "upgrading"}
+
+// upgradeNote represents the upgrade notes of a pull request, i.e - the steps
+// needed to migrate to its changes.
+type upgradeNote struct {
+	PullRequest gitPullRequest
+	Notes       string
+}
+
+// extractUpgradeNotes removes the upgrade notes sections, i.e - `## Upgrade
+// Notes`, from the bodies of the provided pull requests, returning them in the
+// order of the pull requests.
+func extractUpgradeNotes(pullRequests []gitPullRequest) []upgradeNote {
+	var upgrades []upgradeNote
+	for i := range pullRequests {
+		notes, body := splitUpgradeNotes(pullRequests[i].Body)
+		if notes == "" {
+			continue
+		}
+		pullRequests[i].Body = body
+		upgrades = append(upgrades, upgradeNote{PullRequest: pullRequests[i], Notes: notes})
+	}
+	return upgrades
+}
+
+// splitUpgradeNotes splits the provided markdown body into the content of its
+// upgrade notes sections, which run until the next heading of the same or a
+// higher level, and the rest of the body.
+func splitUpgradeNotes(body string) (string, string) {
+	var (
+		notes, rest []string
+		level       int
+		inFence     bool
+	)
+	for line := range strings.SplitSeq(body, "\n") {
+		if reFence.MatchString(line) {
+			inFence = !inFence
+		}
+
+		if matches := reHeading.FindStringSubmatch(line); matches != nil && !inFence {
+			title := strings.ToLower(strings.Trim(strings.TrimSpace(matches[2]), "#: "))
+			switch {
+			case slices.Contains(upgradeNotesHeadings, title):
+				level = len(matches[1])
+				continue
+			case level > 0 && len(matches[1]) <= level:
+				level = 0
+			}
+		}
+
+		if level > 0 {
+			notes = append(notes, line)
+		} else {
+			rest = append(rest, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(notes, "\n")), strings.TrimSpace(strings.Join(rest, "\n"))
+}
+
+// renderMarkdownUpgrades writes the upgrading section of the release notes to
+// the writer as markdown, if there is anything to report.
+func renderMarkdownUpgrades(w io.Writer, upgrades []upgradeNote) {
+	if len(upgrades) == 0 {
+		return
+	}
+
+	// Output the upgrading header.
+	fmt.Fprint(w, "# Upgrading\n\n")
+
+	// Output the upgrade notes of each pull request, attributed to it.
+	for _, upgrade := range upgrades {
+		if upgrade.PullRequest.Title == "" {
+			fmt.Fprintf(w, "%s\n\n", upgrade.Notes)
+			continue
+		}
+		fmt.Fprintf(w, "**%s**%s\n\n%s\n\n", upgrade.PullRequest.Title, pullRequestReference(upgrade.PullRequest), upgrade.Notes)
+	}
+}
@@ -0,0 +1,370 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Validate checks the configuration for problems that would prevent the
+// release notes from being generated, returning an error describing each of
+// them.
+func (c Config) Validate() error {
+	var errs []error
+
+	// Check the sort key.
+	if err := sortPullRequests(nil, c.Sort); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the schedule of the release train.
+	if err := c.Train.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the release branch patterns.
+	for _, pattern := range c.ReleaseBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, &ReleaseBranchInvalidError{Pattern: pattern, Err: err})
+		}
+	}
+
+	// Check the handling of the entries of earlier release candidates.
+	if err := c.ReleaseCandidates.Published.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the format of the fragment files.
+	if err := c.Fragments.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the Go module check.
+	if err := c.GoModule.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the branch pattern of the stacks.
+	if err := c.Stacks.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the title pattern of the follow-ups.
+	if err := c.Fixups.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the size thresholds.
+	if err := c.Size.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the thresholds of the notes quality report.
+	if err := c.Report.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the strategy.
+	if err := c.Strategy.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the output and upload formats.
+	for _, format := range append([]Format{c.Format}, c.Publish.Upload...) {
+		if _, ok := c.Plugins.formatPlugin(format); format != "" && !format.builtin() && !ok {
+			errs = append(errs, &FormatInvalidError{Format: format})
+		}
+	}
+
+	// Check the pagination mode.
+	if err := c.Pagination.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check nothing needs every entry up front when streaming.
+	if c.Stream {
+		if features := c.streamIncompatible(); len(features) > 0 {
+			errs = append(errs, &StreamIncompatibleError{Features: features})
+		}
+	}
+
+	// Check the category rules compile.
+	if _, err := newCategoriser(c.Categories); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the mood of the normalised titles.
+	if err := c.NormaliseTitles.Mood.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the title rewrite rules compile.
+	if _, err := compileTitleRewrites(c.TitleRewrites); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the filter expression compiles.
+	if _, err := newEntryFilter(c.Filter); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the signing configuration.
+	if err := c.Sign.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check there are uploaded release notes to attest.
+	if c.Sigstore.Enabled && len(c.Publish.Upload) == 0 {
+		errs = append(errs, &SigstoreError{Err: errors.New("attesting requires the release notes to be uploaded (publish.upload)")})
+	}
+
+	// Check the cache and retry configuration.
+	if err := c.Cache.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Retry.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the timezone.
+	if _, err := c.location(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the template parses.
+	if c.Template != "" {
+		if _, err := parseTemplate(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Check the header and footer parse.
+	if _, err := c.Header.parse("header", c); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := c.Footer.parse("footer", c); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the template of the thank-you paragraph parses.
+	if c.Thanks.Template != "" {
+		if _, err := c.Thanks.parse(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Check the version files to bump.
+	for _, file := range c.Bump.Files {
+		if _, err := file.compile(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Check the format plugins.
+	for _, plugin := range c.Plugins.Formats {
+		if err := plugin.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Check the provider plugin, if any.
+	if len(c.Plugins.Provider) > 0 {
+		if err := validatePluginCommand(c.Plugins.Provider); err != nil {
+			errs = append(errs, &PluginError{Name: "provider", Err: err})
+		}
+	}
+
+	// Check the vendored directories.
+	for _, vendored := range c.Vendored {
+		if vendored.Name == "" || vendored.Path == "" {
+			errs = append(errs, &VendoredInvalidError{Vendored: vendored})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateReleaseCandidateRegex checks that the provided release candidate
+// regex pattern compiles.
+func ValidateReleaseCandidateRegex(releaseCandidateRegex string) error {
+	if _, err := regexp.Compile(releaseCandidateRegex); err != nil {
+		return &ReleaseCandidateRegexInvalidError{Regex: releaseCandidateRegex, Err: err}
+	}
+	return nil
+}
+
+// ValidateInputs checks the provided tag, and the release candidate regex and
+// branch names of the options, before any release notes are collected,
+// returning an error describing what is wrong with each of them.
+func ValidateInputs(ctx context.Context, tagName string, opts ...Option) error {
+	o := newOptions(opts)
+	resolveBranches(ctx, &o)
+	return validateInputs(ctx, tagName, o)
+}
+
+// validateInputs checks the inputs as described by ValidateInputs.
+func validateInputs(ctx context.Context, tagName string, o options) error {
+	var errs []error
+
+	// Check the repository is known, when there is no local clone to detect
+	// it from.
+	if o.apiOnly {
+		if repository, ok := o.cmd.knownRepository(); !ok {
+			errs = append(errs, &InputInvalidError{Input: "repository", Value: repository.String(), Reason: "a repository is required without a local clone"})
+		}
+	}
+
+	// Check the repository path is a repository, bare or not.
+	if o.cmd.repoPath != "" {
+		if _, err := o.cmd.run(ctx, "git", "rev-parse", "--git-dir"); err != nil {
+			errs = append(errs, &InputInvalidError{Input: "repository path", Value: o.cmd.repoPath, Reason: "the path is not a git repository"})
+		}
+	}
+
+	// Check that at least one call to the forge is made at a time.
+	if o.concurrency < 1 {
+		errs = append(errs, &InputInvalidError{Input: "concurrency", Value: strconv.Itoa(o.concurrency), Reason: "at least one call to the forge must be made at a time"})
+	}
+
+	// Check the pagination mode, which may have been set after the
+	// configuration was validated.
+	if err := o.config.Pagination.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the title rewrite rules, which are compiled with the options.
+	if o.titleRewritesErr != nil {
+		errs = append(errs, o.titleRewritesErr)
+	}
+
+	// Check the mood of the titles, which may have been set after the
+	// configuration was validated.
+	if err := o.config.NormaliseTitles.Mood.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check that everything is available offline.
+	if o.offline {
+		if o.apiOnly {
+			errs = append(errs, &InputInvalidError{Input: "repository", Value: o.cmd.repository.String(), Reason: "a local clone is required offline"})
+		}
+		if o.mode != ModeTag && o.mode != ModeTrain {
+			errs = append(errs, &InputInvalidError{Input: "mode", Value: o.mode.Name, Reason: "only the tag and train modes can be used offline"})
+		}
+	}
+
+	// Check the history is complete, unless it will be deepened.
+	if !o.apiOnly && (!o.deepen || o.offline) && isShallow(ctx, o.cmd) {
+		errs = append(errs, &ShallowCloneError{Remote: o.remote})
+	}
+
+	// Check the release candidate regex compiles.
+	if err := ValidateReleaseCandidateRegex(o.releaseCandidateRegex); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the branch names are valid.
+	for _, branch := range []struct{ input, name string }{
+		{"current branch name", o.currentBranchName},
+		{"default branch name", o.defaultBranchName},
+	} {
+		if reason, ok := validBranchName(branch.name); !ok {
+			errs = append(errs, &InputInvalidError{Input: branch.input, Value: branch.name, Reason: reason})
+		}
+	}
+
+	// Check the tag exists, unless it is the name of a release train, which
+	// may not have been tagged yet, or the unreleased changes are listed up to
+	// the HEAD commit.
+	switch {
+	case o.unreleased:
+	case tagName == "":
+		errs = append(errs, &InputInvalidError{Input: "tag", Value: tagName, Reason: "a tag is required"})
+	case o.mode == ModeTrain:
+		location, err := o.config.location()
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if _, err := o.config.Train.lastDeparture(buildTime(), location); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		if !tagExists(ctx, o, tagName) {
+			errs = append(errs, &InputInvalidError{Input: "tag", Value: tagName, Reason: "the tag does not exist, it may need to be fetched"})
+			break
+		}
+
+		// Check the major version of the tag matches the Go module, if
+		// configured.
+		if err := checkGoModule(ctx, o.cmd, tagName, o.config.GoModule, !o.apiOnly); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validBranchName reports whether the provided name is a valid branch name,
+// following the rules of `git check-ref-format --branch`, and the reason why
+// not if it isn't.
+func validBranchName(name string) (string, bool) {
+	switch {
+	case name == "":
+		return "a branch name is required", false
+	case strings.HasPrefix(name, "-"), strings.HasPrefix(name, "/"), strings.HasSuffix(name, "/"):
+		return "a branch name cannot start with a '-' or '/', or end with a '/'", false
+	case strings.HasSuffix(name, "."), strings.HasSuffix(name, ".lock"):
+		return "a branch name cannot end with a '.' or '.lock'", false
+	case strings.Contains(name, ".."), strings.Contains(name, "//"), strings.Contains(name, "@{"):
+		return "a branch name cannot contain '..', '//', or '@{'", false
+	case strings.ContainsAny(name, " ~^:?*[\\"), strings.ContainsFunc(name, unicode.IsControl):
+		return "a branch name cannot contain spaces, control characters, or any of '~^:?*[\\'", false
+	}
+	return "", true
+}
+
+// CheckForge checks that the forge can be reached, and that the credentials
+// used to access it are valid.
+func CheckForge(ctx context.Context, opts ...Option) error {
+	o := newOptions(opts)
+
+	if _, err := o.cmd.runForge(ctx, "gh", "auth", "status"); err != nil {
+		return &ForgeUnavailableError{Err: err}
+	}
+	return nil
+}
+
+// CheckReleaseNotes checks that the release notes for the provided tag can be
+// generated, as described by MakeReleaseNotes, without outputting, publishing,
+// or writing them, or deleting the fragments.
+func CheckReleaseNotes(ctx context.Context, tagName string, opts ...Option) error {
+	o := newOptions(opts)
+	o.writer = io.Discard
+
+	// Never publish, post, or write the release notes, nor delete the
+	// fragments, when checking them.
+	o.config.Publish.Enabled = false
+	o.config.Notify = NotifyConfig{}
+	o.config.Site = SiteConfig{}
+	o.config.Docs = DocsConfig{}
+	o.config.Sign = SignConfig{}
+	o.config.GoReleaser.Enabled = false
+	o.config.Fragments.Delete = false
+	o.config.Diff = false
+	o.config.Check = false
+	if o.config.Pagination.Mode == PaginationParts {
+		o.config.Pagination.Mode = ""
+	}
+
+	if err := makeReleaseNotes(ctx, tagName, o); err != nil {
+		return &OperationError{Op: "generate the release notes", Ref: tagName, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package lorekeeper
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckReleaseNotesSideEffects(t *testing.T) {
+	dir := newTestRepository(t, []testCommit{
+		{subject: "Initial commit", at: "2026-01-01T00:00:00Z"},
+		{
+			subject: "Add a flag (#1)",
+			at:      "2026-01-02T00:00:00Z",
+			files:   map[string]string{"newsfragments/1.feature.md": "Add a flag.\n"},
+			tag:     "v1.0.0",
+		},
+	})
+	goReleaserNotes := filepath.Join(dir, "release-notes.md")
+	partsDir := t.TempDir()
+
+	err := CheckReleaseNotes(context.Background(), "v1.0.0",
+		WithRepoPath(dir),
+		WithOffline(true),
+		WithMode(ModeTag),
+		WithCurrentBranch("main"),
+		WithDefaultBranch("main"),
+		WithConfig(Config{
+			Fragments:  FragmentsConfig{Format: FragmentsTowncrier, Delete: true},
+			GoReleaser: GoReleaserConfig{Enabled: true, Output: goReleaserNotes},
+			Pagination: PaginationConfig{Mode: PaginationParts, Dir: partsDir},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("CheckReleaseNotes() error = %v", err)
+	}
+
+	// The fragments are kept, and no files are written.
+	if _, err := os.Stat(filepath.Join(dir, "newsfragments", "1.feature.md")); err != nil {
+		t.Errorf("CheckReleaseNotes() deleted the fragment: %v", err)
+	}
+	if _, err := os.Stat(goReleaserNotes); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("CheckReleaseNotes() wrote the GoReleaser notes: %v", err)
+	}
+	if parts, _ := os.ReadDir(partsDir); len(parts) > 0 {
+		t.Errorf("CheckReleaseNotes() wrote %d parts", len(parts))
+	}
+}
@@ -0,0 +1,132 @@
+package lorekeeper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// VendoredConfig declares a directory of vendored code, i.e - a git subtree.
+type VendoredConfig struct {
+	// Name is the name of the vendored code, i.e - `libfoo`.
+	Name string `yaml:"name"`
+
+	// Path is the directory containing the vendored code.
+	Path string `yaml:"path"`
+
+	// Upstream is the URL of the upstream repository, used to link to the
+	// upstream commit.
+	Upstream string `yaml:"upstream"`
+}
+
+// contains reports whether the file path is within the vendored directory.
+func (c VendoredConfig) contains(path string) bool {
+	dir := strings.TrimSuffix(c.Path, "/") + "/"
+	return strings.HasPrefix(path, dir)
+}
+
+// vendoredChange represents the changes made to vendored code between two
+// refs.
+type vendoredChange struct {
+	Vendored       VendoredConfig
+	UpstreamCommit string
+	PullRequests   []gitPullRequest
+}
+
+// upstreamCommitURL returns the URL of the upstream commit, if the upstream
+// repository is hosted on GitHub.
+func (c vendoredChange) upstreamCommitURL() (string, bool) {
+	repoURL, ok := githubRepoURL(c.Vendored.Upstream)
+	if !ok || c.UpstreamCommit == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s/commit/%s", repoURL, c.UpstreamCommit), true
+}
+
+// splitVendored separates the pull requests that only touch the files of one
+// of the vendored directories from the rest, returning the remaining pull
+// requests and the changes made to each vendored directory.
+func splitVendored(
+	ctx context.Context,
+	cmd commander,
+	pullRequests []gitPullRequest,
+	vendored []VendoredConfig,
+	previousRef, currentRef string,
+) ([]gitPullRequest, []vendoredChange) {
+	if len(vendored) == 0 {
+		return pullRequests, nil
+	}
+
+	var (
+		remaining []gitPullRequest
+		changes   = make([]vendoredChange, len(vendored))
+	)
+	for idx, config := range vendored {
+		changes[idx].Vendored = config
+	}
+
+	for _, pullRequest := range pullRequests {
+		idx := vendoredIndex(pullRequest, vendored)
+		if idx < 0 {
+			remaining = append(remaining, pullRequest)
+			continue
+		}
+		changes[idx].PullRequests = append(changes[idx].PullRequests, pullRequest)
+	}
+
+	// Keep the vendored directories that changed, with their upstream commit.
+	var changed []vendoredChange
+	for _, change := range changes {
+		if len(change.PullRequests) == 0 {
+			continue
+		}
+		change.UpstreamCommit = subtreeSplitCommit(ctx, cmd, change.Vendored.Path, previousRef, currentRef)
+		changed = append(changed, change)
+	}
+
+	return remaining, changed
+}
+
+// vendoredIndex returns the index of the vendored directory that contains
+// every file touched by the pull request, or -1 if there is none.
+func vendoredIndex(pullRequest gitPullRequest, vendored []VendoredConfig) int {
+	if len(pullRequest.Files) == 0 {
+		return -1
+	}
+
+	for idx, config := range vendored {
+		all := true
+		for _, file := range pullRequest.Files {
+			if !config.contains(file.Path) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// subtreeSplitCommit returns the upstream commit that the vendored directory
+// was most recently updated to between the provided refs, as recorded by the
+// `git-subtree-split` trailer that `git subtree` adds to its commits. It
+// returns an empty string if there is no such commit.
+func subtreeSplitCommit(ctx context.Context, cmd commander, path, previousRef, currentRef string) string {
+	revisions := currentRef
+	if previousRef != "" {
+		revisions = previousRef + ".." + currentRef
+	}
+
+	output, err := cmd.run(ctx, "git",
+		"log", "-n", "1", "--format=%(trailers:key=git-subtree-split,valueonly)",
+		revisions, "--", path,
+	)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(output)
+}
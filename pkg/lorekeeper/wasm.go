@@ -0,0 +1,76 @@
+package lorekeeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// callWASMPlugin runs the WebAssembly (WASI) module at the provided path as a
+// plugin, writing the request for the method to its stdin, and returning the
+// result read from its stdout.
+//
+// The module is sandboxed, with no access to the filesystem, network, or
+// environment variables, and is killed if it outlives the context, or its
+// timeout.
+func callWASMPlugin(ctx context.Context, path string, method string, params map[string]any) (string, error) {
+	request, err := newPluginRequest(method, params)
+	if err != nil {
+		return "", &PluginError{Name: path, Err: err}
+	}
+
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return "", &PluginError{Name: path, Err: err}
+	}
+
+	// Kill the plugin if it outlives the context, or its timeout.
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	// Create the runtime, with only the WASI imports available.
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	defer runtime.Close(ctx)
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	module, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		return "", &PluginError{Name: path, Err: err}
+	}
+
+	// Run the module, capturing its stderr so that it never reaches stdout.
+	var stdout, stderr bytes.Buffer
+	moduleConfig := wazero.NewModuleConfig().
+		WithArgs(path).
+		WithStdin(bytes.NewReader(request)).
+		WithStdout(&stdout).
+		WithStderr(&stderr)
+
+	start := time.Now()
+	if _, err := runtime.InstantiateModule(ctx, module, moduleConfig); err != nil {
+		// A module exiting successfully, i.e - with `proc_exit(0)`, is not a
+		// failure.
+		var exitErr *sys.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 0 {
+			log.Debug("Plugin failed", "plugin", path, "method", method, "duration", time.Since(start), "err", err, "stderr", strings.TrimSpace(stderr.String()))
+			return "", &PluginError{Name: path, Err: err}
+		}
+	}
+	log.Debug("Plugin succeeded", "plugin", path, "method", method, "duration", time.Since(start))
+
+	// Read the response.
+	result, err := readPluginResponse(stdout.Bytes())
+	if err != nil {
+		return "", &PluginError{Name: path, Err: err}
+	}
+
+	return result, nil
+}
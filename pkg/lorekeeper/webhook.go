@@ -0,0 +1,71 @@
+package lorekeeper
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+)
+
+const (
+	// webhookSignatureHeader is the header containing the HMAC-SHA256
+	// signature of the webhook payload.
+	webhookSignatureHeader = "X-Lorekeeper-Signature-256"
+
+	// webhookEventHeader is the header identifying the webhook event.
+	webhookEventHeader = "X-Lorekeeper-Event"
+
+	// webhookEvent is the webhook event sent when release notes are generated.
+	webhookEvent = "release-notes"
+)
+
+// WebhookConfig represents an endpoint that the structured JSON release notes
+// are posted to.
+type WebhookConfig struct {
+	// URL is the endpoint URL. Environment variables in the URL are expanded.
+	URL string `yaml:"url"`
+
+	// Secret is the key used to sign the payload, with the HMAC-SHA256
+	// signature sent in the `X-Lorekeeper-Signature-256` header as
+	// `sha256=<hex>`. Environment variables in the secret are expanded. If
+	// empty, the payload is not signed.
+	Secret string `yaml:"secret"`
+}
+
+// NotifyWebhooks posts the provided structured JSON release notes to each of
+// the webhooks, signing the payload with the secret of each.
+func NotifyWebhooks(ctx context.Context, webhooks []WebhookConfig, notesJSON []byte, opts ...Option) error {
+	o := newOptions(opts)
+	return notifyWebhooks(ctx, o.cmd, webhooks, notesJSON)
+}
+
+// notifyWebhooks posts the release notes as described by NotifyWebhooks, with the
+// provided commander.
+func notifyWebhooks(ctx context.Context, cmd commander, webhooks []WebhookConfig, notesJSON []byte) error {
+	for _, webhook := range webhooks {
+		webhookURL := os.ExpandEnv(webhook.URL)
+		if webhookURL == "" {
+			continue
+		}
+
+		headers := map[string]string{webhookEventHeader: webhookEvent}
+		if secret := os.ExpandEnv(webhook.Secret); secret != "" {
+			headers[webhookSignatureHeader] = signWebhookPayload(secret, notesJSON)
+		}
+
+		if err := postBody(ctx, cmd, webhookURL, notesJSON, headers); err != nil {
+			return &NotifyError{Destination: "webhook", Err: err}
+		}
+	}
+
+	return nil
+}
+
+// signWebhookPayload returns the HMAC-SHA256 signature of the payload with the
+// secret, in the form `sha256=<hex>`.
+func signWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}